@@ -3,7 +3,11 @@ package main
 import (
 	"context"
 	"eth-graph-api/internal/block"
+	"eth-graph-api/internal/graph"
+	"eth-graph-api/internal/meta"
+	"eth-graph-api/internal/pool"
 	"eth-graph-api/internal/token"
+	"eth-graph-api/pkg/blocktime"
 	"github.com/shurcooL/graphql"
 	"net/http"
 )
@@ -23,18 +27,38 @@ func (r *RealGraphClient) Query(ctx context.Context, q interface{}, variables ma
 }
 
 // initHandlers initializes and returns the HTTP handlers for the API
-// given a particular API version and GraphQL client. It also sets
-// up the repositories, services, and handlers for the token and block
-// resources
-func initHandlers(apiVersion string, graphClient *graphql.Client) http.Handler {
+// given a particular API version and GraphQL clients. It also sets
+// up the repositories, services, and handlers for the token, pool, block, graph and meta
+// resources, and starts refreshing the token graph and the indexing status in the background.
+// The blocks client is nil when no blocks subgraph is configured
+func initHandlers(apiVersion string, graphClient *graphql.Client, blocksClient *graphql.Client) http.Handler {
+
+	metaRepo := meta.NewMetaRepository(&RealGraphClient{Client: graphClient})
+	metaService := meta.NewMetaService(metaRepo)
+	metaHandler := &meta.Handler{MetaService: metaService}
+	go metaService.Run(context.Background())
 
 	tokenRepo := token.NewTokenRepository(&RealGraphClient{Client: graphClient})
 	tokenService := token.NewTokenService(tokenRepo)
 	tokenHandler := &token.Handler{TokenService: tokenService}
 
 	blockRepo := block.NewBlockRepository(&RealGraphClient{Client: graphClient})
-	blockService := block.NewBlockService(blockRepo)
+	var blocksGraphClient blocktime.GraphClient
+	if blocksClient != nil {
+		blocksGraphClient = &RealGraphClient{Client: blocksClient}
+	}
+	resolver := blocktime.NewResolver(blocksGraphClient, &RealGraphClient{Client: graphClient})
+	blockService := block.NewBlockService(blockRepo, resolver, metaService)
 	blockHandler := &block.Handler{BlockService: blockService}
 
-	return Routes(apiVersion, *tokenHandler, *blockHandler)
+	poolRepo := pool.NewPoolRepository(&RealGraphClient{Client: graphClient})
+	poolService := pool.NewPoolService(poolRepo)
+	poolHandler := &pool.Handler{PoolService: poolService}
+
+	graphRepo := graph.NewGraphRepository(&RealGraphClient{Client: graphClient})
+	graphService := graph.NewGraphService(graphRepo, metaService)
+	graphHandler := &graph.Handler{GraphService: graphService}
+	go graphService.Run(context.Background(), graph.RefreshInterval)
+
+	return Routes(apiVersion, *tokenHandler, *poolHandler, *blockHandler, *graphHandler, *metaHandler)
 }
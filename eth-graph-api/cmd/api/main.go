@@ -1,7 +1,10 @@
 package main
 
 import (
+	"eth-graph-api/internal/graph"
 	"eth-graph-api/pkg/logger"
+	"eth-graph-api/pkg/paging"
+	"eth-graph-api/pkg/validator"
 	"fmt"
 	"github.com/joho/godotenv"
 	"github.com/shurcooL/graphql"
@@ -9,6 +12,8 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 )
 
 func main() {
@@ -31,18 +36,43 @@ func main() {
 		logger.Error("error occurred", "error", err)
 	}
 
-	// Get the port number and Graph API URL from environment variables.
+	// Get the port number and Graph API URLs from environment variables.
+	// The blocks subgraph is optional, block times fall back to the swaps of the Graph API without it.
 	port := os.Getenv("PORT")
 	graphApi := os.Getenv("GRAPH_API")
+	blocksGraphApi := os.Getenv("BLOCKS_GRAPH_API")
 	apiVersion := fmt.Sprintf("/%s", os.Getenv("API_VERSION"))
 
+	// Set the ceiling on the number of entities gathered from the subgraph by a single request,
+	// keeping the default when it is not configured.
+	if maxItems, err := strconv.Atoi(os.Getenv("MAX_ITEMS")); err == nil && maxItems > 0 {
+		paging.MaxItems = maxItems
+	}
+
+	// Set how often the token graph is rebuilt (in seconds), the TVL (in USD) pools need to be part of it,
+	// and the TVL pools need to be searched for price-inconsistency cycles, keeping the defaults when they are not configured.
+	if seconds, err := strconv.Atoi(os.Getenv("GRAPH_REFRESH_INTERVAL")); err == nil && seconds > 0 {
+		graph.RefreshInterval = time.Duration(seconds) * time.Second
+	}
+	if minTvlUSD := os.Getenv("GRAPH_MIN_TVL_USD"); validator.IsValidAmount(minTvlUSD) {
+		graph.MinTvlUSD = minTvlUSD
+	}
+	if minTvlUSD := os.Getenv("CYCLES_MIN_TVL_USD"); validator.IsValidAmount(minTvlUSD) {
+		graph.CyclesMinTvlUSD = minTvlUSD
+	}
+
 	// Create a new GraphQL client using the API URL.
 	graphqlClient := graphql.NewClient(graphApi, nil)
 
-	// Initialize the API handlers using the API version and GraphQL client
+	var blocksClient *graphql.Client
+	if blocksGraphApi != "" {
+		blocksClient = graphql.NewClient(blocksGraphApi, nil)
+	}
+
+	// Initialize the API handlers using the API version and GraphQL clients
 	// The initHandlers function is presumed to set up the routing and handlers for the API,
 	// and set up the HTTP server using the specified port and handler.
-	router := initHandlers(apiVersion, graphqlClient)
+	router := initHandlers(apiVersion, graphqlClient, blocksClient)
 	srv := &http.Server{
 		Addr:    fmt.Sprintf(":%s", port),
 		Handler: router,
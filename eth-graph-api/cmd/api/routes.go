@@ -2,6 +2,9 @@ package main
 
 import (
 	"eth-graph-api/internal/block"
+	"eth-graph-api/internal/graph"
+	"eth-graph-api/internal/meta"
+	"eth-graph-api/internal/pool"
 	"eth-graph-api/internal/token"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -15,8 +18,10 @@ import (
 
 // Routes initializes and returns an http.Handler that handles routing for the API.
 // It uses the given apiVersion to prefix the API routes and uses the provided
-// tokenHandler and blockHandler to handle requests to token- and block-related routes, respectively
-func Routes(apiVersion string, tokenHandler token.Handler, blockHandler block.Handler) http.Handler {
+// tokenHandler, poolHandler and blockHandler to handle requests to token-, pool- and block-related routes, respectively,
+// and graphHandler to handle requests over the token graph.
+// The metaHandler serves the indexing status of the subgraph and relates the data routes to it
+func Routes(apiVersion string, tokenHandler token.Handler, poolHandler pool.Handler, blockHandler block.Handler, graphHandler graph.Handler, metaHandler meta.Handler) http.Handler {
 	limiter := rate.NewLimiter(rate.Every(1*time.Second), 1)
 	mux := chi.NewRouter()
 
@@ -24,7 +29,7 @@ func Routes(apiVersion string, tokenHandler token.Handler, blockHandler block.Ha
 		AllowedOrigins:   []string{"https://*", "http://*"},
 		AllowedMethods:   []string{"GET", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", meta.IndexedBlockHeader, "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -34,13 +39,50 @@ func Routes(apiVersion string, tokenHandler token.Handler, blockHandler block.Ha
 	mux.Use(rateLimit(limiter))
 
 	mux.Route(apiVersion, func(mux chi.Router) {
-		mux.Route("/tokens/{token}", func(mux chi.Router) {
-			mux.Get("/pools", tokenHandler.GetPoolsByTokenHandler)
-			mux.Get("/volume", tokenHandler.GetVolumeHandler)
+		mux.Get("/meta", metaHandler.GetMetaHandler)
+
+		// The data routes tell the indexed block and refuse blocks and times the subgraph has not indexed yet
+		mux.Group(func(mux chi.Router) {
+			mux.Use(metaHandler.Indexed)
+
+			mux.Route("/tokens/{token}", func(mux chi.Router) {
+				mux.Get("/", tokenHandler.GetTokenHandler)
+				mux.Get("/pools", tokenHandler.GetPoolsByTokenHandler)
+				mux.Get("/volume", tokenHandler.GetVolumeHandler)
+				mux.Get("/volume/daily", tokenHandler.GetDailyVolumeHandler)
+				mux.Get("/volume/hourly", tokenHandler.GetHourlyVolumeHandler)
+				mux.Get("/candles", tokenHandler.GetCandlesHandler)
+			})
+			mux.Route("/pools/{pool}", func(mux chi.Router) {
+				mux.Get("/", poolHandler.GetPoolHandler)
+				mux.Get("/history", poolHandler.GetHistoryHandler)
+				mux.Get("/candles", poolHandler.GetCandlesHandler)
+				mux.Get("/ticks", poolHandler.GetTicksHandler)
+				mux.Get("/quote", poolHandler.GetQuoteHandler)
+				mux.Get("/swaps", blockHandler.GetSwapsByPoolHandler)
+				mux.Get("/mints", blockHandler.GetMintsByPoolHandler)
+				mux.Get("/burns", blockHandler.GetBurnsByPoolHandler)
+				mux.Get("/collects", blockHandler.GetCollectsByPoolHandler)
+			})
+			mux.Get("/swaps", blockHandler.GetSwapsByTimeRangeHandler)
+			mux.Get("/blocks/swaps", blockHandler.GetSwapsByBlockRangeHandler)
+			mux.Get("/blocks/by-time/{ts}", blockHandler.GetBlockByTimeHandler)
+			mux.Route("/blocks/{block}", func(mux chi.Router) {
+				mux.Get("/", blockHandler.GetBlockSummaryHandler)
+				mux.Get("/time", blockHandler.GetBlockTimeHandler)
+				mux.Get("/swaps", blockHandler.GetSwapsByBlockHandler)
+				mux.Get("/swaps/tokens", blockHandler.GetSwappedTokensByBlockHandler)
+				mux.Get("/mints", blockHandler.GetMintsByBlockHandler)
+				mux.Get("/burns", blockHandler.GetBurnsByBlockHandler)
+			})
 		})
-		mux.Route("/blocks/{block}", func(mux chi.Router) {
-			mux.Get("/swaps", blockHandler.GetSwapsByBlockHandler)
-			mux.Get("/swaps/tokens", blockHandler.GetSwappedTokensByBlockHandler)
+
+		// The graph routes answer from the token graph snapshot and tell the block it was built as of
+		mux.Get("/routes", graphHandler.GetRoutesHandler)
+		mux.Route("/analytics", func(mux chi.Router) {
+			mux.Get("/cycles", graphHandler.GetCyclesHandler)
+			mux.Get("/graph/centrality", graphHandler.GetCentralityHandler)
+			mux.Get("/graph/components", graphHandler.GetComponentsHandler)
 		})
 	})
 
@@ -1,15 +1,190 @@
 package block
 
+import "eth-graph-api/pkg/paging"
+
 type Token struct {
-	Symbol string `json:"symbol"`
+	ID       string `json:"id" graphql:"id"`
+	Symbol   string `json:"symbol" graphql:"symbol"`
+	Decimals string `json:"decimals" graphql:"decimals"`
 }
 
 type Pool struct {
-	Token0 Token `json:"token0"`
-	Token1 Token `json:"token1"`
+	ID     string `json:"id" graphql:"id"`
+	Token0 Token  `json:"token0" graphql:"token0"`
+	Token1 Token  `json:"token1" graphql:"token1"`
+}
+
+// Transaction is the transaction a swap was made in, identified by its hash
+type Transaction struct {
+	ID          string `json:"id" graphql:"id"`
+	BlockNumber string `json:"blockNumber" graphql:"blockNumber"`
 }
 
+// Swap is a single swap event, with the amounts of the pool's tokens going in (positive)
+// and out (negative), and the state of the pool right after it
 type Swap struct {
-	ID   string `json:"id"`
-	Pool Pool   `json:"pool"`
+	ID           string      `json:"id" graphql:"id"`
+	Transaction  Transaction `json:"transaction" graphql:"transaction"`
+	Timestamp    string      `json:"timestamp" graphql:"timestamp"`
+	LogIndex     string      `json:"logIndex" graphql:"logIndex"`
+	Pool         Pool        `json:"pool" graphql:"pool"`
+	Sender       string      `json:"sender" graphql:"sender"`
+	Recipient    string      `json:"recipient" graphql:"recipient"`
+	Origin       string      `json:"origin" graphql:"origin"`
+	Amount0      string      `json:"amount0" graphql:"amount0"`
+	Amount1      string      `json:"amount1" graphql:"amount1"`
+	AmountUSD    string      `json:"amountUSD" graphql:"amountUSD"`
+	SqrtPriceX96 string      `json:"sqrtPriceX96" graphql:"sqrtPriceX96"`
+	Tick         string      `json:"tick" graphql:"tick"`
+}
+
+// Mint is a single event of liquidity being added to a pool within the tick range [TickLower, TickUpper]
+type Mint struct {
+	ID          string      `json:"id" graphql:"id"`
+	Transaction Transaction `json:"transaction" graphql:"transaction"`
+	Timestamp   string      `json:"timestamp" graphql:"timestamp"`
+	LogIndex    string      `json:"logIndex" graphql:"logIndex"`
+	Pool        Pool        `json:"pool" graphql:"pool"`
+	Owner       string      `json:"owner" graphql:"owner"`
+	Sender      string      `json:"sender" graphql:"sender"`
+	Origin      string      `json:"origin" graphql:"origin"`
+	Amount      string      `json:"amount" graphql:"amount"`
+	Amount0     string      `json:"amount0" graphql:"amount0"`
+	Amount1     string      `json:"amount1" graphql:"amount1"`
+	AmountUSD   string      `json:"amountUSD" graphql:"amountUSD"`
+	TickLower   string      `json:"tickLower" graphql:"tickLower"`
+	TickUpper   string      `json:"tickUpper" graphql:"tickUpper"`
+}
+
+// Burn is a single event of liquidity being removed from a pool within the tick range [TickLower, TickUpper]
+type Burn struct {
+	ID          string      `json:"id" graphql:"id"`
+	Transaction Transaction `json:"transaction" graphql:"transaction"`
+	Timestamp   string      `json:"timestamp" graphql:"timestamp"`
+	LogIndex    string      `json:"logIndex" graphql:"logIndex"`
+	Pool        Pool        `json:"pool" graphql:"pool"`
+	Owner       string      `json:"owner" graphql:"owner"`
+	Origin      string      `json:"origin" graphql:"origin"`
+	Amount      string      `json:"amount" graphql:"amount"`
+	Amount0     string      `json:"amount0" graphql:"amount0"`
+	Amount1     string      `json:"amount1" graphql:"amount1"`
+	AmountUSD   string      `json:"amountUSD" graphql:"amountUSD"`
+	TickLower   string      `json:"tickLower" graphql:"tickLower"`
+	TickUpper   string      `json:"tickUpper" graphql:"tickUpper"`
+}
+
+// Collect is a single event of fees and burned liquidity being withdrawn from a pool
+type Collect struct {
+	ID          string      `json:"id" graphql:"id"`
+	Transaction Transaction `json:"transaction" graphql:"transaction"`
+	Timestamp   string      `json:"timestamp" graphql:"timestamp"`
+	LogIndex    string      `json:"logIndex" graphql:"logIndex"`
+	Pool        Pool        `json:"pool" graphql:"pool"`
+	Owner       string      `json:"owner" graphql:"owner"`
+	Amount0     string      `json:"amount0" graphql:"amount0"`
+	Amount1     string      `json:"amount1" graphql:"amount1"`
+	AmountUSD   string      `json:"amountUSD" graphql:"amountUSD"`
+	TickLower   string      `json:"tickLower" graphql:"tickLower"`
+	TickUpper   string      `json:"tickUpper" graphql:"tickUpper"`
+}
+
+// BlockSummary tells what happened in a block on Uniswap: the number of events of each kind,
+// the USD volume of the swaps, how many distinct pools and tokens the events touched,
+// and the pools with the highest swap volume
+type BlockSummary struct {
+	Block        int          `json:"block"`
+	Timestamp    string       `json:"timestamp"`
+	SwapCount    int          `json:"swapCount"`
+	MintCount    int          `json:"mintCount"`
+	BurnCount    int          `json:"burnCount"`
+	CollectCount int          `json:"collectCount"`
+	VolumeUSD    string       `json:"volumeUSD"`
+	PoolCount    int          `json:"poolCount"`
+	TokenCount   int          `json:"tokenCount"`
+	TopPools     []PoolVolume `json:"topPools"`
+}
+
+// PoolVolume is the swap volume of a pool within a block
+type PoolVolume struct {
+	ID        string `json:"id"`
+	Token0    Token  `json:"token0"`
+	Token1    Token  `json:"token1"`
+	SwapCount int    `json:"swapCount"`
+	VolumeUSD string `json:"volumeUSD"`
+}
+
+// SwappedToken aggregates the swaps of a token within a block: how many swaps it took part in,
+// the gross amounts of the token that went into pools (sold) and came out of them (bought),
+// and the USD volume of these swaps
+type SwappedToken struct {
+	ID        string `json:"id"`
+	Symbol    string `json:"symbol"`
+	Decimals  string `json:"decimals"`
+	SwapCount int    `json:"swapCount"`
+	AmountIn  string `json:"amountIn"`
+	AmountOut string `json:"amountOut"`
+	VolumeUSD string `json:"volumeUSD"`
+}
+
+// BlockPage is a page of the events or tokens of a block along with the number of the block,
+// which tells what a block identifier such as "latest" was resolved to
+type BlockPage[T any] struct {
+	Block int `json:"block"`
+	paging.Page[T]
+}
+
+// SwapsParams holds the raw query parameters of a swaps-by-block request
+type SwapsParams struct {
+	Limit  string
+	Cursor string
+	AsOf   string
+}
+
+// RangeParams holds the raw query parameters of a swaps-over-a-range request,
+// where the range is given either in block numbers or in timestamps
+type RangeParams struct {
+	From   string
+	To     string
+	Pool   string
+	Token  string
+	Limit  string
+	Cursor string
+}
+
+// SwapsFilter narrows down the swaps of a range of blocks (FromBlock to ToBlock)
+// or, if no blocks are set, of a time range (From to To), both inclusive,
+// optionally to the swaps of a pool and of pools containing a token
+type SwapsFilter struct {
+	FromBlock int
+	ToBlock   int
+	From      int64
+	To        int64
+	Pool      string
+	Token     string
+	Limit     int
+	Cursor    string
+}
+
+// EventsParams holds the raw query parameters of a request for the events (swaps, mints, burns, collects)
+// of a block or of a pool, where the optional time range (From to To) only applies to the events of a pool,
+// and the minimum USD value (MinUSD) and the sender only to the swaps of a pool
+type EventsParams struct {
+	From   string
+	To     string
+	MinUSD string
+	Sender string
+	Limit  string
+	Cursor string
+}
+
+// EventsFilter narrows down the events of a pool to a time range (From to To), both inclusive,
+// unless they are zero, and its swaps to the ones worth at least MinUSD and made by Sender, unless they are empty
+type EventsFilter struct {
+	Pool   string
+	From   int64
+	To     int64
+	MinUSD string
+	Sender string
+	Limit  int
+	Cursor string
 }
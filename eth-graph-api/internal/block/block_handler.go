@@ -3,9 +3,14 @@ package block
 import (
 	"context"
 	"errors"
+	"eth-graph-api/pkg/blocktime"
 	jh "eth-graph-api/pkg/json_helper"
+	"eth-graph-api/pkg/paging"
 	"github.com/go-chi/chi/v5"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,11 +21,11 @@ type Handler struct {
 // GetSwapsByBlockHandler is an HTTP handler function that retrieves
 // and sends swaps data for a specific block in response to HTTP requests.
 // - It extracts the "block" URL parameter and validates it.
-// - Parses and validates query parameters.
+// - Parses query parameters: limit (or first), cursor and asOf.
 // - Sets a 5-second timeout for the request context.
 // - Fetches swaps data for the block using BlockService.
 // - Handles potential errors and timeouts.
-// - Writes the fetched data as JSON to the HTTP response.
+// - Writes the fetched page as JSON to the HTTP response, with a "Link" header to the next page of the same block.
 func (h *Handler) GetSwapsByBlockHandler(w http.ResponseWriter, r *http.Request) {
 
 	block := chi.URLParam(r, "block")
@@ -33,15 +38,22 @@ func (h *Handler) GetSwapsByBlockHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	queryParams := r.URL.Query()
-	firstStr := queryParams.Get("first")
-	if firstStr == "" {
-		firstStr = "5"
+	params := SwapsParams{
+		Limit:  queryParams.Get("limit"),
+		Cursor: queryParams.Get("cursor"),
+		AsOf:   queryParams.Get("asOf"),
+	}
+	if params.Limit == "" {
+		params.Limit = queryParams.Get("first")
+	}
+	if params.Limit == "" {
+		params.Limit = "5"
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	swaps, err := h.BlockService.GetSwapsByBlockService(ctx, block, firstStr)
+	swaps, err := h.BlockService.GetSwapsByBlockService(ctx, block, params)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
@@ -57,7 +69,7 @@ func (h *Handler) GetSwapsByBlockHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = jh.WriteJSON(w, http.StatusOK, swaps)
+	err = jh.WriteJSON(w, http.StatusOK, swaps, blockLinkHeader(r, swaps.Block, swaps.NextCursor))
 	if err != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
@@ -66,11 +78,11 @@ func (h *Handler) GetSwapsByBlockHandler(w http.ResponseWriter, r *http.Request)
 // GetSwappedTokensByBlockHandler is an HTTP handler function that retrieves
 // and sends swapped tokens data for a specific block in response to HTTP requests.
 // - It extracts the "block" URL parameter and validates it.
-// - Parses and validates query parameters.
+// - Parses query parameters: limit (or first), cursor and asOf.
 // - Sets a 5-second timeout for the request context.
 // - Fetches swapped tokens data for the block using BlockService.
 // - Handles potential errors and timeouts.
-// - Writes the fetched data as JSON to the HTTP response.
+// - Writes the fetched page as JSON to the HTTP response, with a "Link" header to the next page of the same block.
 func (h *Handler) GetSwappedTokensByBlockHandler(w http.ResponseWriter, r *http.Request) {
 
 	block := chi.URLParam(r, "block")
@@ -83,15 +95,22 @@ func (h *Handler) GetSwappedTokensByBlockHandler(w http.ResponseWriter, r *http.
 	}
 
 	queryParams := r.URL.Query()
-	firstStr := queryParams.Get("first")
-	if firstStr == "" {
-		firstStr = "5"
+	params := SwapsParams{
+		Limit:  queryParams.Get("limit"),
+		Cursor: queryParams.Get("cursor"),
+		AsOf:   queryParams.Get("asOf"),
+	}
+	if params.Limit == "" {
+		params.Limit = queryParams.Get("first")
+	}
+	if params.Limit == "" {
+		params.Limit = "5"
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	tokens, err := h.BlockService.GetSwappedTokensByBlockService(ctx, block, firstStr)
+	tokens, err := h.BlockService.GetSwappedTokensByBlockService(ctx, block, params)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
@@ -107,7 +126,377 @@ func (h *Handler) GetSwappedTokensByBlockHandler(w http.ResponseWriter, r *http.
 		return
 	}
 
-	err = jh.WriteJSON(w, http.StatusOK, tokens)
+	err = jh.WriteJSON(w, http.StatusOK, tokens, blockLinkHeader(r, tokens.Block, tokens.NextCursor))
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// blockLinkHeader builds the "Link" header to the next page of a block's collection, see paging.LinkHeader.
+// The link pins the "block" URL parameter of the request, which may be an identifier such as "latest",
+// to its `resolved` number, so the next page is taken from the same block even if a new one has been indexed
+// in the meantime. The path is rebuilt from the route pattern, filling in the URL parameters.
+func blockLinkHeader(r *http.Request, resolved int, next string) http.Header {
+	pinned := r.Clone(r.Context())
+
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		path := rctx.RoutePattern()
+		for i, key := range rctx.URLParams.Keys {
+			value := rctx.URLParams.Values[i]
+			if key == "block" {
+				value = strconv.Itoa(resolved)
+			}
+			path = strings.Replace(path, "{"+key+"}", value, 1)
+		}
+		pinned.URL.Path = path
+		pinned.URL.RawPath = ""
+	}
+
+	return paging.LinkHeader(pinned, next)
+}
+
+// GetBlockSummaryHandler is an HTTP handler function that retrieves
+// and sends the summary of a specific block in response to HTTP requests.
+// - It extracts the "block" URL parameter and validates it.
+// - Sets a 5-second timeout for the request context.
+// - Builds the summary of the block using BlockService.
+// - Handles potential errors and timeouts.
+// - Writes the summary as JSON to the HTTP response.
+func (h *Handler) GetBlockSummaryHandler(w http.ResponseWriter, r *http.Request) {
+
+	block := chi.URLParam(r, "block")
+	if block == "" {
+		err := jh.ErrorJSON(w, errors.New("block cannot be empty"), http.StatusBadRequest)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	summary, err := h.BlockService.GetBlockSummaryService(ctx, block)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else {
+			err = jh.ErrorJSON(w, err, http.StatusBadRequest)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	err = jh.WriteJSON(w, http.StatusOK, summary)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// GetBlockByTimeHandler is an HTTP handler function that resolves
+// and sends the latest block mined at or before a UNIX timestamp in response to HTTP requests.
+// - It extracts the "ts" URL parameter and validates it.
+// - Sets a 5-second timeout for the request context.
+// - Resolves the block using BlockService.
+// - Handles potential errors and timeouts.
+// - Writes the block as JSON to the HTTP response.
+func (h *Handler) GetBlockByTimeHandler(w http.ResponseWriter, r *http.Request) {
+
+	ts := chi.URLParam(r, "ts")
+	if ts == "" {
+		err := jh.ErrorJSON(w, errors.New("timestamp cannot be empty"), http.StatusBadRequest)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	block, err := h.BlockService.GetBlockByTimeService(ctx, ts)
+	writeBlockTime(w, block, err)
+}
+
+// GetBlockTimeHandler is an HTTP handler function that resolves
+// and sends the UNIX timestamp of a specific block in response to HTTP requests.
+// - It extracts the "block" URL parameter and validates it.
+// - Sets a 5-second timeout for the request context.
+// - Resolves the timestamp using BlockService.
+// - Handles potential errors and timeouts.
+// - Writes the block as JSON to the HTTP response.
+func (h *Handler) GetBlockTimeHandler(w http.ResponseWriter, r *http.Request) {
+
+	block := chi.URLParam(r, "block")
+	if block == "" {
+		err := jh.ErrorJSON(w, errors.New("block cannot be empty"), http.StatusBadRequest)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	resolved, err := h.BlockService.GetBlockTimeService(ctx, block)
+	writeBlockTime(w, resolved, err)
+}
+
+// writeBlockTime writes a resolved block, or the error that occurred while resolving it, as JSON to the HTTP response.
+func writeBlockTime(w http.ResponseWriter, block *blocktime.Block, err error) {
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else if errors.Is(err, blocktime.ErrNotFound) {
+			err = jh.ErrorJSON(w, err, http.StatusNotFound)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else {
+			err = jh.ErrorJSON(w, err, http.StatusBadRequest)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	err = jh.WriteJSON(w, http.StatusOK, block)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// GetSwapsByBlockRangeHandler is an HTTP handler function that retrieves
+// and sends swaps data over a range of blocks in response to HTTP requests.
+// - It parses query parameters: fromBlock and toBlock, the optional pool and token filters,
+// limit (or first) and cursor.
+// - Sets a 5-second timeout for the request context.
+// - Fetches swaps data for the range using BlockService.
+// - Handles potential errors and timeouts.
+// - Writes the fetched page as JSON to the HTTP response, with a "Link" header to the next page.
+func (h *Handler) GetSwapsByBlockRangeHandler(w http.ResponseWriter, r *http.Request) {
+
+	queryParams := r.URL.Query()
+	params := rangeParams(queryParams)
+	params.From = queryParams.Get("fromBlock")
+	params.To = queryParams.Get("toBlock")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	swaps, err := h.BlockService.GetSwapsByBlockRangeService(ctx, params)
+	writePage(w, r, swaps, err)
+}
+
+// GetSwapsByTimeRangeHandler is an HTTP handler function that retrieves
+// and sends swaps data over a time range in response to HTTP requests.
+// - It parses query parameters: from and to timestamps, the optional pool and token filters,
+// limit (or first) and cursor.
+// - Sets a 5-second timeout for the request context.
+// - Fetches swaps data for the range using BlockService.
+// - Handles potential errors and timeouts.
+// - Writes the fetched page as JSON to the HTTP response, with a "Link" header to the next page.
+func (h *Handler) GetSwapsByTimeRangeHandler(w http.ResponseWriter, r *http.Request) {
+
+	queryParams := r.URL.Query()
+	params := rangeParams(queryParams)
+	params.From = queryParams.Get("from")
+	params.To = queryParams.Get("to")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	swaps, err := h.BlockService.GetSwapsByTimeRangeService(ctx, params)
+	writePage(w, r, swaps, err)
+}
+
+// rangeParams extracts the query parameters shared by the swaps-over-a-range requests,
+// defaulting the limit to 5.
+func rangeParams(queryParams url.Values) RangeParams {
+	params := RangeParams{
+		Pool:   queryParams.Get("pool"),
+		Token:  queryParams.Get("token"),
+		Limit:  queryParams.Get("limit"),
+		Cursor: queryParams.Get("cursor"),
+	}
+	if params.Limit == "" {
+		params.Limit = queryParams.Get("first")
+	}
+	if params.Limit == "" {
+		params.Limit = "5"
+	}
+
+	return params
+}
+
+// writePage writes a page of swaps or events, or the error that occurred while retrieving it, as JSON to the HTTP response.
+func writePage[T any](w http.ResponseWriter, r *http.Request, page *paging.Page[T], err error) {
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else {
+			err = jh.ErrorJSON(w, err, http.StatusBadRequest)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	err = jh.WriteJSON(w, http.StatusOK, page, paging.LinkHeader(r, page.NextCursor))
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// GetMintsByBlockHandler is an HTTP handler function that retrieves
+// and sends the mints made in a specific block in response to HTTP requests.
+// - It extracts the "block" URL parameter and validates it.
+// - Parses query parameters: limit (or first) and cursor.
+// - Sets a 5-second timeout for the request context.
+// - Fetches the mints of the block using BlockService.
+// - Handles potential errors and timeouts.
+// - Writes the fetched page as JSON to the HTTP response, with a "Link" header to the next page of the same block.
+func (h *Handler) GetMintsByBlockHandler(w http.ResponseWriter, r *http.Request) {
+
+	block := chi.URLParam(r, "block")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	mints, err := h.BlockService.GetMintsByBlockService(ctx, block, eventsParams(r.URL.Query()))
+	writeBlockPage(w, r, mints, err)
+}
+
+// GetBurnsByBlockHandler is an HTTP handler function that retrieves
+// and sends the burns made in a specific block in response to HTTP requests.
+// - It extracts the "block" URL parameter and validates it.
+// - Parses query parameters: limit (or first) and cursor.
+// - Sets a 5-second timeout for the request context.
+// - Fetches the burns of the block using BlockService.
+// - Handles potential errors and timeouts.
+// - Writes the fetched page as JSON to the HTTP response, with a "Link" header to the next page of the same block.
+func (h *Handler) GetBurnsByBlockHandler(w http.ResponseWriter, r *http.Request) {
+
+	block := chi.URLParam(r, "block")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	burns, err := h.BlockService.GetBurnsByBlockService(ctx, block, eventsParams(r.URL.Query()))
+	writeBlockPage(w, r, burns, err)
+}
+
+// GetSwapsByPoolHandler is an HTTP handler function that retrieves
+// and sends the swaps of a specific pool in response to HTTP requests.
+// - It extracts the "pool" URL parameter.
+// - Parses query parameters: the optional from and to timestamps, minUSD and sender, limit (or first) and cursor.
+// - Sets a 5-second timeout for the request context.
+// - Fetches the swaps of the pool using BlockService.
+// - Handles potential errors and timeouts.
+// - Writes the fetched page as JSON to the HTTP response, with a "Link" header to the next page.
+func (h *Handler) GetSwapsByPoolHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	swaps, err := h.BlockService.GetSwapsByPoolService(ctx, chi.URLParam(r, "pool"), eventsParams(r.URL.Query()))
+	writePage(w, r, swaps, err)
+}
+
+// GetMintsByPoolHandler is an HTTP handler function that retrieves
+// and sends the mints of a specific pool in response to HTTP requests.
+// - It extracts the "pool" URL parameter.
+// - Parses query parameters: the optional from and to timestamps, limit (or first) and cursor.
+// - Sets a 5-second timeout for the request context.
+// - Fetches the mints of the pool using BlockService.
+// - Handles potential errors and timeouts.
+// - Writes the fetched page as JSON to the HTTP response, with a "Link" header to the next page.
+func (h *Handler) GetMintsByPoolHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	mints, err := h.BlockService.GetMintsByPoolService(ctx, chi.URLParam(r, "pool"), eventsParams(r.URL.Query()))
+	writePage(w, r, mints, err)
+}
+
+// GetBurnsByPoolHandler is an HTTP handler function that retrieves
+// and sends the burns of a specific pool in response to HTTP requests.
+// - It extracts the "pool" URL parameter.
+// - Parses query parameters: the optional from and to timestamps, limit (or first) and cursor.
+// - Sets a 5-second timeout for the request context.
+// - Fetches the burns of the pool using BlockService.
+// - Handles potential errors and timeouts.
+// - Writes the fetched page as JSON to the HTTP response, with a "Link" header to the next page.
+func (h *Handler) GetBurnsByPoolHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	burns, err := h.BlockService.GetBurnsByPoolService(ctx, chi.URLParam(r, "pool"), eventsParams(r.URL.Query()))
+	writePage(w, r, burns, err)
+}
+
+// GetCollectsByPoolHandler is an HTTP handler function that retrieves
+// and sends the collects of a specific pool in response to HTTP requests.
+// - It extracts the "pool" URL parameter.
+// - Parses query parameters: the optional from and to timestamps, limit (or first) and cursor.
+// - Sets a 5-second timeout for the request context.
+// - Fetches the collects of the pool using BlockService.
+// - Handles potential errors and timeouts.
+// - Writes the fetched page as JSON to the HTTP response, with a "Link" header to the next page.
+func (h *Handler) GetCollectsByPoolHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	collects, err := h.BlockService.GetCollectsByPoolService(ctx, chi.URLParam(r, "pool"), eventsParams(r.URL.Query()))
+	writePage(w, r, collects, err)
+}
+
+// eventsParams extracts the query parameters of the liquidity events requests, defaulting the limit to 5.
+func eventsParams(queryParams url.Values) EventsParams {
+	params := EventsParams{
+		From:   queryParams.Get("from"),
+		To:     queryParams.Get("to"),
+		MinUSD: queryParams.Get("minUSD"),
+		Sender: queryParams.Get("sender"),
+		Limit:  queryParams.Get("limit"),
+		Cursor: queryParams.Get("cursor"),
+	}
+	if params.Limit == "" {
+		params.Limit = queryParams.Get("first")
+	}
+	if params.Limit == "" {
+		params.Limit = "5"
+	}
+
+	return params
+}
+
+// writeBlockPage writes a page of a block's collection, or the error that occurred while retrieving it,
+// as JSON to the HTTP response, with a "Link" header to the next page of the same block, see blockLinkHeader.
+func writeBlockPage[T any](w http.ResponseWriter, r *http.Request, page *BlockPage[T], err error) {
+	if err != nil {
+		writePage[T](w, r, nil, err)
+		return
+	}
+
+	err = jh.WriteJSON(w, http.StatusOK, page, blockLinkHeader(r, page.Block, page.NextCursor))
 	if err != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
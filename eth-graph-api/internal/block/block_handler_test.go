@@ -3,6 +3,8 @@ package block
 import (
 	"context"
 	"errors"
+	"eth-graph-api/pkg/blocktime"
+	"eth-graph-api/pkg/paging"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -15,28 +17,83 @@ type MockBlockService struct {
 	mock.Mock
 }
 
-func (m *MockBlockService) GetSwapsByBlockService(ctx context.Context, blockStr string, firstStr string) ([]Swap, error) {
-	args := m.Called(ctx, blockStr, firstStr)
-	return args.Get(0).([]Swap), args.Error(1)
+func (m *MockBlockService) GetSwapsByBlockService(ctx context.Context, blockStr string, params SwapsParams) (*BlockPage[Swap], error) {
+	args := m.Called(ctx, blockStr, params)
+	return args.Get(0).(*BlockPage[Swap]), args.Error(1)
 }
 
-func (m *MockBlockService) GetSwappedTokensByBlockService(ctx context.Context, blockStr string, firstStr string) ([]Token, error) {
-	args := m.Called(ctx, blockStr, firstStr)
-	return args.Get(0).([]Token), args.Error(1)
+func (m *MockBlockService) GetSwappedTokensByBlockService(ctx context.Context, blockStr string, params SwapsParams) (*BlockPage[SwappedToken], error) {
+	args := m.Called(ctx, blockStr, params)
+	return args.Get(0).(*BlockPage[SwappedToken]), args.Error(1)
+}
+
+func (m *MockBlockService) GetSwapsByBlockRangeService(ctx context.Context, params RangeParams) (*paging.Page[Swap], error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(*paging.Page[Swap]), args.Error(1)
+}
+
+func (m *MockBlockService) GetSwapsByTimeRangeService(ctx context.Context, params RangeParams) (*paging.Page[Swap], error) {
+	args := m.Called(ctx, params)
+	return args.Get(0).(*paging.Page[Swap]), args.Error(1)
+}
+
+func (m *MockBlockService) GetBlockSummaryService(ctx context.Context, blockStr string) (*BlockSummary, error) {
+	args := m.Called(ctx, blockStr)
+	return args.Get(0).(*BlockSummary), args.Error(1)
+}
+
+func (m *MockBlockService) GetBlockByTimeService(ctx context.Context, tsStr string) (*blocktime.Block, error) {
+	args := m.Called(ctx, tsStr)
+	return args.Get(0).(*blocktime.Block), args.Error(1)
+}
+
+func (m *MockBlockService) GetBlockTimeService(ctx context.Context, blockStr string) (*blocktime.Block, error) {
+	args := m.Called(ctx, blockStr)
+	return args.Get(0).(*blocktime.Block), args.Error(1)
+}
+
+func (m *MockBlockService) GetMintsByBlockService(ctx context.Context, blockStr string, params EventsParams) (*BlockPage[Mint], error) {
+	args := m.Called(ctx, blockStr, params)
+	return args.Get(0).(*BlockPage[Mint]), args.Error(1)
+}
+
+func (m *MockBlockService) GetBurnsByBlockService(ctx context.Context, blockStr string, params EventsParams) (*BlockPage[Burn], error) {
+	args := m.Called(ctx, blockStr, params)
+	return args.Get(0).(*BlockPage[Burn]), args.Error(1)
+}
+
+func (m *MockBlockService) GetSwapsByPoolService(ctx context.Context, poolStr string, params EventsParams) (*paging.Page[Swap], error) {
+	args := m.Called(ctx, poolStr, params)
+	return args.Get(0).(*paging.Page[Swap]), args.Error(1)
+}
+
+func (m *MockBlockService) GetMintsByPoolService(ctx context.Context, poolStr string, params EventsParams) (*paging.Page[Mint], error) {
+	args := m.Called(ctx, poolStr, params)
+	return args.Get(0).(*paging.Page[Mint]), args.Error(1)
+}
+
+func (m *MockBlockService) GetBurnsByPoolService(ctx context.Context, poolStr string, params EventsParams) (*paging.Page[Burn], error) {
+	args := m.Called(ctx, poolStr, params)
+	return args.Get(0).(*paging.Page[Burn]), args.Error(1)
+}
+
+func (m *MockBlockService) GetCollectsByPoolService(ctx context.Context, poolStr string, params EventsParams) (*paging.Page[Collect], error) {
+	args := m.Called(ctx, poolStr, params)
+	return args.Get(0).(*paging.Page[Collect]), args.Error(1)
 }
 
 func TestGetSwapsByBlockHandler(t *testing.T) {
 	tests := []struct {
 		name           string
 		url            string
-		mockSvcOutput  []Swap
+		mockSvcOutput  *BlockPage[Swap]
 		mockSvcErr     error
 		expectedStatus int
 	}{
 		{
 			name:           "valid request",
 			url:            "/v1/blocks/18319881/swaps?first=5",
-			mockSvcOutput:  []Swap{{ID: "1"}},
+			mockSvcOutput:  &BlockPage[Swap]{Block: 18319881, Page: paging.Page[Swap]{Data: []Swap{{ID: "1"}}}},
 			mockSvcErr:     nil,
 			expectedStatus: http.StatusOK,
 		},
@@ -52,7 +109,7 @@ func TestGetSwapsByBlockHandler(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mockSvc := new(MockBlockService)
-			mockSvc.On("GetSwapsByBlockService", mock.Anything, mock.Anything, mock.Anything).Return(test.mockSvcOutput, test.mockSvcErr).Once()
+			mockSvc.On("GetSwapsByBlockService", mock.Anything, mock.Anything, SwapsParams{Limit: "5"}).Return(test.mockSvcOutput, test.mockSvcErr).Once()
 
 			h := &Handler{
 				BlockService: mockSvc,
@@ -75,18 +132,86 @@ func TestGetSwapsByBlockHandler(t *testing.T) {
 	}
 }
 
+func TestGetSwapsByBlockHandlerNextPage(t *testing.T) {
+	mockSvc := new(MockBlockService)
+	mockSvc.On("GetSwapsByBlockService", mock.Anything, "18319881", SwapsParams{Limit: "1", AsOf: "true"}).
+		Return(&BlockPage[Swap]{Block: 18319881, Page: *paging.NewPage([]Swap{{ID: "1"}}, "1")}, nil).Once()
+
+	h := &Handler{
+		BlockService: mockSvc,
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "/v1/blocks/18319881/swaps?limit=1&asOf=true", nil)
+	assert.NoError(t, err)
+	req.Host = "localhost:3000"
+
+	rr := httptest.NewRecorder()
+	r := chi.NewRouter()
+	r.Get("/v1/blocks/{block}/swaps", h.GetSwapsByBlockHandler)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"next_cursor":"MQ","has_more":true`)
+	assert.Equal(t, `<http://localhost:3000/v1/blocks/18319881/swaps?asOf=true&cursor=MQ&limit=1>; rel="next"`, rr.Header().Get("Link"))
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestBlockLinkHeader(t *testing.T) {
+	// The block identifier also makes a segment of the path before it
+	req, err := http.NewRequest(http.MethodGet, "/1/blocks/1/swaps?limit=1", nil)
+	assert.NoError(t, err)
+	req.Host = "localhost:3000"
+
+	var header http.Header
+	r := chi.NewRouter()
+	r.Route("/1/blocks/{block}", func(r chi.Router) {
+		r.Get("/swaps", func(w http.ResponseWriter, r *http.Request) {
+			header = blockLinkHeader(r, 18319881, "MQ")
+		})
+	})
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, `<http://localhost:3000/1/blocks/18319881/swaps?cursor=MQ&limit=1>; rel="next"`, header.Get("Link"))
+}
+
+func TestGetSwapsByLatestBlockHandlerNextPage(t *testing.T) {
+	mockSvc := new(MockBlockService)
+	mockSvc.On("GetSwapsByBlockService", mock.Anything, "latest-10", SwapsParams{Limit: "1"}).
+		Return(&BlockPage[Swap]{Block: 18319881, Page: *paging.NewPage([]Swap{{ID: "1"}}, "1")}, nil).Once()
+
+	h := &Handler{
+		BlockService: mockSvc,
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "/v1/blocks/latest-10/swaps?limit=1", nil)
+	assert.NoError(t, err)
+	req.Host = "localhost:3000"
+
+	rr := httptest.NewRecorder()
+	r := chi.NewRouter()
+	r.Get("/v1/blocks/{block}/swaps", h.GetSwapsByBlockHandler)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `{"block":18319881,"data":[`)
+	assert.Equal(t, `<http://localhost:3000/v1/blocks/18319881/swaps?cursor=MQ&limit=1>; rel="next"`, rr.Header().Get("Link"))
+
+	mockSvc.AssertExpectations(t)
+}
+
 func TestGetSwappedTokensByBlockHandler(t *testing.T) {
 	tests := []struct {
 		name           string
 		url            string
-		mockSvcOutput  []Token
+		mockSvcOutput  *BlockPage[SwappedToken]
 		mockSvcErr     error
 		expectedStatus int
 	}{
 		{
 			name:           "valid request",
 			url:            "/v1/blocks/18319881/swaps/tokens?first=5",
-			mockSvcOutput:  []Token{{Symbol: "ETH"}},
+			mockSvcOutput:  &BlockPage[SwappedToken]{Block: 18319881, Page: paging.Page[SwappedToken]{Data: []SwappedToken{{Symbol: "ETH"}}}},
 			mockSvcErr:     nil,
 			expectedStatus: http.StatusOK,
 		},
@@ -102,7 +227,7 @@ func TestGetSwappedTokensByBlockHandler(t *testing.T) {
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
 			mockSvc := new(MockBlockService)
-			mockSvc.On("GetSwappedTokensByBlockService", mock.Anything, mock.Anything, mock.Anything).Return(test.mockSvcOutput, test.mockSvcErr).Once()
+			mockSvc.On("GetSwappedTokensByBlockService", mock.Anything, mock.Anything, SwapsParams{Limit: "5"}).Return(test.mockSvcOutput, test.mockSvcErr).Once()
 
 			h := &Handler{
 				BlockService: mockSvc,
@@ -124,3 +249,308 @@ func TestGetSwappedTokensByBlockHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestGetSwapsByRangeHandlers(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		method         string
+		params         RangeParams
+		mockSvcOutput  *paging.Page[Swap]
+		mockSvcErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "block range",
+			url:            "/v1/blocks/swaps?fromBlock=18319800&toBlock=18319881&pool=0xpool&limit=10",
+			method:         "GetSwapsByBlockRangeService",
+			params:         RangeParams{From: "18319800", To: "18319881", Pool: "0xpool", Limit: "10"},
+			mockSvcOutput:  paging.NewPage([]Swap{{ID: "1"}}, ""),
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "block range too wide",
+			url:            "/v1/blocks/swaps?fromBlock=1&toBlock=18319881",
+			method:         "GetSwapsByBlockRangeService",
+			params:         RangeParams{From: "1", To: "18319881", Limit: "5"},
+			mockSvcErr:     errors.New("invalid block range"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "time range",
+			url:            "/v1/swaps?from=1697030000&to=1697031000&token=0xtoken&cursor=abc",
+			method:         "GetSwapsByTimeRangeService",
+			params:         RangeParams{From: "1697030000", To: "1697031000", Token: "0xtoken", Limit: "5", Cursor: "abc"},
+			mockSvcOutput:  paging.NewPage([]Swap{{ID: "1"}}, ""),
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockBlockService)
+			mockSvc.On(test.method, mock.Anything, test.params).Return(test.mockSvcOutput, test.mockSvcErr).Once()
+
+			h := &Handler{
+				BlockService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Get("/v1/swaps", h.GetSwapsByTimeRangeHandler)
+			r.Get("/v1/blocks/swaps", h.GetSwapsByBlockRangeHandler)
+			r.Get("/v1/blocks/{block}/swaps", h.GetSwapsByBlockHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetBlockSummaryHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		mockSvcOutput  *BlockSummary
+		mockSvcErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "valid request",
+			url:            "/v1/blocks/18319881",
+			mockSvcOutput:  &BlockSummary{Block: 18319881, SwapCount: 3},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "timeout",
+			url:            "/v1/blocks/18319881",
+			mockSvcErr:     context.DeadlineExceeded,
+			expectedStatus: http.StatusRequestTimeout,
+		},
+		{
+			name:           "invalid block",
+			url:            "/v1/blocks/invalid",
+			mockSvcErr:     errors.New("invalid block"),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockBlockService)
+			mockSvc.On("GetBlockSummaryService", mock.Anything, mock.Anything).Return(test.mockSvcOutput, test.mockSvcErr).Once()
+
+			h := &Handler{
+				BlockService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Get("/v1/blocks/{block}", h.GetBlockSummaryHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetBlockTimeHandlers(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		method         string
+		mockSvcOutput  *blocktime.Block
+		mockSvcErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "block by time",
+			url:            "/v1/blocks/by-time/1697031100",
+			method:         "GetBlockByTimeService",
+			mockSvcOutput:  &blocktime.Block{Number: 18319881, Timestamp: 1697031095, Exact: true},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid timestamp",
+			url:            "/v1/blocks/by-time/yesterday",
+			method:         "GetBlockByTimeService",
+			mockSvcErr:     errors.New("invalid timestamp"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "time of block",
+			url:            "/v1/blocks/18319881/time",
+			method:         "GetBlockTimeService",
+			mockSvcOutput:  &blocktime.Block{Number: 18319881, Timestamp: 1697031095, Exact: true},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "block not found",
+			url:            "/v1/blocks/99999999/time",
+			method:         "GetBlockTimeService",
+			mockSvcErr:     blocktime.ErrNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "timeout",
+			url:            "/v1/blocks/18319881/time",
+			method:         "GetBlockTimeService",
+			mockSvcErr:     context.DeadlineExceeded,
+			expectedStatus: http.StatusRequestTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockBlockService)
+			mockSvc.On(test.method, mock.Anything, mock.Anything).Return(test.mockSvcOutput, test.mockSvcErr).Once()
+
+			h := &Handler{
+				BlockService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Get("/v1/blocks/by-time/{ts}", h.GetBlockByTimeHandler)
+			r.Get("/v1/blocks/{block}/time", h.GetBlockTimeHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetMintsByBlockHandler(t *testing.T) {
+	mockSvc := new(MockBlockService)
+	mockSvc.On("GetMintsByBlockService", mock.Anything, "latest", EventsParams{Limit: "1"}).
+		Return(&BlockPage[Mint]{Block: 18319881, Page: *paging.NewPage([]Mint{{ID: "0xa#1"}}, "1,0xa#1")}, nil).Once()
+	mockSvc.On("GetMintsByBlockService", mock.Anything, "invalid", EventsParams{Limit: "5"}).
+		Return((*BlockPage[Mint])(nil), errors.New("invalid block")).Once()
+
+	h := &Handler{
+		BlockService: mockSvc,
+	}
+
+	r := chi.NewRouter()
+	r.Get("/v1/blocks/{block}/mints", h.GetMintsByBlockHandler)
+
+	req, err := http.NewRequest(http.MethodGet, "/v1/blocks/latest/mints?first=1", nil)
+	assert.NoError(t, err)
+	req.Host = "localhost:3000"
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `{"block":18319881,"data":[`)
+	assert.Equal(t, `<http://localhost:3000/v1/blocks/18319881/mints?cursor=MSwweGEjMQ&first=1>; rel="next"`, rr.Header().Get("Link"))
+
+	req, err = http.NewRequest(http.MethodGet, "/v1/blocks/invalid/mints", nil)
+	assert.NoError(t, err)
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+
+	mockSvc.AssertExpectations(t)
+}
+
+func TestGetEventsByPoolHandlers(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+	params := EventsParams{From: "1697030000", To: "1697031000", Limit: "5"}
+
+	tests := []struct {
+		name           string
+		url            string
+		setup          func(m *MockBlockService)
+		expectedStatus int
+	}{
+		{
+			name: "swaps",
+			url:  "/v1/pools/" + pool + "/swaps?from=1697030000&to=1697031000&minUSD=100000&sender=0x3fc91a3afd70395cd496c647d5a6cc9d4b2b7fad",
+			setup: func(m *MockBlockService) {
+				swapsParams := params
+				swapsParams.MinUSD = "100000"
+				swapsParams.Sender = "0x3fc91a3afd70395cd496c647d5a6cc9d4b2b7fad"
+				m.On("GetSwapsByPoolService", mock.Anything, pool, swapsParams).Return(paging.NewPage([]Swap{{ID: "0xa#1"}}, ""), nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "invalid sender",
+			url:  "/v1/pools/" + pool + "/swaps?sender=invalid",
+			setup: func(m *MockBlockService) {
+				m.On("GetSwapsByPoolService", mock.Anything, pool, EventsParams{Sender: "invalid", Limit: "5"}).Return((*paging.Page[Swap])(nil), errors.New("invalid sender")).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "mints",
+			url:  "/v1/pools/" + pool + "/mints?from=1697030000&to=1697031000",
+			setup: func(m *MockBlockService) {
+				m.On("GetMintsByPoolService", mock.Anything, pool, params).Return(paging.NewPage([]Mint{{ID: "0xa#1"}}, ""), nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "burns",
+			url:  "/v1/pools/" + pool + "/burns?from=1697030000&to=1697031000",
+			setup: func(m *MockBlockService) {
+				m.On("GetBurnsByPoolService", mock.Anything, pool, params).Return(paging.NewPage([]Burn{{ID: "0xa#1"}}, ""), nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "collects timeout",
+			url:  "/v1/pools/" + pool + "/collects?from=1697030000&to=1697031000",
+			setup: func(m *MockBlockService) {
+				m.On("GetCollectsByPoolService", mock.Anything, pool, params).Return((*paging.Page[Collect])(nil), context.DeadlineExceeded).Once()
+			},
+			expectedStatus: http.StatusRequestTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockBlockService)
+			test.setup(mockSvc)
+
+			h := &Handler{
+				BlockService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Route("/v1/pools/{pool}", func(r chi.Router) {
+				r.Get("/swaps", h.GetSwapsByPoolHandler)
+				r.Get("/mints", h.GetMintsByPoolHandler)
+				r.Get("/burns", h.GetBurnsByPoolHandler)
+				r.Get("/collects", h.GetCollectsByPoolHandler)
+			})
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
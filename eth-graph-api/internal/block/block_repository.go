@@ -3,7 +3,11 @@ package block
 import (
 	"context"
 	"eth-graph-api/pkg/logger"
+	"eth-graph-api/pkg/paging"
+	"eth-graph-api/pkg/subgraph"
 	"github.com/shurcooL/graphql"
+	"strconv"
+	"strings"
 )
 
 // GraphClient is an interface that declares a method for making
@@ -20,10 +24,27 @@ type GraphClient interface {
 // Repository is an interface that declares methods for fetching Swap data,
 // providing a way to access Swap data without exposing details of the data retrieval.
 // GetSwapsByBlock retrieves swap data related to a specific blockchain block.
-// It takes a block number and a maximum number of results ("first") as parameters,
-// and returns a slice of Swaps and an error if the data retrieval fails
+// It takes a block number, whether to query the swaps as of the block instead of the ones made in it ("asOf"),
+// a maximum number of results ("limit") and a cursor to continue after as parameters,
+// and returns a slice of Swaps, the cursor to continue after them, and an error if the data retrieval fails.
+// GetSwaps retrieves swap data over a range of blocks or time, narrowed down by a SwapsFilter.
+// The swaps are ordered by timestamp and then by ID, even over a block range.
+// It returns a slice of Swaps, the cursor to continue after them, and an error if the data retrieval fails.
+// GetMintsByBlock, GetBurnsByBlock and GetCollectsByBlock retrieve the liquidity events made in a block
+// the same way GetSwapsByBlock retrieves its swaps.
+// GetMintsByPool, GetBurnsByPool and GetCollectsByPool retrieve the liquidity events of a pool,
+// narrowed down by an EventsFilter, the same way GetSwaps retrieves swaps over a time range.
+// GetSwapsByPool retrieves the swaps of a pool, narrowed down by an EventsFilter, in execution order.
 type Repository interface {
-	GetSwapsByBlock(ctx context.Context, block int, first int) ([]Swap, error)
+	GetSwapsByBlock(ctx context.Context, block int, asOf bool, limit int, cursor string) ([]Swap, string, error)
+	GetSwaps(ctx context.Context, filter SwapsFilter) ([]Swap, string, error)
+	GetMintsByBlock(ctx context.Context, block int, limit int, cursor string) ([]Mint, string, error)
+	GetBurnsByBlock(ctx context.Context, block int, limit int, cursor string) ([]Burn, string, error)
+	GetCollectsByBlock(ctx context.Context, block int, limit int, cursor string) ([]Collect, string, error)
+	GetSwapsByPool(ctx context.Context, filter EventsFilter) ([]Swap, string, error)
+	GetMintsByPool(ctx context.Context, filter EventsFilter) ([]Mint, string, error)
+	GetBurnsByPool(ctx context.Context, filter EventsFilter) ([]Burn, string, error)
+	GetCollectsByPool(ctx context.Context, filter EventsFilter) ([]Collect, string, error)
 }
 
 // blockRepository is a struct that implements the Repository interface,
@@ -44,26 +65,539 @@ func NewBlockRepository(graphClient GraphClient) Repository {
 
 // GetSwapsByBlock is a method on blockRepository that fetches and returns
 // swap data for a specific blockchain block from the GraphQL API.
-// - It constructs a GraphQL query and variables based on provided parameters,
-// - Sends the query to the GraphQL API using the graphClient,
+// - It constructs a GraphQL query and variables based on provided parameters:
+// by default, it matches the swaps made in the block, i.e. whose transaction has the block's number,
+// while with `asOf` it runs a time-travel query over the swaps indexed as of the block,
+// - Walks the swaps in the block's execution order, i.e. ordered by log index and then by ID,
+// page by page, starting after the `cursor`, until `limit` swaps are gathered,
 // - Handles potential query execution errors,
-// - And returns the fetched swap data.
-func (tr *blockRepository) GetSwapsByBlock(ctx context.Context, block int, first int) ([]Swap, error) {
+// - And returns the fetched swap data along with the cursor to continue after it.
+func (tr *blockRepository) GetSwapsByBlock(ctx context.Context, block int, asOf bool, limit int, cursor string) ([]Swap, string, error) {
 
-	var query struct {
-		Swaps []Swap `graphql:"swaps(block: {number: $block}, first: $first)"`
+	base := subgraph.Swap_filter{
+		"transaction_": map[string]interface{}{"blockNumber": strconv.Itoa(block)},
+	}
+	if asOf {
+		base = subgraph.Swap_filter{}
+	}
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Swap, error) {
+		where, err := eventsWhere([]subgraph.Swap_filter{base}, "logIndex", cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"where": where,
+		}
+
+		if asOf {
+			var query struct {
+				Swaps []Swap `graphql:"swaps(block: {number: $block}, first: $first, orderBy: logIndex, orderDirection: asc, where: $where)"`
+			}
+			vars["block"] = graphql.Int(block)
+
+			err = tr.graphClient.Query(ctx, &query, vars)
+			if err != nil {
+				logger.Error("error querying swaps as of block", err)
+				return nil, err
+			}
+
+			return query.Swaps, nil
+		}
+
+		var query struct {
+			Swaps []Swap `graphql:"swaps(first: $first, orderBy: logIndex, orderDirection: asc, where: $where)"`
+		}
+
+		err = tr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("error querying swaps by block", err)
+			return nil, err
+		}
+
+		return query.Swaps, nil
+	}
+
+	cursorOf := func(s Swap) string {
+		return s.LogIndex + "," + s.ID
+	}
+
+	return paging.Collect(ctx, fetch, cursorOf, cursor, limit)
+}
+
+// GetSwaps is a method on blockRepository that fetches and returns
+// swap data over a range of blocks or time from the GraphQL API.
+// - It constructs a GraphQL query matching the swaps whose transaction belongs to the block range of `filter`,
+// or, if it has no block range, the swaps made within its time range,
+// narrowed down to the pool and the token of `filter`, if any,
+// - Walks the swaps ordered by timestamp and then by ID page by page,
+// starting after `filter.Cursor`, until `filter.Limit` swaps are gathered,
+// - Handles potential query execution errors,
+// - And returns the fetched swap data along with the cursor to continue after it.
+func (tr *blockRepository) GetSwaps(ctx context.Context, filter SwapsFilter) ([]Swap, string, error) {
+
+	base := subgraph.Swap_filter{}
+	if filter.FromBlock > 0 {
+		base["transaction_"] = map[string]interface{}{
+			"blockNumber_gte": strconv.Itoa(filter.FromBlock),
+			"blockNumber_lte": strconv.Itoa(filter.ToBlock),
+		}
+	} else {
+		base["timestamp_gte"] = strconv.FormatInt(filter.From, 10)
+		base["timestamp_lte"] = strconv.FormatInt(filter.To, 10)
+	}
+
+	if filter.Pool != "" {
+		base["pool"] = filter.Pool
+	}
+
+	filters := []subgraph.Swap_filter{base}
+	if filter.Token != "" {
+		side0 := subgraph.Swap_filter{"token0": filter.Token}
+		side1 := subgraph.Swap_filter{"token1": filter.Token}
+		for k, v := range base {
+			side0[k] = v
+			side1[k] = v
+		}
+		filters = []subgraph.Swap_filter{side0, side1}
+	}
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Swap, error) {
+		where, err := eventsWhere(filters, "timestamp", cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		var query struct {
+			Swaps []Swap `graphql:"swaps(first: $first, orderBy: timestamp, orderDirection: asc, where: $where)"`
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"where": where,
+		}
+
+		err = tr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("error querying swaps over a range", err)
+			return nil, err
+		}
+
+		return query.Swaps, nil
+	}
+
+	cursorOf := func(s Swap) string {
+		return s.Timestamp + "," + s.ID
+	}
+
+	return paging.Collect(ctx, fetch, cursorOf, filter.Cursor, filter.Limit)
+}
+
+// GetMintsByBlock is a method on blockRepository that fetches and returns
+// the mints made in a specific blockchain block from the GraphQL API,
+// in the block's execution order, page by page, starting after the `cursor`, until `limit` mints are gathered.
+func (tr *blockRepository) GetMintsByBlock(ctx context.Context, block int, limit int, cursor string) ([]Mint, string, error) {
+
+	base := subgraph.Mint_filter{
+		"transaction_": map[string]interface{}{"blockNumber": strconv.Itoa(block)},
+	}
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Mint, error) {
+		where, err := eventsWhere([]subgraph.Mint_filter{base}, "logIndex", cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		var query struct {
+			Mints []Mint `graphql:"mints(first: $first, orderBy: logIndex, orderDirection: asc, where: $where)"`
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"where": where,
+		}
+
+		err = tr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("error querying mints by block", err)
+			return nil, err
+		}
+
+		return query.Mints, nil
+	}
+
+	cursorOf := func(m Mint) string {
+		return m.LogIndex + "," + m.ID
+	}
+
+	return paging.Collect(ctx, fetch, cursorOf, cursor, limit)
+}
+
+// GetBurnsByBlock is a method on blockRepository that fetches and returns
+// the burns made in a specific blockchain block from the GraphQL API,
+// in the block's execution order, page by page, starting after the `cursor`, until `limit` burns are gathered.
+func (tr *blockRepository) GetBurnsByBlock(ctx context.Context, block int, limit int, cursor string) ([]Burn, string, error) {
+
+	base := subgraph.Burn_filter{
+		"transaction_": map[string]interface{}{"blockNumber": strconv.Itoa(block)},
+	}
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Burn, error) {
+		where, err := eventsWhere([]subgraph.Burn_filter{base}, "logIndex", cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		var query struct {
+			Burns []Burn `graphql:"burns(first: $first, orderBy: logIndex, orderDirection: asc, where: $where)"`
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"where": where,
+		}
+
+		err = tr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("error querying burns by block", err)
+			return nil, err
+		}
+
+		return query.Burns, nil
+	}
+
+	cursorOf := func(b Burn) string {
+		return b.LogIndex + "," + b.ID
+	}
+
+	return paging.Collect(ctx, fetch, cursorOf, cursor, limit)
+}
+
+// GetCollectsByBlock is a method on blockRepository that fetches and returns
+// the collects made in a specific blockchain block from the GraphQL API,
+// in the block's execution order, page by page, starting after the `cursor`, until `limit` collects are gathered.
+func (tr *blockRepository) GetCollectsByBlock(ctx context.Context, block int, limit int, cursor string) ([]Collect, string, error) {
+
+	base := subgraph.Collect_filter{
+		"transaction_": map[string]interface{}{"blockNumber": strconv.Itoa(block)},
+	}
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Collect, error) {
+		where, err := eventsWhere([]subgraph.Collect_filter{base}, "logIndex", cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		var query struct {
+			Collects []Collect `graphql:"collects(first: $first, orderBy: logIndex, orderDirection: asc, where: $where)"`
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"where": where,
+		}
+
+		err = tr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("error querying collects by block", err)
+			return nil, err
+		}
+
+		return query.Collects, nil
+	}
+
+	cursorOf := func(c Collect) string {
+		return c.LogIndex + "," + c.ID
+	}
+
+	return paging.Collect(ctx, fetch, cursorOf, cursor, limit)
+}
+
+// GetSwapsByPool is a method on blockRepository that fetches and returns
+// the swaps of the pool of `filter` from the GraphQL API, the swap tape of the pool.
+// - It constructs a GraphQL query matching the swaps of the pool made within the time range of `filter`, if it has one,
+// worth at least `filter.MinUSD` and made by `filter.Sender`, unless they are empty,
+// - Walks the swaps in execution order, i.e. ordered by timestamp and then by log index, page by page,
+// starting after `filter.Cursor`, until `filter.Limit` swaps are gathered,
+// - Handles potential query execution errors,
+// - And returns the fetched swap data along with the cursor to continue after it.
+// The subgraph orders by a single field, so the swaps are fetched ordered by timestamp, and the swaps of the block
+// a page ends with, which may be cut, are fetched again as a whole, ordered by log index.
+func (tr *blockRepository) GetSwapsByPool(ctx context.Context, filter EventsFilter) ([]Swap, string, error) {
+
+	base := subgraph.Swap_filter(poolEventsFilter(filter))
+	if filter.MinUSD != "" {
+		base["amountUSD_gte"] = filter.MinUSD
+	}
+	if filter.Sender != "" {
+		base["sender"] = filter.Sender
+	}
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Swap, error) {
+		where, err := swapTapeWhere(base, cursor, "")
+		if err != nil {
+			return nil, err
+		}
+
+		var query struct {
+			Swaps []Swap `graphql:"swaps(first: $first, orderBy: timestamp, orderDirection: asc, where: $where)"`
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"where": where,
+		}
+
+		err = tr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("error querying swaps by pool", err)
+			return nil, err
+		}
+
+		swaps := query.Swaps
+		if len(swaps) == first {
+			last := swaps[len(swaps)-1].Timestamp
+			for len(swaps) > 0 && swaps[len(swaps)-1].Timestamp == last {
+				swaps = swaps[:len(swaps)-1]
+			}
+
+			where, err = swapTapeWhere(base, cursor, last)
+			if err != nil {
+				return nil, err
+			}
+
+			var block struct {
+				Swaps []Swap `graphql:"swaps(first: $first, orderBy: logIndex, orderDirection: asc, where: $where)"`
+			}
+
+			vars = map[string]interface{}{
+				"first": graphql.Int(paging.PageSize),
+				"where": where,
+			}
+
+			err = tr.graphClient.Query(ctx, &block, vars)
+			if err != nil {
+				logger.Error("error querying swaps by pool", err)
+				return nil, err
+			}
+
+			swaps = append(swaps, block.Swaps...)
+		}
+
+		return subgraph.InExecutionOrder(swaps, func(s Swap) (string, string) {
+			return s.Timestamp, s.LogIndex
+		}), nil
+	}
+
+	cursorOf := func(s Swap) string {
+		return s.Timestamp + "," + s.LogIndex
+	}
+
+	return paging.Collect(ctx, fetch, cursorOf, filter.Cursor, filter.Limit)
+}
+
+// swapTapeWhere builds the "where" argument matching the swaps matched by `base` past the `cursor`,
+// which holds the timestamp and the log index of the last swap seen, in execution order.
+// Unless `timestamp` is empty, the swaps are narrowed down to the ones made at that time, i.e. in that block.
+func swapTapeWhere(base subgraph.Swap_filter, cursor string, timestamp string) (subgraph.Swap_filter, error) {
+	var branches []subgraph.Swap_filter
+
+	if cursor == "" {
+		branches = []subgraph.Swap_filter{{}}
+	} else {
+		ts, logIndex, ok := strings.Cut(cursor, ",")
+		if !ok || ts == "" || logIndex == "" {
+			return nil, paging.ErrInvalidCursor
+		}
+		branches = []subgraph.Swap_filter{
+			{"timestamp_gt": ts},
+			{"timestamp": ts, "logIndex_gt": logIndex},
+		}
+		// Within a single block, only the swaps past the cursor in it or all of its swaps are left
+		if timestamp == ts {
+			branches = branches[1:]
+		} else if timestamp != "" {
+			branches = branches[:1]
+		}
+	}
+
+	for _, branch := range branches {
+		for k, v := range base {
+			branch[k] = v
+		}
+		if timestamp != "" {
+			delete(branch, "timestamp_gt")
+			branch["timestamp"] = timestamp
+		}
+	}
+
+	if len(branches) == 1 {
+		return branches[0], nil
+	}
+
+	return subgraph.Swap_filter{"or": branches}, nil
+}
+
+// GetMintsByPool is a method on blockRepository that fetches and returns
+// the mints of the pool of `filter`, made within its time range if it has one, from the GraphQL API,
+// ordered by timestamp and then by ID, page by page, starting after `filter.Cursor`, until `filter.Limit` mints are gathered.
+func (tr *blockRepository) GetMintsByPool(ctx context.Context, filter EventsFilter) ([]Mint, string, error) {
+
+	base := subgraph.Mint_filter(poolEventsFilter(filter))
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Mint, error) {
+		where, err := eventsWhere([]subgraph.Mint_filter{base}, "timestamp", cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		var query struct {
+			Mints []Mint `graphql:"mints(first: $first, orderBy: timestamp, orderDirection: asc, where: $where)"`
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"where": where,
+		}
+
+		err = tr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("error querying mints by pool", err)
+			return nil, err
+		}
+
+		return query.Mints, nil
+	}
+
+	cursorOf := func(m Mint) string {
+		return m.Timestamp + "," + m.ID
+	}
+
+	return paging.Collect(ctx, fetch, cursorOf, filter.Cursor, filter.Limit)
+}
+
+// GetBurnsByPool is a method on blockRepository that fetches and returns
+// the burns of the pool of `filter`, made within its time range if it has one, from the GraphQL API,
+// ordered by timestamp and then by ID, page by page, starting after `filter.Cursor`, until `filter.Limit` burns are gathered.
+func (tr *blockRepository) GetBurnsByPool(ctx context.Context, filter EventsFilter) ([]Burn, string, error) {
+
+	base := subgraph.Burn_filter(poolEventsFilter(filter))
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Burn, error) {
+		where, err := eventsWhere([]subgraph.Burn_filter{base}, "timestamp", cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		var query struct {
+			Burns []Burn `graphql:"burns(first: $first, orderBy: timestamp, orderDirection: asc, where: $where)"`
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"where": where,
+		}
+
+		err = tr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("error querying burns by pool", err)
+			return nil, err
+		}
+
+		return query.Burns, nil
+	}
+
+	cursorOf := func(b Burn) string {
+		return b.Timestamp + "," + b.ID
+	}
+
+	return paging.Collect(ctx, fetch, cursorOf, filter.Cursor, filter.Limit)
+}
+
+// GetCollectsByPool is a method on blockRepository that fetches and returns
+// the collects of the pool of `filter`, made within its time range if it has one, from the GraphQL API,
+// ordered by timestamp and then by ID, page by page, starting after `filter.Cursor`, until `filter.Limit` collects are gathered.
+func (tr *blockRepository) GetCollectsByPool(ctx context.Context, filter EventsFilter) ([]Collect, string, error) {
+
+	base := subgraph.Collect_filter(poolEventsFilter(filter))
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Collect, error) {
+		where, err := eventsWhere([]subgraph.Collect_filter{base}, "timestamp", cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		var query struct {
+			Collects []Collect `graphql:"collects(first: $first, orderBy: timestamp, orderDirection: asc, where: $where)"`
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"where": where,
+		}
+
+		err = tr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("error querying collects by pool", err)
+			return nil, err
+		}
+
+		return query.Collects, nil
+	}
+
+	cursorOf := func(c Collect) string {
+		return c.Timestamp + "," + c.ID
+	}
+
+	return paging.Collect(ctx, fetch, cursorOf, filter.Cursor, filter.Limit)
+}
+
+// poolEventsFilter builds the filter shared by the liquidity events of a pool:
+// the pool of `filter` and its time range, if it has one.
+func poolEventsFilter(filter EventsFilter) map[string]interface{} {
+	base := map[string]interface{}{
+		"pool": filter.Pool,
+	}
+
+	if filter.From > 0 {
+		base["timestamp_gte"] = strconv.FormatInt(filter.From, 10)
+	}
+	if filter.To > 0 {
+		base["timestamp_lte"] = strconv.FormatInt(filter.To, 10)
+	}
+
+	return base
+}
+
+// eventsWhere builds the "where" argument matching the events (swaps, mints, etc.) matched by any of `filters`
+// past the `cursor` in the order of the `orderBy` field and then of the ID.
+// The cursor holds the value of that field and the ID of the last event seen, so it splits every filter in two:
+// events past the value, and events with the same value past the ID, each branch carrying its whole filter.
+func eventsWhere[F ~map[string]interface{}](filters []F, orderBy string, cursor string) (F, error) {
+	if cursor == "" {
+		if len(filters) == 1 {
+			return filters[0], nil
+		}
+		return F{"or": filters}, nil
 	}
 
-	vars := map[string]interface{}{
-		"block": graphql.Int(block),
-		"first": graphql.Int(first),
+	value, id, ok := strings.Cut(cursor, ",")
+	if !ok || value == "" || id == "" {
+		return nil, paging.ErrInvalidCursor
 	}
 
-	err := tr.graphClient.Query(ctx, &query, vars)
-	if err != nil {
-		logger.Error("error querying swaps by block", err)
-		return nil, err
+	var branches []F
+	for _, filter := range filters {
+		pastValue := F{orderBy + "_gt": value}
+		pastID := F{orderBy: value, "id_gt": id}
+		for k, v := range filter {
+			pastValue[k] = v
+			pastID[k] = v
+		}
+		branches = append(branches, pastValue, pastID)
 	}
 
-	return query.Swaps, nil
+	return F{"or": branches}, nil
 }
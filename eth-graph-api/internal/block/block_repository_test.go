@@ -3,6 +3,9 @@ package block
 import (
 	"context"
 	"errors"
+	"eth-graph-api/pkg/paging"
+	"eth-graph-api/pkg/subgraph"
+	"github.com/shurcooL/graphql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
@@ -17,6 +20,16 @@ func (m *MockGraphClient) Query(ctx context.Context, query interface{}, vars map
 	return args.Error(0)
 }
 
+// swapsInBlock is the query of the swaps made in a block
+type swapsInBlock = struct {
+	Swaps []Swap `graphql:"swaps(first: $first, orderBy: logIndex, orderDirection: asc, where: $where)"`
+}
+
+// swapsAsOfBlock is the time-travel query of the swaps indexed as of a block
+type swapsAsOfBlock = struct {
+	Swaps []Swap `graphql:"swaps(block: {number: $block}, first: $first, orderBy: logIndex, orderDirection: asc, where: $where)"`
+}
+
 func TestGetSwapsByBlock(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -34,9 +47,7 @@ func TestGetSwapsByBlock(t *testing.T) {
 				m.On("Query", mock.Anything, mock.Anything, mock.Anything).
 					Return(nil).
 					Run(func(args mock.Arguments) {
-						arg := args.Get(1).(*struct {
-							Swaps []Swap `graphql:"swaps(block: {number: $block}, first: $first)"`
-						})
+						arg := args.Get(1).(*swapsInBlock)
 						arg.Swaps = []Swap{{ID: "1"}, {ID: "2"}}
 					})
 			},
@@ -60,7 +71,7 @@ func TestGetSwapsByBlock(t *testing.T) {
 			test.mockClientFunc(mockClient)
 
 			repo := NewBlockRepository(mockClient)
-			result, err := repo.GetSwapsByBlock(context.Background(), test.block, test.first)
+			result, _, err := repo.GetSwapsByBlock(context.Background(), test.block, false, test.first, "")
 
 			if test.expectedError != "" {
 				assert.Error(t, err)
@@ -74,3 +85,309 @@ func TestGetSwapsByBlock(t *testing.T) {
 		})
 	}
 }
+
+// TestGetSwapsByBlockVars guards against going back to a time-travel query:
+// the swaps made in a block are matched by the block number of their transaction.
+func TestGetSwapsByBlockVars(t *testing.T) {
+	mockClient := new(MockGraphClient)
+
+	expectedVars := map[string]interface{}{
+		"first": graphql.Int(6),
+		"where": subgraph.Swap_filter{
+			"transaction_": map[string]interface{}{"blockNumber": "18319881"},
+		},
+	}
+
+	mockClient.On("Query", mock.Anything, mock.MatchedBy(func(*swapsInBlock) bool { return true }), expectedVars).
+		Return(nil).Once()
+
+	repo := NewBlockRepository(mockClient)
+	_, _, err := repo.GetSwapsByBlock(context.Background(), 18319881, false, 5, "")
+
+	assert.NoError(t, err)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetSwapsByBlockAsOfVars(t *testing.T) {
+	mockClient := new(MockGraphClient)
+
+	expectedVars := map[string]interface{}{
+		"block": graphql.Int(18319881),
+		"first": graphql.Int(6),
+		"where": subgraph.Swap_filter{},
+	}
+
+	mockClient.On("Query", mock.Anything, mock.Anything, expectedVars).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			arg := args.Get(1).(*swapsAsOfBlock)
+			arg.Swaps = []Swap{{ID: "1"}}
+		}).Once()
+
+	repo := NewBlockRepository(mockClient)
+	result, _, err := repo.GetSwapsByBlock(context.Background(), 18319881, true, 5, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Swap{{ID: "1"}}, result)
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetSwapsByBlockPaging(t *testing.T) {
+	mockClient := new(MockGraphClient)
+
+	inBlock := map[string]interface{}{"blockNumber": "18319881"}
+	expectedVars := map[string]interface{}{
+		"first": graphql.Int(3),
+		"where": subgraph.Swap_filter{"or": []subgraph.Swap_filter{
+			{"transaction_": inBlock, "logIndex_gt": "7"},
+			{"transaction_": inBlock, "logIndex": "7", "id_gt": "0xa#0"},
+		}},
+	}
+
+	mockClient.On("Query", mock.Anything, mock.Anything, expectedVars).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			arg := args.Get(1).(*swapsInBlock)
+			arg.Swaps = []Swap{{ID: "0xb#1", LogIndex: "8"}, {ID: "0xc#2", LogIndex: "9"}, {ID: "0xd#3", LogIndex: "10"}}
+		}).Once()
+
+	repo := NewBlockRepository(mockClient)
+	result, next, err := repo.GetSwapsByBlock(context.Background(), 18319881, false, 2, "7,0xa#0")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Swap{{ID: "0xb#1", LogIndex: "8"}, {ID: "0xc#2", LogIndex: "9"}}, result)
+	assert.Equal(t, "9,0xc#2", next)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetSwapsVars(t *testing.T) {
+	tests := []struct {
+		name          string
+		filter        SwapsFilter
+		expectedWhere subgraph.Swap_filter
+	}{
+		{
+			name:   "block range",
+			filter: SwapsFilter{FromBlock: 18319800, ToBlock: 18319881, Limit: 5},
+			expectedWhere: subgraph.Swap_filter{
+				"transaction_": map[string]interface{}{"blockNumber_gte": "18319800", "blockNumber_lte": "18319881"},
+			},
+		},
+		{
+			name:   "time range of a pool",
+			filter: SwapsFilter{From: 1697030000, To: 1697031000, Pool: "0xpool", Limit: 5},
+			expectedWhere: subgraph.Swap_filter{
+				"timestamp_gte": "1697030000", "timestamp_lte": "1697031000", "pool": "0xpool",
+			},
+		},
+		{
+			name:   "time range of a token after a cursor",
+			filter: SwapsFilter{From: 1697030000, To: 1697031000, Token: "0xtoken", Limit: 5, Cursor: "1697030500,0xa#1"},
+			expectedWhere: subgraph.Swap_filter{"or": []subgraph.Swap_filter{
+				{"token0": "0xtoken", "timestamp_gte": "1697030000", "timestamp_lte": "1697031000", "timestamp_gt": "1697030500"},
+				{"token0": "0xtoken", "timestamp_gte": "1697030000", "timestamp_lte": "1697031000", "timestamp": "1697030500", "id_gt": "0xa#1"},
+				{"token1": "0xtoken", "timestamp_gte": "1697030000", "timestamp_lte": "1697031000", "timestamp_gt": "1697030500"},
+				{"token1": "0xtoken", "timestamp_gte": "1697030000", "timestamp_lte": "1697031000", "timestamp": "1697030500", "id_gt": "0xa#1"},
+			}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockClient := new(MockGraphClient)
+
+			expectedVars := map[string]interface{}{
+				"first": graphql.Int(6),
+				"where": test.expectedWhere,
+			}
+
+			mockClient.On("Query", mock.Anything, mock.Anything, expectedVars).
+				Return(nil).
+				Run(func(args mock.Arguments) {
+					arg := args.Get(1).(*struct {
+						Swaps []Swap `graphql:"swaps(first: $first, orderBy: timestamp, orderDirection: asc, where: $where)"`
+					})
+					arg.Swaps = []Swap{{ID: "0xb#2", Timestamp: "1697030600"}}
+				}).Once()
+
+			repo := NewBlockRepository(mockClient)
+			result, next, err := repo.GetSwaps(context.Background(), test.filter)
+
+			assert.NoError(t, err)
+			assert.Equal(t, []Swap{{ID: "0xb#2", Timestamp: "1697030600"}}, result)
+			assert.Equal(t, "", next)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetLiquidityEventsByBlockVars(t *testing.T) {
+	inBlock := map[string]interface{}{"blockNumber": "18319881"}
+
+	tests := []struct {
+		name  string
+		where interface{}
+		query func(repo Repository) error
+	}{
+		{
+			name:  "mints",
+			where: subgraph.Mint_filter{"transaction_": inBlock},
+			query: func(repo Repository) error {
+				_, _, err := repo.GetMintsByBlock(context.Background(), 18319881, 5, "")
+				return err
+			},
+		},
+		{
+			name:  "burns",
+			where: subgraph.Burn_filter{"transaction_": inBlock},
+			query: func(repo Repository) error {
+				_, _, err := repo.GetBurnsByBlock(context.Background(), 18319881, 5, "")
+				return err
+			},
+		},
+		{
+			name: "collects after a cursor",
+			where: subgraph.Collect_filter{"or": []subgraph.Collect_filter{
+				{"transaction_": inBlock, "logIndex_gt": "7"},
+				{"transaction_": inBlock, "logIndex": "7", "id_gt": "0xa#0"},
+			}},
+			query: func(repo Repository) error {
+				_, _, err := repo.GetCollectsByBlock(context.Background(), 18319881, 5, "7,0xa#0")
+				return err
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockClient := new(MockGraphClient)
+
+			expectedVars := map[string]interface{}{
+				"first": graphql.Int(6),
+				"where": test.where,
+			}
+			mockClient.On("Query", mock.Anything, mock.Anything, expectedVars).Return(nil).Once()
+
+			err := test.query(NewBlockRepository(mockClient))
+
+			assert.NoError(t, err)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestEventsWhere(t *testing.T) {
+	base := subgraph.Swap_filter{"pool": "0xpool"}
+
+	where, err := eventsWhere([]subgraph.Swap_filter{base}, "logIndex", "")
+	assert.NoError(t, err)
+	assert.Equal(t, base, where)
+
+	_, err = eventsWhere([]subgraph.Swap_filter{base}, "logIndex", "0xa#0")
+	assert.ErrorIs(t, err, paging.ErrInvalidCursor)
+}
+
+func TestGetEventsByPoolVars(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+
+	mockClient := new(MockGraphClient)
+	mockClient.On("Query", mock.Anything, mock.Anything, map[string]interface{}{
+		"first": graphql.Int(2),
+		"where": subgraph.Burn_filter{"or": []subgraph.Burn_filter{
+			{"pool": pool, "timestamp_gte": "1697030000", "timestamp_lte": "1697031000", "timestamp_gt": "1697030500"},
+			{"pool": pool, "timestamp_gte": "1697030000", "timestamp_lte": "1697031000", "timestamp": "1697030500", "id_gt": "0xb#2"},
+		}},
+	}).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(1).(*struct {
+			Burns []Burn `graphql:"burns(first: $first, orderBy: timestamp, orderDirection: asc, where: $where)"`
+		})
+		arg.Burns = []Burn{{ID: "0xc#3", Timestamp: "1697030600"}}
+	}).Once()
+
+	mockClient.On("Query", mock.Anything, mock.Anything, map[string]interface{}{
+		"first": graphql.Int(2),
+		"where": subgraph.Mint_filter{"pool": pool},
+	}).Return(nil).Once()
+
+	repo := NewBlockRepository(mockClient)
+
+	burns, next, err := repo.GetBurnsByPool(context.Background(), EventsFilter{Pool: pool, From: 1697030000, To: 1697031000, Limit: 1, Cursor: "1697030500,0xb#2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Burn{{ID: "0xc#3", Timestamp: "1697030600"}}, burns)
+	assert.Equal(t, "", next)
+
+	mints, _, err := repo.GetMintsByPool(context.Background(), EventsFilter{Pool: pool, Limit: 1})
+	assert.NoError(t, err)
+	assert.Empty(t, mints)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetSwapsByPoolOrder(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+	sender := "0x3fc91a3afd70395cd496c647d5a6cc9d4b2b7fad"
+
+	mockClient := new(MockGraphClient)
+	mockClient.On("Query", mock.Anything, mock.Anything, map[string]interface{}{
+		"first": graphql.Int(3),
+		"where": subgraph.Swap_filter{"or": []subgraph.Swap_filter{
+			{"pool": pool, "amountUSD_gte": "100000", "sender": sender, "timestamp_gt": "1697030500"},
+			{"pool": pool, "amountUSD_gte": "100000", "sender": sender, "timestamp": "1697030500", "logIndex_gt": "7"},
+		}},
+	}).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(1).(*struct {
+			Swaps []Swap `graphql:"swaps(first: $first, orderBy: timestamp, orderDirection: asc, where: $where)"`
+		})
+		// Ties on timestamp come back ordered by ID, and the last block is cut
+		arg.Swaps = []Swap{
+			{ID: "0xb#5", Timestamp: "1697030500", LogIndex: "12"},
+			{ID: "0xa#4", Timestamp: "1697030500", LogIndex: "9"},
+			{ID: "0xc#6", Timestamp: "1697030512", LogIndex: "30"},
+		}
+	}).Once()
+
+	mockClient.On("Query", mock.Anything, mock.Anything, map[string]interface{}{
+		"first": graphql.Int(paging.PageSize),
+		"where": subgraph.Swap_filter{"pool": pool, "amountUSD_gte": "100000", "sender": sender, "timestamp": "1697030512"},
+	}).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(1).(*swapsInBlock)
+		arg.Swaps = []Swap{
+			{ID: "0xd#7", Timestamp: "1697030512", LogIndex: "3"},
+			{ID: "0xc#6", Timestamp: "1697030512", LogIndex: "30"},
+		}
+	}).Once()
+
+	repo := NewBlockRepository(mockClient)
+	swaps, next, err := repo.GetSwapsByPool(context.Background(), EventsFilter{
+		Pool:   pool,
+		MinUSD: "100000",
+		Sender: sender,
+		Limit:  2,
+		Cursor: "1697030500,7",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Swap{
+		{ID: "0xa#4", Timestamp: "1697030500", LogIndex: "9"},
+		{ID: "0xb#5", Timestamp: "1697030500", LogIndex: "12"},
+	}, swaps)
+	assert.Equal(t, "1697030500,12", next)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestSwapTapeWhere(t *testing.T) {
+	base := subgraph.Swap_filter{"pool": "0xpool"}
+
+	where, err := swapTapeWhere(base, "1697030500,7", "1697030500")
+	assert.NoError(t, err)
+	assert.Equal(t, subgraph.Swap_filter{"pool": "0xpool", "timestamp": "1697030500", "logIndex_gt": "7"}, where)
+
+	where, err = swapTapeWhere(base, "", "")
+	assert.NoError(t, err)
+	assert.Equal(t, subgraph.Swap_filter{"pool": "0xpool"}, where)
+
+	_, err = swapTapeWhere(base, "1697030500", "")
+	assert.ErrorIs(t, err, paging.ErrInvalidCursor)
+}
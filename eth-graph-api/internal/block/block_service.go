@@ -3,107 +3,732 @@ package block
 import (
 	"context"
 	"errors"
+	"eth-graph-api/internal/meta"
+	"eth-graph-api/pkg/blocktime"
+	"eth-graph-api/pkg/calc"
+	"eth-graph-api/pkg/paging"
 	"eth-graph-api/pkg/validator"
+	"fmt"
+	"golang.org/x/sync/errgroup"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 // Service is an interface that declares methods for retrieving swap and token data.
 // Implementations of this interface will provide concrete functionality
 // for fetching and possibly processing swap and token data.
 type Service interface {
-	GetSwapsByBlockService(ctx context.Context, blockStr string, firstStr string) ([]Swap, error)
-	GetSwappedTokensByBlockService(ctx context.Context, blockStr string, firstStr string) ([]Token, error)
+	GetSwapsByBlockService(ctx context.Context, blockStr string, params SwapsParams) (*BlockPage[Swap], error)
+	GetSwappedTokensByBlockService(ctx context.Context, blockStr string, params SwapsParams) (*BlockPage[SwappedToken], error)
+	GetSwapsByBlockRangeService(ctx context.Context, params RangeParams) (*paging.Page[Swap], error)
+	GetSwapsByTimeRangeService(ctx context.Context, params RangeParams) (*paging.Page[Swap], error)
+	GetBlockSummaryService(ctx context.Context, blockStr string) (*BlockSummary, error)
+	GetBlockByTimeService(ctx context.Context, tsStr string) (*blocktime.Block, error)
+	GetBlockTimeService(ctx context.Context, blockStr string) (*blocktime.Block, error)
+	GetMintsByBlockService(ctx context.Context, blockStr string, params EventsParams) (*BlockPage[Mint], error)
+	GetBurnsByBlockService(ctx context.Context, blockStr string, params EventsParams) (*BlockPage[Burn], error)
+	GetSwapsByPoolService(ctx context.Context, poolStr string, params EventsParams) (*paging.Page[Swap], error)
+	GetMintsByPoolService(ctx context.Context, poolStr string, params EventsParams) (*paging.Page[Mint], error)
+	GetBurnsByPoolService(ctx context.Context, poolStr string, params EventsParams) (*paging.Page[Burn], error)
+	GetCollectsByPoolService(ctx context.Context, poolStr string, params EventsParams) (*paging.Page[Collect], error)
+}
+
+// topPoolsCount is the number of pools with the highest swap volume listed in a block summary.
+const topPoolsCount = 5
+
+// finalityDepth is how many blocks behind the latest one a block is considered final.
+// Ethereum finalizes a block once two epochs of 32 slots have passed, and the subgraph
+// does not know the finalized checkpoint, so "finalized" is taken as this many blocks behind its head.
+const finalityDepth = 64
+
+// IndexingStatus is an interface that declares a method for retrieving the indexing status of the subgraph,
+// implemented by meta.Service, which tells the latest indexed block block identifiers such as "latest" resolve against.
+type IndexingStatus interface {
+	GetMetaService(ctx context.Context) (*meta.Meta, error)
 }
 
 // blockService is a struct that implements the Service interface.
 // It uses a Repository to fetch swap data and implements additional logic
-// to process and return the requested data.
+// to process and return the requested data, a blocktime.Resolver
+// to convert between block numbers and timestamps, and an IndexingStatus to resolve block identifiers.
 type blockService struct {
 	blockRepo Repository
+	resolver  blocktime.Resolver
+	status    IndexingStatus
 }
 
 // NewBlockService is a constructor function that creates and returns a new instance
-// of the blockService, initializing it with a provided Repository.
-func NewBlockService(repo Repository) Service {
+// of the blockService, initializing it with a provided Repository, blocktime.Resolver and IndexingStatus.
+func NewBlockService(repo Repository, resolver blocktime.Resolver, status IndexingStatus) Service {
 	return &blockService{
 		blockRepo: repo,
+		resolver:  resolver,
+		status:    status,
 	}
 }
 
 // GetSwapsByBlockService retrieves swap data for a specific blockchain block.
-// - It validates and converts input parameters (block and first) from strings to integers,
-// - Fetches swap data using the blockRepo,
-// - And returns the fetched data.
-func (s *blockService) GetSwapsByBlockService(ctx context.Context, blockStr string, firstStr string) ([]Swap, error) {
+// - It validates and converts input parameters (block, limit and asOf) from strings,
+// resolving a block identifier such as "latest" to its number,
+// - Decodes the opaque `cursor` and fetches a page of swap data after it using the blockRepo,
+// - And returns the fetched data wrapped into a page envelope along with the block number.
+func (s *blockService) GetSwapsByBlockService(ctx context.Context, blockStr string, params SwapsParams) (*BlockPage[Swap], error) {
 
-	if !validator.IsValidBlock(blockStr) {
+	if !validator.IsValidBlockTag(blockStr) {
 		return nil, errors.New("invalid block")
 	}
 
-	firstNum, err := strconv.Atoi(firstStr)
+	limitNum, err := strconv.Atoi(params.Limit)
 	if err != nil {
-		firstNum = 5
+		limitNum = 5
 	}
 
-	if !validator.IsValidFirst(firstNum) {
-		firstNum = 5
+	if !validator.IsValidLimit(limitNum) {
+		limitNum = 5
 	}
 
-	blockNum, err := strconv.Atoi(blockStr)
+	cursor, err := paging.DecodeCursor(params.Cursor)
 	if err != nil {
-		return nil, errors.New("invalid block")
+		return nil, errors.New("invalid cursor")
+	}
+
+	asOf, err := parseAsOf(params.AsOf)
+	if err != nil {
+		return nil, err
+	}
+
+	blockNum, err := s.resolveBlock(ctx, blockStr)
+	if err != nil {
+		return nil, err
+	}
+
+	swaps, next, err := s.blockRepo.GetSwapsByBlock(ctx, blockNum, asOf, limitNum, cursor)
+	if err != nil {
+		return nil, err
 	}
 
-	return s.blockRepo.GetSwapsByBlock(ctx, blockNum, firstNum)
+	return &BlockPage[Swap]{Block: blockNum, Page: *paging.NewPage(swaps, next)}, nil
 }
 
-// GetSwappedTokensByBlockService retrieves token data for swaps in a specific blockchain block.
-// - It validates and converts input parameters (block and first) from strings to integers,
-// - Retrieves swap data using the blockRepo,
-// - Processes swaps to extract and collate related token data,
-// - And returns the token data.
-func (s *blockService) GetSwappedTokensByBlockService(ctx context.Context, blockStr string, firstStr string) ([]Token, error) {
+// GetSwappedTokensByBlockService retrieves the tokens swapped in a specific blockchain block.
+// - It validates and converts input parameters (block, limit and asOf) from strings,
+// resolving a block identifier such as "latest" to its number,
+// - Retrieves all swaps of the block using the blockRepo,
+// - Aggregates the swaps per token, telling tokens apart by their address rather than their symbol,
+// which is not unique,
+// - And returns a page of the tokens, in the order of their first swap in the block,
+// starting after the token the opaque `cursor` points to, along with the block number.
+func (s *blockService) GetSwappedTokensByBlockService(ctx context.Context, blockStr string, params SwapsParams) (*BlockPage[SwappedToken], error) {
 
-	if !validator.IsValidBlock(blockStr) {
+	if !validator.IsValidBlockTag(blockStr) {
 		return nil, errors.New("invalid block")
 	}
 
-	firstNum, err := strconv.Atoi(firstStr)
+	limitNum, err := strconv.Atoi(params.Limit)
 	if err != nil {
-		firstNum = 5
+		limitNum = 5
 	}
 
-	if !validator.IsValidFirst(firstNum) {
-		firstNum = 5
+	if !validator.IsValidLimit(limitNum) {
+		limitNum = 5
 	}
 
-	blockNum, err := strconv.Atoi(blockStr)
+	cursor, err := paging.DecodeCursor(params.Cursor)
 	if err != nil {
-		return nil, errors.New("invalid block")
+		return nil, errors.New("invalid cursor")
 	}
 
-	swaps, err := s.blockRepo.GetSwapsByBlock(ctx, blockNum, firstNum)
+	asOf, err := parseAsOf(params.AsOf)
+	if err != nil {
+		return nil, err
+	}
 
+	blockNum, err := s.resolveBlock(ctx, blockStr)
 	if err != nil {
+		return nil, err
+	}
+
+	swaps, next, err := s.blockRepo.GetSwapsByBlock(ctx, blockNum, asOf, paging.MaxItems, "")
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
 		return nil, errors.New("issue to get swaps")
 	}
 
-	var tokensMap = make(map[string]Token)
-	var tokens []Token
+	if next != "" {
+		return nil, errors.New("too many swaps in block")
+	}
+
+	tokens, err := aggregateSwappedTokens(swaps)
+	if err != nil {
+		return nil, errors.New("issue to aggregate swaps")
+	}
 
-	for _, s := range swaps {
-		if _, ok := tokensMap[s.Pool.Token0.Symbol]; !ok {
-			tokensMap[s.Pool.Token0.Symbol] = s.Pool.Token0
+	start := 0
+	if cursor != "" {
+		start = -1
+		for i, t := range tokens {
+			if t.ID == cursor {
+				start = i + 1
+				break
+			}
 		}
+		if start < 0 {
+			return nil, errors.New("invalid cursor")
+		}
+	}
 
-		if _, ok := tokensMap[s.Pool.Token1.Symbol]; !ok {
-			tokensMap[s.Pool.Token1.Symbol] = s.Pool.Token1
+	end := start + limitNum
+	if end >= len(tokens) {
+		return &BlockPage[SwappedToken]{Block: blockNum, Page: *paging.NewPage(tokens[start:], "")}, nil
+	}
+
+	return &BlockPage[SwappedToken]{Block: blockNum, Page: *paging.NewPage(tokens[start:end], tokens[end-1].ID)}, nil
+}
+
+// aggregateSwappedTokens sums up the `swaps` per token, keyed by the token address,
+// keeping the tokens in the order of their first swap. The amounts are summed exactly, keeping all their decimals.
+// A positive amount of a swap goes into the pool and a negative one comes out of it.
+func aggregateSwappedTokens(swaps []Swap) ([]SwappedToken, error) {
+	type amounts struct {
+		in  []string
+		out []string
+		usd []string
+	}
+
+	var tokens []SwappedToken
+	index := make(map[string]int)
+	var sums []amounts
+
+	for _, swap := range swaps {
+		for _, side := range []struct {
+			token  Token
+			amount string
+		}{
+			{swap.Pool.Token0, swap.Amount0},
+			{swap.Pool.Token1, swap.Amount1},
+		} {
+			i, ok := index[side.token.ID]
+			if !ok {
+				i = len(tokens)
+				index[side.token.ID] = i
+				tokens = append(tokens, SwappedToken{
+					ID:       side.token.ID,
+					Symbol:   side.token.Symbol,
+					Decimals: side.token.Decimals,
+				})
+				sums = append(sums, amounts{})
+			}
+
+			tokens[i].SwapCount++
+			if strings.HasPrefix(side.amount, "-") {
+				sums[i].out = append(sums[i].out, strings.TrimPrefix(side.amount, "-"))
+			} else if side.amount != "" {
+				sums[i].in = append(sums[i].in, side.amount)
+			}
+			if swap.AmountUSD != "" {
+				sums[i].usd = append(sums[i].usd, swap.AmountUSD)
+			}
 		}
 	}
 
-	for _, t := range tokensMap {
-		tokens = append(tokens, t)
+	var err error
+	for i := range tokens {
+		if tokens[i].AmountIn, err = calc.SumDecimals(sums[i].in); err != nil {
+			return nil, err
+		}
+		if tokens[i].AmountOut, err = calc.SumDecimals(sums[i].out); err != nil {
+			return nil, err
+		}
+		if tokens[i].VolumeUSD, err = calc.SumDecimals(sums[i].usd); err != nil {
+			return nil, err
+		}
 	}
 
 	return tokens, nil
 }
+
+// GetBlockSummaryService summarizes what happened in a specific blockchain block on Uniswap.
+// - It validates the block and resolves it to its number,
+// - Retrieves all swaps, mints, burns and collects made in the block using the blockRepo, concurrently,
+// - Counts the events and the distinct pools and tokens they touched, and sums up the USD volume of the swaps,
+// - And returns the summary along with the pools with the highest swap volume.
+// The timestamp of the block is taken from its events or, if nothing happened on Uniswap in the block,
+// resolved by the resolver. It is empty if the resolver cannot tell it either.
+func (s *blockService) GetBlockSummaryService(ctx context.Context, blockStr string) (*BlockSummary, error) {
+
+	if !validator.IsValidBlockTag(blockStr) {
+		return nil, errors.New("invalid block")
+	}
+
+	blockNum, err := s.resolveBlock(ctx, blockStr)
+	if err != nil {
+		return nil, err
+	}
+
+	var swaps []Swap
+	var mints []Mint
+	var burns []Burn
+	var collects []Collect
+
+	// A timeout is returned as is, while any other failure of the repository is not reported to the client
+	repoError := func(err error) error {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		return errors.New("issue to get block events")
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var next string
+		var err error
+		if swaps, next, err = s.blockRepo.GetSwapsByBlock(gctx, blockNum, false, paging.MaxItems, ""); err != nil {
+			return repoError(err)
+		}
+		if next != "" {
+			return errors.New("too many swaps in block")
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var next string
+		var err error
+		if mints, next, err = s.blockRepo.GetMintsByBlock(gctx, blockNum, paging.MaxItems, ""); err != nil {
+			return repoError(err)
+		}
+		if next != "" {
+			return errors.New("too many mints in block")
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var next string
+		var err error
+		if burns, next, err = s.blockRepo.GetBurnsByBlock(gctx, blockNum, paging.MaxItems, ""); err != nil {
+			return repoError(err)
+		}
+		if next != "" {
+			return errors.New("too many burns in block")
+		}
+		return nil
+	})
+	g.Go(func() error {
+		var next string
+		var err error
+		if collects, next, err = s.blockRepo.GetCollectsByBlock(gctx, blockNum, paging.MaxItems, ""); err != nil {
+			return repoError(err)
+		}
+		if next != "" {
+			return errors.New("too many collects in block")
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	summary := &BlockSummary{
+		Block:        blockNum,
+		SwapCount:    len(swaps),
+		MintCount:    len(mints),
+		BurnCount:    len(burns),
+		CollectCount: len(collects),
+	}
+
+	// Every event tells the pool it touched and the time of the block
+	var touched []Pool
+	var timestamps []string
+	for _, e := range swaps {
+		touched = append(touched, e.Pool)
+		timestamps = append(timestamps, e.Timestamp)
+	}
+	for _, e := range mints {
+		touched = append(touched, e.Pool)
+		timestamps = append(timestamps, e.Timestamp)
+	}
+	for _, e := range burns {
+		touched = append(touched, e.Pool)
+		timestamps = append(timestamps, e.Timestamp)
+	}
+	for _, e := range collects {
+		touched = append(touched, e.Pool)
+		timestamps = append(timestamps, e.Timestamp)
+	}
+
+	if len(timestamps) > 0 {
+		summary.Timestamp = timestamps[0]
+	} else {
+		block, err := s.resolver.TimeOf(ctx, int64(blockNum))
+		if err != nil && !errors.Is(err, blocktime.ErrNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			summary.Timestamp = strconv.FormatInt(block.Timestamp, 10)
+		}
+	}
+
+	pools := make(map[string]bool)
+	tokens := make(map[string]bool)
+	for _, p := range touched {
+		pools[p.ID] = true
+		tokens[p.Token0.ID] = true
+		tokens[p.Token1.ID] = true
+	}
+	summary.PoolCount = len(pools)
+	summary.TokenCount = len(tokens)
+
+	summary.TopPools, summary.VolumeUSD, err = poolVolumes(swaps)
+	if err != nil {
+		return nil, errors.New("issue to aggregate swaps")
+	}
+
+	if len(summary.TopPools) > topPoolsCount {
+		summary.TopPools = summary.TopPools[:topPoolsCount]
+	}
+
+	return summary, nil
+}
+
+// poolVolumes sums up the USD volume of the `swaps` per pool and in total.
+// It returns the pools ordered by their volume, from the highest, and then by their ID, along with the total volume.
+func poolVolumes(swaps []Swap) ([]PoolVolume, string, error) {
+	volumes := make([]PoolVolume, 0)
+	index := make(map[string]int)
+	var amounts [][]string
+	var all []string
+
+	for _, swap := range swaps {
+		i, ok := index[swap.Pool.ID]
+		if !ok {
+			i = len(volumes)
+			index[swap.Pool.ID] = i
+			volumes = append(volumes, PoolVolume{
+				ID:     swap.Pool.ID,
+				Token0: swap.Pool.Token0,
+				Token1: swap.Pool.Token1,
+			})
+			amounts = append(amounts, nil)
+		}
+
+		volumes[i].SwapCount++
+		if swap.AmountUSD != "" {
+			amounts[i] = append(amounts[i], swap.AmountUSD)
+			all = append(all, swap.AmountUSD)
+		}
+	}
+
+	var err error
+	for i := range volumes {
+		if volumes[i].VolumeUSD, err = calc.SumDecimals(amounts[i]); err != nil {
+			return nil, "", err
+		}
+	}
+
+	total, err := calc.SumDecimals(all)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// The volumes are sums computed above, so they always compare without an error
+	sort.SliceStable(volumes, func(i, j int) bool {
+		cmp, _ := calc.CompareNumbers(volumes[i].VolumeUSD, volumes[j].VolumeUSD)
+		if cmp != 0 {
+			return cmp > 0
+		}
+		return volumes[i].ID < volumes[j].ID
+	})
+
+	return volumes, total, nil
+}
+
+// GetSwapsByBlockRangeService retrieves swap data over a range of blocks.
+// - It validates the range, which may not span more than validator.MaxBlockRange blocks,
+// - Validates and parses the paging and filtering parameters,
+// - Fetches a page of the swaps made in the range using the blockRepo,
+// - And returns the fetched data wrapped into a page envelope.
+func (s *blockService) GetSwapsByBlockRangeService(ctx context.Context, params RangeParams) (*paging.Page[Swap], error) {
+
+	if !validator.IsValidBlockRange(params.From, params.To) {
+		return nil, fmt.Errorf("invalid block range, it may span up to %d blocks", validator.MaxBlockRange)
+	}
+
+	filter, err := rangeFilter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.FromBlock, _ = strconv.Atoi(params.From)
+	filter.ToBlock, _ = strconv.Atoi(params.To)
+
+	swaps, next, err := s.blockRepo.GetSwaps(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return paging.NewPage(swaps, next), nil
+}
+
+// GetSwapsByTimeRangeService retrieves swap data over a time range.
+// - It validates the range, which may not be longer than validator.MaxTimeRange seconds,
+// - Validates and parses the paging and filtering parameters,
+// - Fetches a page of the swaps made in the range using the blockRepo,
+// - And returns the fetched data wrapped into a page envelope.
+func (s *blockService) GetSwapsByTimeRangeService(ctx context.Context, params RangeParams) (*paging.Page[Swap], error) {
+
+	if !validator.IsValidTimeRange(params.From, params.To) {
+		return nil, fmt.Errorf("invalid range, it may be up to %d seconds long", validator.MaxTimeRange)
+	}
+
+	filter, err := rangeFilter(params)
+	if err != nil {
+		return nil, err
+	}
+
+	filter.From, _ = strconv.ParseInt(params.From, 10, 64)
+	filter.To, _ = strconv.ParseInt(params.To, 10, 64)
+
+	swaps, next, err := s.blockRepo.GetSwaps(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return paging.NewPage(swaps, next), nil
+}
+
+// GetBlockByTimeService resolves the latest block mined at or before a UNIX timestamp.
+// - It validates and converts the timestamp from string to integer,
+// - And returns the block resolved by the resolver, or blocktime.ErrNotFound.
+func (s *blockService) GetBlockByTimeService(ctx context.Context, tsStr string) (*blocktime.Block, error) {
+
+	if !validator.IsValidTimestamp(tsStr) {
+		return nil, errors.New("invalid timestamp")
+	}
+
+	ts, err := strconv.ParseInt(tsStr, 10, 64)
+	if err != nil {
+		return nil, errors.New("invalid timestamp")
+	}
+
+	return s.resolver.BlockAt(ctx, ts)
+}
+
+// GetBlockTimeService resolves the UNIX timestamp of a specific blockchain block.
+// - It validates the block and resolves it to its number,
+// - And returns the block resolved by the resolver, or blocktime.ErrNotFound.
+func (s *blockService) GetBlockTimeService(ctx context.Context, blockStr string) (*blocktime.Block, error) {
+
+	if !validator.IsValidBlockTag(blockStr) {
+		return nil, errors.New("invalid block")
+	}
+
+	blockNum, err := s.resolveBlock(ctx, blockStr)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.resolver.TimeOf(ctx, int64(blockNum))
+}
+
+// resolveBlock turns a valid block identifier into a block number.
+// A block number is taken as is, while "latest", "latest-N" and "finalized"
+// are resolved against the latest block indexed by the subgraph.
+func (s *blockService) resolveBlock(ctx context.Context, blockStr string) (int, error) {
+	if blockNum, err := strconv.Atoi(blockStr); err == nil {
+		return blockNum, nil
+	}
+
+	m, err := s.status.GetMetaService(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	latest := m.Block.Number
+	blockNum := latest
+	switch {
+	case blockStr == "finalized":
+		blockNum = latest - finalityDepth
+	case strings.HasPrefix(blockStr, "latest-"):
+		offset, _ := strconv.Atoi(strings.TrimPrefix(blockStr, "latest-"))
+		blockNum = latest - offset
+	}
+
+	if blockNum <= 0 {
+		return 0, errors.New("invalid block, it is before the first block")
+	}
+
+	return blockNum, nil
+}
+
+// GetMintsByBlockService retrieves the mints made in a specific blockchain block, see blockEvents.
+func (s *blockService) GetMintsByBlockService(ctx context.Context, blockStr string, params EventsParams) (*BlockPage[Mint], error) {
+	return blockEvents(ctx, s, blockStr, params, s.blockRepo.GetMintsByBlock)
+}
+
+// GetBurnsByBlockService retrieves the burns made in a specific blockchain block, see blockEvents.
+func (s *blockService) GetBurnsByBlockService(ctx context.Context, blockStr string, params EventsParams) (*BlockPage[Burn], error) {
+	return blockEvents(ctx, s, blockStr, params, s.blockRepo.GetBurnsByBlock)
+}
+
+// blockEvents retrieves the liquidity events made in a specific blockchain block.
+// - It validates and converts input parameters (block and limit) from strings,
+// resolving a block identifier such as "latest" to its number,
+// - Decodes the opaque `cursor` and fetches a page of the events after it using `get`,
+// - And returns the fetched data wrapped into a page envelope along with the block number.
+func blockEvents[T any](ctx context.Context, s *blockService, blockStr string, params EventsParams,
+	get func(ctx context.Context, block int, limit int, cursor string) ([]T, string, error)) (*BlockPage[T], error) {
+
+	if !validator.IsValidBlockTag(blockStr) {
+		return nil, errors.New("invalid block")
+	}
+
+	limitNum, err := strconv.Atoi(params.Limit)
+	if err != nil || !validator.IsValidLimit(limitNum) {
+		limitNum = 5
+	}
+
+	cursor, err := paging.DecodeCursor(params.Cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	blockNum, err := s.resolveBlock(ctx, blockStr)
+	if err != nil {
+		return nil, err
+	}
+
+	events, next, err := get(ctx, blockNum, limitNum, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BlockPage[T]{Block: blockNum, Page: *paging.NewPage(events, next)}, nil
+}
+
+// GetSwapsByPoolService retrieves the swaps of a specific pool in execution order, see poolEvents.
+// The swaps can further be narrowed down to the ones worth at least "minUSD" and made by "sender".
+func (s *blockService) GetSwapsByPoolService(ctx context.Context, poolStr string, params EventsParams) (*paging.Page[Swap], error) {
+	if params.MinUSD != "" && !validator.IsValidAmount(params.MinUSD) {
+		return nil, errors.New("invalid minUSD")
+	}
+	if params.Sender != "" && !validator.IsValidAddress(params.Sender) {
+		return nil, errors.New("invalid sender")
+	}
+
+	return poolEvents(ctx, poolStr, params, func(ctx context.Context, filter EventsFilter) ([]Swap, string, error) {
+		filter.MinUSD = params.MinUSD
+		filter.Sender = strings.ToLower(params.Sender)
+		return s.blockRepo.GetSwapsByPool(ctx, filter)
+	})
+}
+
+// GetMintsByPoolService retrieves the mints of a specific pool, see poolEvents.
+func (s *blockService) GetMintsByPoolService(ctx context.Context, poolStr string, params EventsParams) (*paging.Page[Mint], error) {
+	return poolEvents(ctx, poolStr, params, s.blockRepo.GetMintsByPool)
+}
+
+// GetBurnsByPoolService retrieves the burns of a specific pool, see poolEvents.
+func (s *blockService) GetBurnsByPoolService(ctx context.Context, poolStr string, params EventsParams) (*paging.Page[Burn], error) {
+	return poolEvents(ctx, poolStr, params, s.blockRepo.GetBurnsByPool)
+}
+
+// GetCollectsByPoolService retrieves the collects of a specific pool, see poolEvents.
+func (s *blockService) GetCollectsByPoolService(ctx context.Context, poolStr string, params EventsParams) (*paging.Page[Collect], error) {
+	return poolEvents(ctx, poolStr, params, s.blockRepo.GetCollectsByPool)
+}
+
+// poolEvents retrieves the events of a specific pool, from the oldest.
+// - It validates the pool and the optional time range, which requires both "from" and "to",
+// - Validates and parses the paging parameters,
+// - Fetches a page of the events using `get`,
+// - And returns the fetched data wrapped into a page envelope.
+func poolEvents[T any](ctx context.Context, poolStr string, params EventsParams,
+	get func(ctx context.Context, filter EventsFilter) ([]T, string, error)) (*paging.Page[T], error) {
+
+	if !validator.IsValidPool(poolStr) {
+		return nil, errors.New("invalid pool")
+	}
+
+	limitNum, err := strconv.Atoi(params.Limit)
+	if err != nil || !validator.IsValidLimit(limitNum) {
+		limitNum = 5
+	}
+
+	cursor, err := paging.DecodeCursor(params.Cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	filter := EventsFilter{
+		Pool:   strings.ToLower(poolStr),
+		Limit:  limitNum,
+		Cursor: cursor,
+	}
+
+	if params.From != "" || params.To != "" {
+		if !validator.IsValidRange(params.From, params.To) {
+			return nil, errors.New("invalid range")
+		}
+		filter.From, _ = strconv.ParseInt(params.From, 10, 64)
+		filter.To, _ = strconv.ParseInt(params.To, 10, 64)
+	}
+
+	events, next, err := get(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return paging.NewPage(events, next), nil
+}
+
+// rangeFilter validates and parses the parameters shared by the swaps-over-a-range requests:
+// the limit, defaulting to 5, the opaque cursor, and the optional pool and token.
+func rangeFilter(params RangeParams) (SwapsFilter, error) {
+	limitNum, err := strconv.Atoi(params.Limit)
+	if err != nil || !validator.IsValidLimit(limitNum) {
+		limitNum = 5
+	}
+
+	cursor, err := paging.DecodeCursor(params.Cursor)
+	if err != nil {
+		return SwapsFilter{}, errors.New("invalid cursor")
+	}
+
+	filter := SwapsFilter{
+		Limit:  limitNum,
+		Cursor: cursor,
+	}
+
+	if params.Pool != "" {
+		if !validator.IsValidPool(params.Pool) {
+			return SwapsFilter{}, errors.New("invalid pool")
+		}
+		filter.Pool = strings.ToLower(params.Pool)
+	}
+
+	if params.Token != "" {
+		if !validator.IsValidToken(params.Token) {
+			return SwapsFilter{}, errors.New("invalid token")
+		}
+		filter.Token = strings.ToLower(params.Token)
+	}
+
+	return filter, nil
+}
+
+// parseAsOf parses the optional "asOf" parameter, which switches from the swaps made in a block
+// to the swaps indexed as of the block. It defaults to false.
+func parseAsOf(asOfStr string) (bool, error) {
+	if asOfStr == "" {
+		return false, nil
+	}
+
+	asOf, err := strconv.ParseBool(asOfStr)
+	if err != nil {
+		return false, errors.New("invalid asOf")
+	}
+
+	return asOf, nil
+}
@@ -3,6 +3,9 @@ package block
 import (
 	"context"
 	"errors"
+	"eth-graph-api/internal/meta"
+	"eth-graph-api/pkg/blocktime"
+	"eth-graph-api/pkg/paging"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
@@ -12,9 +15,79 @@ type MockRepository struct {
 	mock.Mock
 }
 
-func (m *MockRepository) GetSwapsByBlock(ctx context.Context, block int, first int) ([]Swap, error) {
-	args := m.Called(ctx, block, first)
-	return args.Get(0).([]Swap), args.Error(1)
+func (m *MockRepository) GetSwapsByBlock(ctx context.Context, block int, asOf bool, limit int, cursor string) ([]Swap, string, error) {
+	args := m.Called(ctx, block, asOf, limit, cursor)
+	return args.Get(0).([]Swap), args.String(1), args.Error(2)
+}
+
+func (m *MockRepository) GetSwaps(ctx context.Context, filter SwapsFilter) ([]Swap, string, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Swap), args.String(1), args.Error(2)
+}
+
+func (m *MockRepository) GetMintsByBlock(ctx context.Context, block int, limit int, cursor string) ([]Mint, string, error) {
+	args := m.Called(ctx, block, limit, cursor)
+	return args.Get(0).([]Mint), args.String(1), args.Error(2)
+}
+
+func (m *MockRepository) GetBurnsByBlock(ctx context.Context, block int, limit int, cursor string) ([]Burn, string, error) {
+	args := m.Called(ctx, block, limit, cursor)
+	return args.Get(0).([]Burn), args.String(1), args.Error(2)
+}
+
+func (m *MockRepository) GetCollectsByBlock(ctx context.Context, block int, limit int, cursor string) ([]Collect, string, error) {
+	args := m.Called(ctx, block, limit, cursor)
+	return args.Get(0).([]Collect), args.String(1), args.Error(2)
+}
+
+func (m *MockRepository) GetSwapsByPool(ctx context.Context, filter EventsFilter) ([]Swap, string, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Swap), args.String(1), args.Error(2)
+}
+
+func (m *MockRepository) GetMintsByPool(ctx context.Context, filter EventsFilter) ([]Mint, string, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Mint), args.String(1), args.Error(2)
+}
+
+func (m *MockRepository) GetBurnsByPool(ctx context.Context, filter EventsFilter) ([]Burn, string, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Burn), args.String(1), args.Error(2)
+}
+
+func (m *MockRepository) GetCollectsByPool(ctx context.Context, filter EventsFilter) ([]Collect, string, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]Collect), args.String(1), args.Error(2)
+}
+
+type MockIndexingStatus struct {
+	mock.Mock
+}
+
+func (m *MockIndexingStatus) GetMetaService(ctx context.Context) (*meta.Meta, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*meta.Meta), args.Error(1)
+}
+
+// indexedAt returns an IndexingStatus telling that the subgraph is at the block `number`.
+func indexedAt(number int) *MockIndexingStatus {
+	status := new(MockIndexingStatus)
+	status.On("GetMetaService", mock.Anything).Return(&meta.Meta{Block: meta.Block{Number: number}}, nil)
+	return status
+}
+
+type MockResolver struct {
+	mock.Mock
+}
+
+func (m *MockResolver) BlockAt(ctx context.Context, timestamp int64) (*blocktime.Block, error) {
+	args := m.Called(ctx, timestamp)
+	return args.Get(0).(*blocktime.Block), args.Error(1)
+}
+
+func (m *MockResolver) TimeOf(ctx context.Context, number int64) (*blocktime.Block, error) {
+	args := m.Called(ctx, number)
+	return args.Get(0).(*blocktime.Block), args.Error(1)
 }
 
 func TestGetSwapsByBlockService(t *testing.T) {
@@ -61,17 +134,17 @@ func TestGetSwapsByBlockService(t *testing.T) {
 			mockRepo := new(MockRepository)
 
 			if !test.expectingError || (test.expectingError && test.mockRepoErr != nil) {
-				mockRepo.On("GetSwapsByBlock", mock.Anything, mock.Anything, mock.Anything).Return(test.mockRepoOutput, test.mockRepoErr).Once()
+				mockRepo.On("GetSwapsByBlock", mock.Anything, 1234, false, 10, "").Return(test.mockRepoOutput, "", test.mockRepoErr).Once()
 			}
 
-			svc := NewBlockService(mockRepo)
-			output, err := svc.GetSwapsByBlockService(context.Background(), test.blockStr, test.firstStr)
+			svc := NewBlockService(mockRepo, nil, nil)
+			output, err := svc.GetSwapsByBlockService(context.Background(), test.blockStr, SwapsParams{Limit: test.firstStr})
 
 			if test.expectingError {
 				assert.Error(t, err)
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, test.expectedOutput, output)
+				assert.Equal(t, test.expectedOutput, output.Data)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -79,42 +152,123 @@ func TestGetSwapsByBlockService(t *testing.T) {
 	}
 }
 
+func TestGetSwapsByBlockServiceCursor(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetSwapsByBlock", mock.Anything, 1234, false, 1, "0xa#1").Return([]Swap{{ID: "0xb#2"}}, "0xb#2", nil).Once()
+
+	svc := NewBlockService(mockRepo, nil, nil)
+	output, err := svc.GetSwapsByBlockService(context.Background(), "1234", SwapsParams{Limit: "1", Cursor: paging.EncodeCursor("0xa#1")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, paging.EncodeCursor("0xb#2"), output.NextCursor)
+	assert.True(t, output.HasMore)
+
+	_, err = svc.GetSwapsByBlockService(context.Background(), "1234", SwapsParams{Limit: "1", Cursor: "0xa#1"})
+	assert.EqualError(t, err, "invalid cursor")
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetSwapsByBlockServiceAsOf(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetSwapsByBlock", mock.Anything, 1234, true, 5, "").Return([]Swap{{ID: "0xb#2"}}, "", nil).Once()
+
+	svc := NewBlockService(mockRepo, nil, nil)
+	output, err := svc.GetSwapsByBlockService(context.Background(), "1234", SwapsParams{Limit: "5", AsOf: "true"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Swap{{ID: "0xb#2"}}, output.Data)
+
+	_, err = svc.GetSwapsByBlockService(context.Background(), "1234", SwapsParams{Limit: "5", AsOf: "sometimes"})
+	assert.EqualError(t, err, "invalid asOf")
+
+	mockRepo.AssertExpectations(t)
+}
+
 func TestGetSwappedTokensByBlockService(t *testing.T) {
+	usdc := Token{ID: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Symbol: "USDC", Decimals: "6"}
+	fakeUSDC := Token{ID: "0x0000000000000000000000000000000000000bad", Symbol: "USDC", Decimals: "18"}
+	weth := Token{ID: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", Symbol: "WETH", Decimals: "18"}
+
+	swaps := []Swap{
+		{ID: "0xa#1", Pool: Pool{Token0: usdc, Token1: weth}, Amount0: "-1600.5", Amount1: "1", AmountUSD: "1600"},
+		{ID: "0xb#2", Pool: Pool{Token0: fakeUSDC, Token1: weth}, Amount0: "100", Amount1: "-0.001", AmountUSD: "1.5"},
+		{ID: "0xc#3", Pool: Pool{Token0: usdc, Token1: weth}, Amount0: "800", Amount1: "-0.5", AmountUSD: "800"},
+	}
+
+	all := []SwappedToken{
+		{ID: usdc.ID, Symbol: "USDC", Decimals: "6", SwapCount: 2, AmountIn: "800.0000000000", AmountOut: "1600.5000000000", VolumeUSD: "2400.0000000000"},
+		{ID: weth.ID, Symbol: "WETH", Decimals: "18", SwapCount: 3, AmountIn: "1.0000000000", AmountOut: "0.5010000000", VolumeUSD: "2401.5000000000"},
+		{ID: fakeUSDC.ID, Symbol: "USDC", Decimals: "18", SwapCount: 1, AmountIn: "100.0000000000", AmountOut: "0.0000000000", VolumeUSD: "1.5000000000"},
+	}
+
 	tests := []struct {
 		name           string
 		blockStr       string
-		firstStr       string
+		params         SwapsParams
 		mockRepoOutput []Swap
+		mockRepoNext   string
 		mockRepoErr    error
-		expectedOutput []Token
-		expectingError bool
+		expectedOutput *paging.Page[SwappedToken]
+		expectedErr    string
 	}{
 		{
-			name:           "valid input",
+			name:           "all tokens",
 			blockStr:       "1234",
-			firstStr:       "10",
-			mockRepoOutput: []Swap{{ID: "1", Pool: Pool{Token0: Token{Symbol: "AAA"}, Token1: Token{Symbol: "BBB"}}}},
-			mockRepoErr:    nil,
-			expectedOutput: []Token{{Symbol: "AAA"}, {Symbol: "BBB"}},
-			expectingError: false,
+			params:         SwapsParams{Limit: "10"},
+			mockRepoOutput: swaps,
+			expectedOutput: paging.NewPage(all, ""),
 		},
 		{
-			name:           "invalid block",
-			blockStr:       "invalid",
-			firstStr:       "10",
-			mockRepoOutput: nil,
-			mockRepoErr:    nil,
-			expectedOutput: nil,
-			expectingError: true,
+			name:           "first page",
+			blockStr:       "1234",
+			params:         SwapsParams{Limit: "2"},
+			mockRepoOutput: swaps,
+			expectedOutput: paging.NewPage(all[:2], weth.ID),
+		},
+		{
+			name:           "last page",
+			blockStr:       "1234",
+			params:         SwapsParams{Limit: "2", Cursor: paging.EncodeCursor(weth.ID)},
+			mockRepoOutput: swaps,
+			expectedOutput: paging.NewPage(all[2:], ""),
+		},
+		{
+			name:           "unknown cursor",
+			blockStr:       "1234",
+			params:         SwapsParams{Limit: "2", Cursor: paging.EncodeCursor("0xunknown")},
+			mockRepoOutput: swaps,
+			expectedErr:    "invalid cursor",
+		},
+		{
+			name:        "invalid block",
+			blockStr:    "invalid",
+			params:      SwapsParams{Limit: "10"},
+			expectedErr: "invalid block",
+		},
+		{
+			name:           "too many swaps",
+			blockStr:       "1234",
+			params:         SwapsParams{Limit: "10"},
+			mockRepoOutput: swaps,
+			mockRepoNext:   "3,0xc#3",
+			expectedErr:    "too many swaps in block",
 		},
 		{
 			name:           "repo returns error",
 			blockStr:       "1234",
-			firstStr:       "10",
+			params:         SwapsParams{Limit: "10"},
 			mockRepoOutput: nil,
 			mockRepoErr:    errors.New("some error"),
-			expectedOutput: nil,
-			expectingError: true,
+			expectedErr:    "issue to get swaps",
+		},
+		{
+			name:           "repo times out",
+			blockStr:       "1234",
+			params:         SwapsParams{Limit: "10"},
+			mockRepoOutput: nil,
+			mockRepoErr:    context.DeadlineExceeded,
+			expectedErr:    context.DeadlineExceeded.Error(),
 		},
 	}
 
@@ -122,18 +276,440 @@ func TestGetSwappedTokensByBlockService(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			mockRepo := new(MockRepository)
 
-			if !test.expectingError || (test.expectingError && test.mockRepoErr != nil) {
-				mockRepo.On("GetSwapsByBlock", mock.Anything, mock.Anything, mock.Anything).Return(test.mockRepoOutput, test.mockRepoErr).Once()
+			if test.blockStr == "1234" {
+				mockRepo.On("GetSwapsByBlock", mock.Anything, 1234, false, paging.MaxItems, "").
+					Return(test.mockRepoOutput, test.mockRepoNext, test.mockRepoErr).Once()
 			}
 
-			svc := NewBlockService(mockRepo)
-			output, err := svc.GetSwappedTokensByBlockService(context.Background(), test.blockStr, test.firstStr)
+			svc := NewBlockService(mockRepo, nil, nil)
+			output, err := svc.GetSwappedTokensByBlockService(context.Background(), test.blockStr, test.params)
 
-			if test.expectingError {
-				assert.Error(t, err)
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 1234, output.Block)
+				assert.Equal(t, test.expectedOutput, &output.Page)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestAggregateSwappedTokensPrecision(t *testing.T) {
+	weth := Token{ID: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", Symbol: "WETH", Decimals: "18"}
+	usdc := Token{ID: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Symbol: "USDC", Decimals: "6"}
+
+	swaps := []Swap{
+		{Pool: Pool{Token0: usdc, Token1: weth}, Amount0: "-1600.5", Amount1: "1234567.000000000000000001", AmountUSD: "1600"},
+		{Pool: Pool{Token0: usdc, Token1: weth}, Amount0: "-800", Amount1: "0.000000000000000002", AmountUSD: "800"},
+	}
+
+	tokens, err := aggregateSwappedTokens(swaps)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1234567.000000000000000003", tokens[1].AmountIn)
+}
+
+func TestGetSwapsByBlockServiceTags(t *testing.T) {
+	tests := []struct {
+		name          string
+		blockStr      string
+		expectedBlock int
+		expectedErr   string
+	}{
+		{name: "latest", blockStr: "latest", expectedBlock: 18319881},
+		{name: "latest with offset", blockStr: "latest-10", expectedBlock: 18319871},
+		{name: "finalized", blockStr: "finalized", expectedBlock: 18319817},
+		{name: "before the first block", blockStr: "latest-18319881", expectedErr: "invalid block, it is before the first block"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			if test.expectedErr == "" {
+				mockRepo.On("GetSwapsByBlock", mock.Anything, test.expectedBlock, false, 5, "").Return([]Swap{{ID: "1"}}, "", nil).Once()
+			}
+
+			svc := NewBlockService(mockRepo, nil, indexedAt(18319881))
+			output, err := svc.GetSwapsByBlockService(context.Background(), test.blockStr, SwapsParams{Limit: "5"})
+
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedBlock, output.Block)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetSwapsByBlockRangeService(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+
+	tests := []struct {
+		name         string
+		params       RangeParams
+		expectedCall *SwapsFilter
+		expectedErr  string
+	}{
+		{
+			name:   "valid range with filters",
+			params: RangeParams{From: "18319800", To: "18319881", Pool: "0x88E6A0c2dDD26FEEb64F039a2c41296FcB3f5640", Limit: "20", Cursor: paging.EncodeCursor("1697031095,0xa#1")},
+			expectedCall: &SwapsFilter{
+				FromBlock: 18319800,
+				ToBlock:   18319881,
+				Pool:      pool,
+				Limit:     20,
+				Cursor:    "1697031095,0xa#1",
+			},
+		},
+		{
+			name:        "range too wide",
+			params:      RangeParams{From: "18309881", To: "18319881", Limit: "5"},
+			expectedErr: "invalid block range, it may span up to 1000 blocks",
+		},
+		{
+			name:        "reversed range",
+			params:      RangeParams{From: "18319881", To: "18319800", Limit: "5"},
+			expectedErr: "invalid block range, it may span up to 1000 blocks",
+		},
+		{
+			name:        "invalid pool",
+			params:      RangeParams{From: "18319800", To: "18319881", Pool: "pool", Limit: "5"},
+			expectedErr: "invalid pool",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			if test.expectedCall != nil {
+				mockRepo.On("GetSwaps", mock.Anything, *test.expectedCall).Return([]Swap{{ID: "0xb#2"}}, "", nil).Once()
+			}
+
+			svc := NewBlockService(mockRepo, nil, nil)
+			output, err := svc.GetSwapsByBlockRangeService(context.Background(), test.params)
+
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []Swap{{ID: "0xb#2"}}, output.Data)
+				assert.False(t, output.HasMore)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetSwapsByTimeRangeService(t *testing.T) {
+	token := "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetSwaps", mock.Anything, SwapsFilter{From: 1697030000, To: 1697031000, Token: token, Limit: 5}).
+		Return([]Swap{{ID: "0xb#2"}}, "1697030500,0xb#2", nil).Once()
+
+	svc := NewBlockService(mockRepo, nil, nil)
+	output, err := svc.GetSwapsByTimeRangeService(context.Background(), RangeParams{From: "1697030000", To: "1697031000", Token: token, Limit: "5"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, paging.EncodeCursor("1697030500,0xb#2"), output.NextCursor)
+
+	_, err = svc.GetSwapsByTimeRangeService(context.Background(), RangeParams{From: "1697000000", To: "1697031000", Limit: "5"})
+	assert.EqualError(t, err, "invalid range, it may be up to 10800 seconds long")
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetBlockSummaryService(t *testing.T) {
+	usdc := Token{ID: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Symbol: "USDC", Decimals: "6"}
+	weth := Token{ID: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", Symbol: "WETH", Decimals: "18"}
+	dai := Token{ID: "0x6b175474e89094c44da98b954eedeac495271d0f", Symbol: "DAI", Decimals: "18"}
+
+	usdcWeth := Pool{ID: "0xpool1", Token0: usdc, Token1: weth}
+	daiWeth := Pool{ID: "0xpool2", Token0: dai, Token1: weth}
+	daiUsdc := Pool{ID: "0xpool3", Token0: dai, Token1: usdc}
+
+	t.Run("summary of a busy block", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("GetSwapsByBlock", mock.Anything, 1234, false, paging.MaxItems, "").Return([]Swap{
+			{ID: "0xa#1", Timestamp: "1697031095", Pool: usdcWeth, AmountUSD: "100"},
+			{ID: "0xb#2", Timestamp: "1697031095", Pool: daiWeth, AmountUSD: "250.5"},
+			{ID: "0xc#3", Timestamp: "1697031095", Pool: usdcWeth, AmountUSD: "200"},
+		}, "", nil).Once()
+		mockRepo.On("GetMintsByBlock", mock.Anything, 1234, paging.MaxItems, "").Return([]Mint{
+			{ID: "0xd#4", Timestamp: "1697031095", Pool: daiUsdc},
+		}, "", nil).Once()
+		mockRepo.On("GetBurnsByBlock", mock.Anything, 1234, paging.MaxItems, "").Return([]Burn{}, "", nil).Once()
+		mockRepo.On("GetCollectsByBlock", mock.Anything, 1234, paging.MaxItems, "").Return([]Collect{
+			{ID: "0xe#5", Timestamp: "1697031095", Pool: usdcWeth},
+		}, "", nil).Once()
+
+		svc := NewBlockService(mockRepo, nil, nil)
+		summary, err := svc.GetBlockSummaryService(context.Background(), "1234")
+
+		assert.NoError(t, err)
+		assert.Equal(t, &BlockSummary{
+			Block:        1234,
+			Timestamp:    "1697031095",
+			SwapCount:    3,
+			MintCount:    1,
+			BurnCount:    0,
+			CollectCount: 1,
+			VolumeUSD:    "550.5000000000",
+			PoolCount:    3,
+			TokenCount:   3,
+			TopPools: []PoolVolume{
+				{ID: "0xpool1", Token0: usdc, Token1: weth, SwapCount: 2, VolumeUSD: "300.0000000000"},
+				{ID: "0xpool2", Token0: dai, Token1: weth, SwapCount: 1, VolumeUSD: "250.5000000000"},
+			},
+		}, summary)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("too many swaps", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("GetSwapsByBlock", mock.Anything, 1234, false, paging.MaxItems, "").Return([]Swap{{ID: "0xa#1"}}, "1,0xa#1", nil).Once()
+		mockRepo.On("GetMintsByBlock", mock.Anything, 1234, paging.MaxItems, "").Return([]Mint{}, "", nil).Maybe()
+		mockRepo.On("GetBurnsByBlock", mock.Anything, 1234, paging.MaxItems, "").Return([]Burn{}, "", nil).Maybe()
+		mockRepo.On("GetCollectsByBlock", mock.Anything, 1234, paging.MaxItems, "").Return([]Collect{}, "", nil).Maybe()
+
+		svc := NewBlockService(mockRepo, nil, nil)
+		_, err := svc.GetBlockSummaryService(context.Background(), "1234")
+
+		assert.EqualError(t, err, "too many swaps in block")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("repository errors", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			repoErr     error
+			expectedErr error
+		}{
+			{name: "client error", repoErr: errors.New("graphql: unexpected field"), expectedErr: errors.New("issue to get block events")},
+			{name: "timeout", repoErr: context.DeadlineExceeded, expectedErr: context.DeadlineExceeded},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				mockRepo := new(MockRepository)
+				mockRepo.On("GetSwapsByBlock", mock.Anything, 1234, false, paging.MaxItems, "").Return([]Swap{}, "", nil).Maybe()
+				mockRepo.On("GetMintsByBlock", mock.Anything, 1234, paging.MaxItems, "").Return([]Mint(nil), "", test.repoErr).Once()
+				mockRepo.On("GetBurnsByBlock", mock.Anything, 1234, paging.MaxItems, "").Return([]Burn{}, "", nil).Maybe()
+				mockRepo.On("GetCollectsByBlock", mock.Anything, 1234, paging.MaxItems, "").Return([]Collect{}, "", nil).Maybe()
+
+				svc := NewBlockService(mockRepo, nil, nil)
+				_, err := svc.GetBlockSummaryService(context.Background(), "1234")
+
+				assert.EqualError(t, err, test.expectedErr.Error())
+				mockRepo.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("quiet block", func(t *testing.T) {
+		mockRepo := new(MockRepository)
+		mockRepo.On("GetSwapsByBlock", mock.Anything, 1234, false, paging.MaxItems, "").Return([]Swap{}, "", nil).Once()
+		mockRepo.On("GetMintsByBlock", mock.Anything, 1234, paging.MaxItems, "").Return([]Mint{}, "", nil).Once()
+		mockRepo.On("GetBurnsByBlock", mock.Anything, 1234, paging.MaxItems, "").Return([]Burn{}, "", nil).Once()
+		mockRepo.On("GetCollectsByBlock", mock.Anything, 1234, paging.MaxItems, "").Return([]Collect{}, "", nil).Once()
+
+		mockResolver := new(MockResolver)
+		mockResolver.On("TimeOf", mock.Anything, int64(1234)).Return(&blocktime.Block{Number: 1234, Timestamp: 1697031107}, nil).Once()
+
+		svc := NewBlockService(mockRepo, mockResolver, nil)
+		summary, err := svc.GetBlockSummaryService(context.Background(), "1234")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1697031107", summary.Timestamp)
+		assert.Equal(t, 0, summary.SwapCount)
+
+		mockRepo.AssertExpectations(t)
+		mockResolver.AssertExpectations(t)
+	})
+
+	t.Run("invalid block", func(t *testing.T) {
+		svc := NewBlockService(new(MockRepository), nil, nil)
+		_, err := svc.GetBlockSummaryService(context.Background(), "-1")
+
+		assert.EqualError(t, err, "invalid block")
+	})
+}
+
+func TestPoolVolumesOrder(t *testing.T) {
+	swaps := []Swap{
+		{Pool: Pool{ID: "0xb"}, AmountUSD: "9.5"},
+		{Pool: Pool{ID: "0xc"}, AmountUSD: "10"},
+		{Pool: Pool{ID: "0xa"}, AmountUSD: "9.5"},
+	}
+
+	volumes, total, err := poolVolumes(swaps)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "29.0000000000", total)
+	assert.Equal(t, []string{"0xc", "0xa", "0xb"}, []string{volumes[0].ID, volumes[1].ID, volumes[2].ID})
+}
+
+func TestGetBlockByTimeService(t *testing.T) {
+	mockResolver := new(MockResolver)
+	mockResolver.On("BlockAt", mock.Anything, int64(1697031100)).
+		Return(&blocktime.Block{Number: 18319881, Timestamp: 1697031095, Exact: true}, nil).Once()
+
+	svc := NewBlockService(new(MockRepository), mockResolver, nil)
+	block, err := svc.GetBlockByTimeService(context.Background(), "1697031100")
+
+	assert.NoError(t, err)
+	assert.Equal(t, &blocktime.Block{Number: 18319881, Timestamp: 1697031095, Exact: true}, block)
+
+	_, err = svc.GetBlockByTimeService(context.Background(), "yesterday")
+	assert.EqualError(t, err, "invalid timestamp")
+
+	mockResolver.AssertExpectations(t)
+}
+
+func TestGetBlockTimeService(t *testing.T) {
+	mockResolver := new(MockResolver)
+	mockResolver.On("TimeOf", mock.Anything, int64(18319883)).Return((*blocktime.Block)(nil), blocktime.ErrNotFound).Once()
+
+	svc := NewBlockService(new(MockRepository), mockResolver, nil)
+	_, err := svc.GetBlockTimeService(context.Background(), "18319883")
+
+	assert.ErrorIs(t, err, blocktime.ErrNotFound)
+
+	_, err = svc.GetBlockTimeService(context.Background(), "0")
+	assert.EqualError(t, err, "invalid block")
+
+	mockResolver.AssertExpectations(t)
+}
+
+func TestGetMintsByBlockService(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetMintsByBlock", mock.Anything, 18319881, 1, "").Return([]Mint{{ID: "0xa#1"}}, "1,0xa#1", nil).Once()
+
+	svc := NewBlockService(mockRepo, nil, indexedAt(18319881))
+	output, err := svc.GetMintsByBlockService(context.Background(), "latest", EventsParams{Limit: "1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 18319881, output.Block)
+	assert.Equal(t, []Mint{{ID: "0xa#1"}}, output.Data)
+	assert.Equal(t, paging.EncodeCursor("1,0xa#1"), output.NextCursor)
+
+	_, err = svc.GetBurnsByBlockService(context.Background(), "invalid", EventsParams{Limit: "1"})
+	assert.EqualError(t, err, "invalid block")
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetEventsByPoolService(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+
+	tests := []struct {
+		name           string
+		pool           string
+		params         EventsParams
+		expectedFilter *EventsFilter
+		expectedErr    string
+	}{
+		{
+			name:           "all events",
+			pool:           "0x88E6A0c2dDD26FEEb64F039a2c41296FcB3f5640",
+			params:         EventsParams{Limit: "10"},
+			expectedFilter: &EventsFilter{Pool: pool, Limit: 10},
+		},
+		{
+			name:           "time range",
+			pool:           pool,
+			params:         EventsParams{From: "1697030000", To: "1697031000", Limit: "5", Cursor: paging.EncodeCursor("1697030500,0xb#2")},
+			expectedFilter: &EventsFilter{Pool: pool, From: 1697030000, To: 1697031000, Limit: 5, Cursor: "1697030500,0xb#2"},
+		},
+		{
+			name:        "range without end",
+			pool:        pool,
+			params:      EventsParams{From: "1697030000", Limit: "5"},
+			expectedErr: "invalid range",
+		},
+		{
+			name:        "invalid pool",
+			pool:        "invalid",
+			params:      EventsParams{Limit: "5"},
+			expectedErr: "invalid pool",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			if test.expectedFilter != nil {
+				mockRepo.On("GetCollectsByPool", mock.Anything, *test.expectedFilter).Return([]Collect{{ID: "0xb#3"}}, "", nil).Once()
+			}
+
+			svc := NewBlockService(mockRepo, nil, nil)
+			output, err := svc.GetCollectsByPoolService(context.Background(), test.pool, test.params)
+
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []Collect{{ID: "0xb#3"}}, output.Data)
+				assert.False(t, output.HasMore)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetSwapsByPoolService(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+	sender := "0x3fc91a3afd70395cd496c647d5a6cc9d4b2b7fad"
+
+	tests := []struct {
+		name           string
+		params         EventsParams
+		expectedFilter *EventsFilter
+		expectedErr    string
+	}{
+		{
+			name:           "size and sender",
+			params:         EventsParams{From: "1697030000", To: "1697031000", MinUSD: "100000.5", Sender: "0x3fC91A3afd70395Cd496C647d5a6CC9D4B2b7FAD", Limit: "5"},
+			expectedFilter: &EventsFilter{Pool: pool, From: 1697030000, To: 1697031000, MinUSD: "100000.5", Sender: sender, Limit: 5},
+		},
+		{
+			name:        "invalid minUSD",
+			params:      EventsParams{MinUSD: "-1", Limit: "5"},
+			expectedErr: "invalid minUSD",
+		},
+		{
+			name:        "invalid sender",
+			params:      EventsParams{Sender: "0x3fc9", Limit: "5"},
+			expectedErr: "invalid sender",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			if test.expectedFilter != nil {
+				mockRepo.On("GetSwapsByPool", mock.Anything, *test.expectedFilter).Return([]Swap{{ID: "0xa#1"}}, "1697030600,12", nil).Once()
+			}
+
+			svc := NewBlockService(mockRepo, nil, nil)
+			output, err := svc.GetSwapsByPoolService(context.Background(), pool, test.params)
+
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
 			} else {
 				assert.NoError(t, err)
-				assert.ElementsMatch(t, test.expectedOutput, output)
+				assert.Equal(t, []Swap{{ID: "0xa#1"}}, output.Data)
+				assert.True(t, output.HasMore)
+				assert.Equal(t, paging.EncodeCursor("1697030600,12"), output.NextCursor)
 			}
 
 			mockRepo.AssertExpectations(t)
@@ -0,0 +1,110 @@
+package graph
+
+type Token struct {
+	ID       string `json:"id" graphql:"id"`
+	Symbol   string `json:"symbol" graphql:"symbol"`
+	Decimals string `json:"decimals" graphql:"decimals"`
+}
+
+// Pool is a pool of the token graph, an edge between its two tokens, along with the state its swaps are estimated from:
+// the price, tick and active liquidity, and the amounts of the tokens it holds (TotalValueLockedToken0 and
+// TotalValueLockedToken1, in token units)
+type Pool struct {
+	ID                     string `json:"id" graphql:"id"`
+	FeeTier                string `json:"feeTier" graphql:"feeTier"`
+	Token0                 Token  `json:"token0" graphql:"token0"`
+	Token1                 Token  `json:"token1" graphql:"token1"`
+	Liquidity              string `json:"liquidity" graphql:"liquidity"`
+	SqrtPrice              string `json:"sqrtPrice" graphql:"sqrtPrice"`
+	Tick                   string `json:"tick" graphql:"tick"`
+	TotalValueLockedUSD    string `json:"totalValueLockedUSD" graphql:"totalValueLockedUSD"`
+	TotalValueLockedToken0 string `json:"totalValueLockedToken0" graphql:"totalValueLockedToken0"`
+	TotalValueLockedToken1 string `json:"totalValueLockedToken1" graphql:"totalValueLockedToken1"`
+}
+
+// Hop is a single swap of a route, of AmountIn of TokenIn for AmountOut of TokenOut through the Pool, in token units
+type Hop struct {
+	Pool      string `json:"pool"`
+	FeeTier   string `json:"feeTier"`
+	TokenIn   Token  `json:"tokenIn"`
+	TokenOut  Token  `json:"tokenOut"`
+	AmountIn  string `json:"amountIn"`
+	AmountOut string `json:"amountOut"`
+}
+
+// Route is a path of swaps from the token in to the token out, each hop swapping the output of the previous one,
+// AmountOut being the estimated output of the last hop
+type Route struct {
+	Hops      []Hop  `json:"hops"`
+	AmountOut string `json:"amountOut"`
+}
+
+// Routes are the best routes swapping AmountIn of TokenIn for TokenOut, from the highest output,
+// found in the token graph as of Block
+type Routes struct {
+	Block    int     `json:"block"`
+	TokenIn  Token   `json:"tokenIn"`
+	TokenOut Token   `json:"tokenOut"`
+	AmountIn string  `json:"amountIn"`
+	Routes   []Route `json:"routes"`
+}
+
+// CycleHop is a swap of a cycle, of TokenIn for TokenOut through the Pool at its Price, the amount of TokenOut
+// one TokenIn is worth, before fees
+type CycleHop struct {
+	Pool     string `json:"pool"`
+	FeeTier  string `json:"feeTier"`
+	TokenIn  Token  `json:"tokenIn"`
+	TokenOut Token  `json:"tokenOut"`
+	Price    string `json:"price"`
+}
+
+// Cycle is a sequence of swaps ending with the token it starts from, whose prices are inconsistent: their product
+// exceeds 1 by GrossProfit, the fraction of the amount swapped it would yield before gas and fees
+type Cycle struct {
+	Hops        []CycleHop `json:"hops"`
+	GrossProfit string     `json:"grossProfit"`
+}
+
+// Cycles are the price-inconsistency cycles found among the pools with a TVL of at least MinTvlUSD
+// in the token graph as of Block, from the highest gross profit
+type Cycles struct {
+	Block     int     `json:"block"`
+	MinTvlUSD string  `json:"minTvlUSD"`
+	Cycles    []Cycle `json:"cycles"`
+}
+
+// TokenCentrality tells how central a token is in the token graph: its Degree is the number of its pools,
+// its LiquidityWeightedDegree the TVL of its pools in USD, and its PageRank its share of the stationary distribution
+// of a walk through the graph, where each pool is taken in proportion to its TVL
+type TokenCentrality struct {
+	Token                   Token   `json:"token"`
+	Degree                  int     `json:"degree"`
+	LiquidityWeightedDegree string  `json:"liquidityWeightedDegree"`
+	PageRank                float64 `json:"pageRank"`
+}
+
+// Centrality ranks the tokens of the token graph as of Block by OrderBy, from the most central
+type Centrality struct {
+	Block   int               `json:"block"`
+	OrderBy string            `json:"orderBy"`
+	Tokens  []TokenCentrality `json:"tokens"`
+}
+
+// Component is a connected component of the token graph: a set of tokens swappable for one another
+// through its pools, whose TVL totals TotalValueLockedUSD
+type Component struct {
+	Size                int     `json:"size"`
+	Pools               int     `json:"pools"`
+	TotalValueLockedUSD string  `json:"totalValueLockedUSD"`
+	Tokens              []Token `json:"tokens"`
+}
+
+// Components are the connected components of the token graph as of Block, from the largest,
+// Isolated being the tokens holding a TVL of at least MinTvlUSD across their pools, but with no pool in the graph,
+// so that they cannot be swapped through it
+type Components struct {
+	Block      int         `json:"block"`
+	Components []Component `json:"components"`
+	Isolated   []Token     `json:"isolated"`
+}
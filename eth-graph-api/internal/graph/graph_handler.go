@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"eth-graph-api/internal/meta"
+	jh "eth-graph-api/pkg/json_helper"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Handler is a struct that contains a Service which provides
+// methods for querying the token graph.
+type Handler struct {
+	GraphService Service
+}
+
+// GetRoutesHandler is an HTTP handler function that finds the best routes swapping an amount of a token for another.
+// It extracts 'tokenIn', 'tokenOut', 'amountIn' (in token units) and 'maxHops' from the query,
+// then utilizes GraphService to search the token graph and respond with the routes,
+// a 404 if a token is not in the graph, a 503 until the graph is loaded, or handle errors appropriately.
+func (h *Handler) GetRoutesHandler(w http.ResponseWriter, r *http.Request) {
+
+	queryParams := r.URL.Query()
+	tokenIn := queryParams.Get("tokenIn")
+	tokenOut := queryParams.Get("tokenOut")
+	amountIn := queryParams.Get("amountIn")
+	maxHops := queryParams.Get("maxHops")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	routes, err := h.GraphService.GetRoutesService(ctx, tokenIn, tokenOut, amountIn, maxHops)
+	writeResult(w, routes, err)
+}
+
+// GetCyclesHandler is an HTTP handler function that lists the price-inconsistency cycles of the token graph,
+// sequences of swaps whose prices multiply to more than 1. It extracts the optional 'minProfit' from the query,
+// then utilizes GraphService to respond with the cycles, a 503 until the graph is loaded,
+// or handle errors appropriately.
+func (h *Handler) GetCyclesHandler(w http.ResponseWriter, r *http.Request) {
+
+	minProfit := r.URL.Query().Get("minProfit")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	cycles, err := h.GraphService.GetCyclesService(ctx, minProfit)
+	writeResult(w, cycles, err)
+}
+
+// GetCentralityHandler is an HTTP handler function that ranks the tokens of the token graph by centrality,
+// telling which ones are its hubs. It extracts the optional 'orderBy' ("pageRank", the default, "degree" or "liquidity")
+// and 'limit' from the query, then utilizes GraphService to respond with the ranking, a 503 until the graph is loaded,
+// or handle errors appropriately.
+func (h *Handler) GetCentralityHandler(w http.ResponseWriter, r *http.Request) {
+
+	queryParams := r.URL.Query()
+	orderBy := queryParams.Get("orderBy")
+	limit := queryParams.Get("limit")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	centrality, err := h.GraphService.GetCentralityService(ctx, orderBy, limit)
+	writeResult(w, centrality, err)
+}
+
+// GetComponentsHandler is an HTTP handler function that lists the connected components of the token graph
+// and the isolated tokens. It utilizes GraphService to respond with the components,
+// a 503 until the graph is loaded, or handle errors appropriately.
+func (h *Handler) GetComponentsHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	components, err := h.GraphService.GetComponentsService(ctx)
+	writeResult(w, components, err)
+}
+
+// snapshotResult is a result computed over a snapshot of the token graph, telling the block of the snapshot.
+type snapshotResult interface {
+	snapshotBlock() int
+}
+
+func (r *Routes) snapshotBlock() int     { return r.Block }
+func (c *Cycles) snapshotBlock() int     { return c.Block }
+func (c *Centrality) snapshotBlock() int { return c.Block }
+func (c *Components) snapshotBlock() int { return c.Block }
+
+// writeResult writes a result computed over the token graph, or the error that occurred while computing it,
+// as JSON to the HTTP response. The graph not being loaded yet is answered with 503 Service Unavailable.
+// The result tells the block of its snapshot in the meta.IndexedBlockHeader, as it was computed from that block
+// rather than from the latest one indexed by the subgraph.
+func writeResult[T snapshotResult](w http.ResponseWriter, result T, err error) {
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else if errors.Is(err, ErrGraphNotReady) {
+			err = jh.ErrorJSON(w, err, http.StatusServiceUnavailable)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else if errors.Is(err, ErrTokenNotFound) {
+			err = jh.ErrorJSON(w, err, http.StatusNotFound)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else {
+			err = jh.ErrorJSON(w, err, http.StatusBadRequest)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	header := http.Header{}
+	header.Set(meta.IndexedBlockHeader, strconv.Itoa(result.snapshotBlock()))
+
+	err = jh.WriteJSON(w, http.StatusOK, result, header)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,270 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"eth-graph-api/internal/meta"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type MockGraphService struct {
+	mock.Mock
+}
+
+func (m *MockGraphService) Run(ctx context.Context, interval time.Duration) {
+	m.Called(ctx, interval)
+}
+
+func (m *MockGraphService) Refresh(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func (m *MockGraphService) GetRoutesService(ctx context.Context, tokenIn string, tokenOut string, amountInStr string, maxHopsStr string) (*Routes, error) {
+	args := m.Called(ctx, tokenIn, tokenOut, amountInStr, maxHopsStr)
+	return args.Get(0).(*Routes), args.Error(1)
+}
+
+func (m *MockGraphService) GetCyclesService(ctx context.Context, minProfitStr string) (*Cycles, error) {
+	args := m.Called(ctx, minProfitStr)
+	return args.Get(0).(*Cycles), args.Error(1)
+}
+
+func (m *MockGraphService) GetCentralityService(ctx context.Context, orderBy string, limitStr string) (*Centrality, error) {
+	args := m.Called(ctx, orderBy, limitStr)
+	return args.Get(0).(*Centrality), args.Error(1)
+}
+
+func (m *MockGraphService) GetComponentsService(ctx context.Context) (*Components, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*Components), args.Error(1)
+}
+
+func TestGetRoutesHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		maxHops        string
+		mockSvcOutput  *Routes
+		mockSvcErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "valid request",
+			url:            "/v1/routes?tokenIn=0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48&tokenOut=0x6b175474e89094c44da98b954eedeac495271d0f&amountIn=1000&maxHops=2",
+			maxHops:        "2",
+			mockSvcOutput:  &Routes{Block: 18319881, Routes: []Route{}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "token not in graph",
+			url:            "/v1/routes?tokenIn=0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48&tokenOut=0x0000000000000000000000000000000000000000&amountIn=1000",
+			mockSvcErr:     ErrTokenNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "graph not loaded",
+			url:            "/v1/routes?tokenIn=0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48&tokenOut=0x6b175474e89094c44da98b954eedeac495271d0f&amountIn=1000",
+			mockSvcErr:     ErrGraphNotReady,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "invalid amount",
+			url:            "/v1/routes?tokenIn=0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48&tokenOut=0x6b175474e89094c44da98b954eedeac495271d0f",
+			mockSvcErr:     errors.New("invalid amountIn"),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockGraphService)
+			mockSvc.On("GetRoutesService", mock.Anything, mock.Anything, mock.Anything, mock.Anything, test.maxHops).Return(test.mockSvcOutput, test.mockSvcErr).Once()
+
+			h := &Handler{
+				GraphService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Get("/v1/routes", h.GetRoutesHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+			if test.mockSvcOutput != nil {
+				assert.Equal(t, "18319881", rr.Header().Get(meta.IndexedBlockHeader))
+			}
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetCyclesHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		minProfit      string
+		mockSvcOutput  *Cycles
+		mockSvcErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "valid request",
+			url:            "/v1/analytics/cycles?minProfit=0.001",
+			minProfit:      "0.001",
+			mockSvcOutput:  &Cycles{Block: 18319881, Cycles: []Cycle{}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid minProfit",
+			url:            "/v1/analytics/cycles?minProfit=1%25",
+			minProfit:      "1%",
+			mockSvcErr:     errors.New("invalid minProfit"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "graph not loaded",
+			url:            "/v1/analytics/cycles",
+			mockSvcErr:     ErrGraphNotReady,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockGraphService)
+			mockSvc.On("GetCyclesService", mock.Anything, test.minProfit).Return(test.mockSvcOutput, test.mockSvcErr).Once()
+
+			h := &Handler{
+				GraphService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Get("/v1/analytics/cycles", h.GetCyclesHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetCentralityHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		orderBy        string
+		limit          string
+		mockSvcOutput  *Centrality
+		mockSvcErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "valid request",
+			url:            "/v1/analytics/graph/centrality?orderBy=degree&limit=10",
+			orderBy:        "degree",
+			limit:          "10",
+			mockSvcOutput:  &Centrality{Block: 18319881, OrderBy: "degree", Tokens: []TokenCentrality{}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid orderBy",
+			url:            "/v1/analytics/graph/centrality?orderBy=betweenness",
+			orderBy:        "betweenness",
+			mockSvcErr:     errors.New("invalid orderBy"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "graph not loaded",
+			url:            "/v1/analytics/graph/centrality",
+			mockSvcErr:     ErrGraphNotReady,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockGraphService)
+			mockSvc.On("GetCentralityService", mock.Anything, test.orderBy, test.limit).Return(test.mockSvcOutput, test.mockSvcErr).Once()
+
+			h := &Handler{
+				GraphService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Get("/v1/analytics/graph/centrality", h.GetCentralityHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetComponentsHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSvcOutput  *Components
+		mockSvcErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "valid request",
+			mockSvcOutput:  &Components{Block: 18319881, Components: []Component{}, Isolated: []Token{}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "graph not loaded",
+			mockSvcErr:     ErrGraphNotReady,
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "timeout",
+			mockSvcErr:     context.DeadlineExceeded,
+			expectedStatus: http.StatusRequestTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockGraphService)
+			mockSvc.On("GetComponentsService", mock.Anything).Return(test.mockSvcOutput, test.mockSvcErr).Once()
+
+			h := &Handler{
+				GraphService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, "/v1/analytics/graph/components", nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Get("/v1/analytics/graph/components", h.GetComponentsHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
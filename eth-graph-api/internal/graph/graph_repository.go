@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"context"
+	"eth-graph-api/pkg/logger"
+	"eth-graph-api/pkg/paging"
+	"eth-graph-api/pkg/subgraph"
+	"github.com/shurcooL/graphql"
+)
+
+// GraphClient is an interface that declares a method for making
+// GraphQL queries, which can be implemented by various clients
+// that interact with a GraphQL API.
+// Query sends a GraphQL query to the API and populates the response data
+// into the passed query structure. "variables" parameter is used to provide
+// GraphQL variables in the query. The method returns an error if the query
+// execution fails.
+type GraphClient interface {
+	Query(ctx context.Context, q interface{}, variables map[string]interface{}) error
+}
+
+// Repository is an interface that declares methods for fetching the data the token graph is built from.
+// GetPools retrieves all pools with some liquidity and a TVL of at least `minTvlUSD` as of the `block`,
+// and GetTokens all tokens with a TVL of at least `minTvlUSD` across their pools as of the same `block`,
+// so that they make a consistent snapshot.
+type Repository interface {
+	GetPools(ctx context.Context, block int, minTvlUSD string) ([]Pool, error)
+	GetTokens(ctx context.Context, block int, minTvlUSD string) ([]Token, error)
+}
+
+// graphRepository is a struct that implements the Repository interface,
+// it uses a GraphClient to fetch the data from the subgraph.
+type graphRepository struct {
+	graphClient GraphClient
+}
+
+// NewGraphRepository is a constructor function that returns a new instance of
+// a struct implementing the Repository interface, initializing it with
+// a provided GraphClient.
+func NewGraphRepository(graphClient GraphClient) Repository {
+	return &graphRepository{
+		graphClient: graphClient,
+	}
+}
+
+// GetPools performs GraphQL queries to retrieve all pools with a non-zero active liquidity and a TVL
+// of at least `minTvlUSD` as of the `block`, ordered by ID, executing within `ctx` context.
+// It returns the pools, paging.ErrTooManyItems if there are more than paging.MaxItems,
+// or an error if a query operation fails.
+func (gr *graphRepository) GetPools(ctx context.Context, block int, minTvlUSD string) ([]Pool, error) {
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Pool, error) {
+		var query struct {
+			Pools []Pool `graphql:"pools(block: {number: $block}, first: $first, orderBy: id, orderDirection: asc, where: $where)"`
+		}
+
+		where := subgraph.Pool_filter{
+			"liquidity_gt":            "0",
+			"totalValueLockedUSD_gte": minTvlUSD,
+		}
+		if cursor != "" {
+			where["id_gt"] = cursor
+		}
+
+		vars := map[string]interface{}{
+			"block": graphql.Int(block),
+			"first": graphql.Int(first),
+			"where": where,
+		}
+
+		err := gr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("GetPools error", "error", err)
+			return nil, err
+		}
+
+		return query.Pools, nil
+	}
+
+	cursorOf := func(p Pool) string {
+		return p.ID
+	}
+
+	return paging.CollectAll(ctx, fetch, cursorOf)
+}
+
+// GetTokens performs GraphQL queries to retrieve all tokens with a TVL of at least `minTvlUSD`
+// across their pools as of the `block`, ordered by ID, executing within `ctx` context.
+// It returns the tokens, paging.ErrTooManyItems if there are more than paging.MaxItems,
+// or an error if a query operation fails.
+func (gr *graphRepository) GetTokens(ctx context.Context, block int, minTvlUSD string) ([]Token, error) {
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Token, error) {
+		var query struct {
+			Tokens []Token `graphql:"tokens(block: {number: $block}, first: $first, orderBy: id, orderDirection: asc, where: $where)"`
+		}
+
+		where := subgraph.Token_filter{
+			"totalValueLockedUSD_gte": minTvlUSD,
+		}
+		if cursor != "" {
+			where["id_gt"] = cursor
+		}
+
+		vars := map[string]interface{}{
+			"block": graphql.Int(block),
+			"first": graphql.Int(first),
+			"where": where,
+		}
+
+		err := gr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("GetTokens error", "error", err)
+			return nil, err
+		}
+
+		return query.Tokens, nil
+	}
+
+	cursorOf := func(t Token) string {
+		return t.ID
+	}
+
+	return paging.CollectAll(ctx, fetch, cursorOf)
+}
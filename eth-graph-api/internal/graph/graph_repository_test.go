@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"eth-graph-api/pkg/subgraph"
+	"github.com/shurcooL/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+type MockGraphClient struct {
+	mock.Mock
+}
+
+func (m *MockGraphClient) Query(ctx context.Context, query interface{}, vars map[string]interface{}) error {
+	args := m.Called(ctx, query, vars)
+	return args.Error(0)
+}
+
+func TestGetPools(t *testing.T) {
+	type poolsQuery = struct {
+		Pools []Pool `graphql:"pools(block: {number: $block}, first: $first, orderBy: id, orderDirection: asc, where: $where)"`
+	}
+
+	t.Run("as of a block", func(t *testing.T) {
+		mockClient := new(MockGraphClient)
+		mockClient.On("Query", mock.Anything, mock.Anything, map[string]interface{}{
+			"block": graphql.Int(18319881),
+			"first": graphql.Int(1000),
+			"where": subgraph.Pool_filter{"liquidity_gt": "0", "totalValueLockedUSD_gte": "10000"},
+		}).Return(nil).Run(func(args mock.Arguments) {
+			arg := args.Get(1).(*poolsQuery)
+			arg.Pools = []Pool{{ID: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"}}
+		}).Once()
+
+		repo := NewGraphRepository(mockClient)
+		pools, err := repo.GetPools(context.Background(), 18319881, "10000")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Pool{{ID: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"}}, pools)
+
+		mockClient.AssertExpectations(t)
+	})
+
+	t.Run("client error", func(t *testing.T) {
+		mockClient := new(MockGraphClient)
+		mockClient.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("client error")).Once()
+
+		repo := NewGraphRepository(mockClient)
+		_, err := repo.GetPools(context.Background(), 18319881, "10000")
+
+		assert.EqualError(t, err, "client error")
+
+		mockClient.AssertExpectations(t)
+	})
+}
+
+func TestGetTokens(t *testing.T) {
+	type tokensQuery = struct {
+		Tokens []Token `graphql:"tokens(block: {number: $block}, first: $first, orderBy: id, orderDirection: asc, where: $where)"`
+	}
+
+	mockClient := new(MockGraphClient)
+	mockClient.On("Query", mock.Anything, mock.Anything, map[string]interface{}{
+		"block": graphql.Int(18319881),
+		"first": graphql.Int(1000),
+		"where": subgraph.Token_filter{"totalValueLockedUSD_gte": "10000"},
+	}).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(1).(*tokensQuery)
+		arg.Tokens = []Token{{ID: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Symbol: "USDC", Decimals: "6"}}
+	}).Once()
+
+	repo := NewGraphRepository(mockClient)
+	tokens, err := repo.GetTokens(context.Background(), 18319881, "10000")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Token{{ID: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Symbol: "USDC", Decimals: "6"}}, tokens)
+
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,1023 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"eth-graph-api/internal/meta"
+	"eth-graph-api/pkg/calc"
+	"eth-graph-api/pkg/logger"
+	"eth-graph-api/pkg/v3math"
+	"eth-graph-api/pkg/validator"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxHops is the number of hops routes are limited to when the request does not tell
+	DefaultMaxHops = 3
+	// maxHopsLimit is the highest number of hops routes can be requested with
+	maxHopsLimit = 4
+	// maxRoutes is the number of routes returned, the ones with the highest outputs
+	maxRoutes = 5
+	// beamWidth is the number of partial routes kept for each token at each hop. Only the ones holding the most
+	// of the token are extended, which keeps the search linear in the number of pools
+	beamWidth = 3
+	// refreshTimeout bounds a single rebuild of the graph from the subgraph
+	refreshTimeout = 2 * time.Minute
+	// maxCycles is the number of price-inconsistency cycles kept, the ones with the highest gross profits
+	maxCycles = 100
+	// cycleEpsilon is the margin, in log-price, a path has to beat another by to relax it, so that
+	// the rounding errors of floating point do not make up cycles
+	cycleEpsilon = 1e-12
+	// cyclePrecision is the precision, in bits, of the big.Float math computing the profits of cycles
+	cyclePrecision = 256
+	// pageRankDamping is the probability the walk of PageRank follows a pool rather than jumping to any token
+	pageRankDamping = 0.85
+	// pageRankIterations bounds the power iterations of PageRank, which stop earlier once the ranks
+	// change by less than pageRankTolerance
+	pageRankIterations = 100
+	pageRankTolerance  = 1e-10
+	// defaultCentralityLimit is the number of tokens ranked by centrality when the request does not tell
+	defaultCentralityLimit = 100
+)
+
+const (
+	// OrderByPageRank ranks tokens by PageRank
+	OrderByPageRank = "pageRank"
+	// OrderByDegree ranks tokens by their number of pools
+	OrderByDegree = "degree"
+	// OrderByLiquidity ranks tokens by the TVL of their pools
+	OrderByLiquidity = "liquidity"
+)
+
+var (
+	// RefreshInterval is how often the graph is rebuilt from the subgraph.
+	RefreshInterval = 5 * time.Minute
+	// MinTvlUSD is the TVL, in USD, pools need to be part of the graph, leaving out the ones too shallow to swap through.
+	MinTvlUSD = "10000"
+	// CyclesMinTvlUSD is the TVL, in USD, pools of the graph need to be searched for price-inconsistency cycles,
+	// as the prices of shallow pools are easily off without being worth trading against.
+	CyclesMinTvlUSD = "100000"
+)
+
+var (
+	// ErrGraphNotReady is returned until the graph has been built for the first time.
+	ErrGraphNotReady = errors.New("token graph not loaded yet")
+	// ErrTokenNotFound is returned when a token is not part of the graph, i.e. it has no pool above MinTvlUSD.
+	ErrTokenNotFound = errors.New("token not found in the graph")
+)
+
+// Service is an interface that defines contracts for the in-memory token graph, where tokens are vertices
+// and pools are edges, ensuring implementations keep it up to date with the subgraph and provide methods
+// for finding routes and price-inconsistency cycles through it, and for analyzing its structure.
+// Run rebuilds the graph every `interval` until `ctx` is done, and Refresh rebuilds it once,
+// searching it for cycles and analyzing it along the way.
+type Service interface {
+	Run(ctx context.Context, interval time.Duration)
+	Refresh(ctx context.Context) error
+	GetRoutesService(ctx context.Context, tokenIn string, tokenOut string, amountInStr string, maxHopsStr string) (*Routes, error)
+	GetCyclesService(ctx context.Context, minProfitStr string) (*Cycles, error)
+	GetCentralityService(ctx context.Context, orderBy string, limitStr string) (*Centrality, error)
+	GetComponentsService(ctx context.Context) (*Components, error)
+}
+
+// edge is a pool of the graph in one direction of swaps, from one of its tokens to the other,
+// with the state of the pool parsed for estimating swaps.
+type edge struct {
+	pool       *Pool
+	from       Token
+	to         Token
+	zeroForOne bool
+	sqrtPrice  *big.Int
+	tick       int
+	liquidity  *big.Int
+	fee        int64
+	// reserveOut is the raw amount of the token out held by the pool, which no swap can exceed
+	reserveOut *big.Int
+}
+
+// snapshot is the token graph as of a block: the tokens, their decimals, the pools,
+// and the edges leaving each token, along with the cycles found among the pools above cyclesMinTvlUSD,
+// the centrality of the tokens, ordered by PageRank, and the connected components.
+type snapshot struct {
+	block    int
+	tokens   map[string]Token
+	decimals map[string]int
+	pools    []Pool
+	edges    map[string][]edge
+
+	cyclesMinTvlUSD string
+	cycles          []Cycle
+
+	centrality []TokenCentrality
+	components *Components
+}
+
+// IndexingStatus is an interface that declares a method for retrieving the indexing status of the subgraph,
+// implemented by meta.Service, which tells the latest indexed block the graph is built as of.
+type IndexingStatus interface {
+	GetMetaService(ctx context.Context) (*meta.Meta, error)
+}
+
+// graphService is a concrete implementation of the Service interface,
+// building the graph using a Repository as of the block told by an IndexingStatus,
+// and swapping the snapshot in place once built, so that requests are served from the previous one meanwhile.
+type graphService struct {
+	graphRepo Repository
+	status    IndexingStatus
+
+	mu   sync.RWMutex
+	snap *snapshot
+}
+
+// NewGraphService constructs a new instance of graphService, using
+// the provided Repository `repo` to fetch data as of the latest block indexed according to `status`.
+// The graph is empty until Refresh or Run builds it.
+func NewGraphService(repo Repository, status IndexingStatus) Service {
+	return &graphService{
+		graphRepo: repo,
+		status:    status,
+	}
+}
+
+// Run rebuilds the graph right away and then every `interval`, until `ctx` is done.
+// Failures are logged, the previous graph being kept until the next attempt.
+func (s *graphService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		err := s.Refresh(ctx)
+		if err != nil {
+			logger.Error("error refreshing token graph", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Refresh rebuilds the graph from the pools above MinTvlUSD as of the latest block indexed by the subgraph,
+// searches it for the cycles among the pools above CyclesMinTvlUSD, and computes the centrality of the tokens
+// and the connected components, along with the tokens above MinTvlUSD left out of it, executing within `ctx` context.
+// It returns an error if the pools or the tokens cannot be retrieved.
+func (s *graphService) Refresh(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, refreshTimeout)
+	defer cancel()
+
+	m, err := s.status.GetMetaService(ctx)
+	if err != nil {
+		return err
+	}
+	block := m.Block.Number
+
+	pools, err := s.graphRepo.GetPools(ctx, block, MinTvlUSD)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := s.graphRepo.GetTokens(ctx, block, MinTvlUSD)
+	if err != nil {
+		return err
+	}
+
+	snap := buildSnapshot(block, pools)
+
+	// The pools of the graph are above MinTvlUSD anyway
+	snap.cyclesMinTvlUSD = CyclesMinTvlUSD
+	if cmp, err := calc.CompareNumbers(CyclesMinTvlUSD, MinTvlUSD); err != nil || cmp < 0 {
+		snap.cyclesMinTvlUSD = MinTvlUSD
+	}
+	snap.cycles = findCycles(snap, snap.cyclesMinTvlUSD)
+	snap.centrality = tokenCentrality(snap)
+	snap.components = connectedComponents(snap, tokens)
+
+	s.mu.Lock()
+	s.snap = snap
+	s.mu.Unlock()
+
+	return nil
+}
+
+// current returns the latest snapshot of the graph, or ErrGraphNotReady if none has been built yet.
+func (s *graphService) current() (*snapshot, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.snap == nil {
+		return nil, ErrGraphNotReady
+	}
+
+	return s.snap, nil
+}
+
+// buildSnapshot builds the graph out of the `pools` as of the `block`.
+// Pools whose state cannot be parsed, e.g. not initialized yet, are left out.
+func buildSnapshot(block int, pools []Pool) *snapshot {
+	snap := &snapshot{
+		block:    block,
+		tokens:   make(map[string]Token),
+		decimals: make(map[string]int),
+		pools:    make([]Pool, 0, len(pools)),
+		edges:    make(map[string][]edge),
+	}
+
+	for _, p := range pools {
+		p := p
+		edges, ok := poolEdges(&p)
+		if !ok {
+			continue
+		}
+
+		snap.pools = append(snap.pools, p)
+		for _, e := range edges {
+			snap.tokens[e.from.ID] = e.from
+			snap.edges[e.from.ID] = append(snap.edges[e.from.ID], e)
+		}
+		snap.decimals[p.Token0.ID], _ = strconv.Atoi(p.Token0.Decimals)
+		snap.decimals[p.Token1.ID], _ = strconv.Atoi(p.Token1.Decimals)
+	}
+
+	return snap
+}
+
+// poolEdges parses the state of the pool `p` into its edges, from token0 to token1 and back.
+// It returns false if the state cannot be parsed.
+func poolEdges(p *Pool) ([2]edge, bool) {
+	var edges [2]edge
+
+	sqrtPrice, ok := new(big.Int).SetString(p.SqrtPrice, 10)
+	if !ok || sqrtPrice.Cmp(v3math.MinSqrtRatio) < 0 || sqrtPrice.Cmp(v3math.MaxSqrtRatio) >= 0 {
+		return edges, false
+	}
+
+	liquidity, ok := new(big.Int).SetString(p.Liquidity, 10)
+	if !ok || liquidity.Sign() <= 0 {
+		return edges, false
+	}
+
+	tick, err := strconv.Atoi(p.Tick)
+	if err != nil {
+		return edges, false
+	}
+
+	fee, err := strconv.ParseInt(p.FeeTier, 10, 64)
+	if err != nil || fee < 0 || fee >= v3math.FeeDenominator {
+		return edges, false
+	}
+
+	decimals0, err := strconv.Atoi(p.Token0.Decimals)
+	if err != nil {
+		return edges, false
+	}
+	decimals1, err := strconv.Atoi(p.Token1.Decimals)
+	if err != nil {
+		return edges, false
+	}
+
+	reserve0, ok := toRaw(p.TotalValueLockedToken0, decimals0)
+	if !ok {
+		return edges, false
+	}
+	reserve1, ok := toRaw(p.TotalValueLockedToken1, decimals1)
+	if !ok {
+		return edges, false
+	}
+
+	edges[0] = edge{pool: p, from: p.Token0, to: p.Token1, zeroForOne: true, sqrtPrice: sqrtPrice, tick: tick, liquidity: liquidity, fee: fee, reserveOut: reserve1}
+	edges[1] = edge{pool: p, from: p.Token1, to: p.Token0, zeroForOne: false, sqrtPrice: sqrtPrice, tick: tick, liquidity: liquidity, fee: fee, reserveOut: reserve0}
+
+	return edges, true
+}
+
+// GetRoutesService finds the best routes swapping `amountInStr` (in token units) of `tokenIn` for `tokenOut`
+// in at most `maxHopsStr` hops (DefaultMaxHops if empty), validating the tokens, the amount and the hops.
+// - Each hop is estimated with the price and active liquidity of its pool, as if the liquidity held over
+// the price move, and is left out if the pool does not hold the output,
+// - The routes are searched hop by hop, extending the beamWidth partial routes holding the most of each token,
+// without going through a token twice,
+// - And the maxRoutes routes with the highest outputs are returned.
+// The search stops as soon as `ctx` is done.
+// It returns the Routes, ErrGraphNotReady, ErrTokenNotFound, or an error.
+func (s *graphService) GetRoutesService(ctx context.Context, tokenIn string, tokenOut string, amountInStr string, maxHopsStr string) (*Routes, error) {
+	if !validator.IsValidToken(tokenIn) {
+		return nil, errors.New("invalid tokenIn")
+	}
+
+	if !validator.IsValidToken(tokenOut) {
+		return nil, errors.New("invalid tokenOut")
+	}
+
+	if !validator.IsValidAmount(amountInStr) {
+		return nil, errors.New("invalid amountIn")
+	}
+
+	maxHops := DefaultMaxHops
+	if maxHopsStr != "" {
+		var err error
+		maxHops, err = strconv.Atoi(maxHopsStr)
+		if err != nil || maxHops < 1 || maxHops > maxHopsLimit {
+			return nil, errors.New("invalid maxHops")
+		}
+	}
+
+	tokenIn = strings.ToLower(tokenIn)
+	tokenOut = strings.ToLower(tokenOut)
+
+	if tokenIn == tokenOut {
+		return nil, errors.New("tokenIn and tokenOut must differ")
+	}
+
+	snap, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+
+	in, ok := snap.tokens[tokenIn]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+	out, ok := snap.tokens[tokenOut]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+
+	amountIn, _ := toRaw(amountInStr, snap.decimals[tokenIn])
+	if amountIn.Sign() == 0 {
+		return nil, errors.New("amountIn too small")
+	}
+
+	routes := &Routes{
+		Block:    snap.block,
+		TokenIn:  in,
+		TokenOut: out,
+		AmountIn: fromRaw(amountIn, snap.decimals[tokenIn]),
+		Routes:   []Route{},
+	}
+
+	paths, err := findRoutes(ctx, snap, tokenIn, tokenOut, amountIn, maxHops)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, found := range paths {
+		route := Route{
+			Hops:      make([]Hop, 0, len(found.hops)),
+			AmountOut: fromRaw(found.amount, snap.decimals[tokenOut]),
+		}
+		for _, h := range found.hops {
+			route.Hops = append(route.Hops, Hop{
+				Pool:      h.edge.pool.ID,
+				FeeTier:   h.edge.pool.FeeTier,
+				TokenIn:   h.edge.from,
+				TokenOut:  h.edge.to,
+				AmountIn:  fromRaw(h.amountIn, snap.decimals[h.edge.from.ID]),
+				AmountOut: fromRaw(h.amountOut, snap.decimals[h.edge.to.ID]),
+			})
+		}
+		routes.Routes = append(routes.Routes, route)
+	}
+
+	return routes, nil
+}
+
+// GetCyclesService lists the price-inconsistency cycles of the latest graph, those with a gross profit
+// of at least `minProfitStr` (a fraction, e.g. "0.01" for 1%) if given, validating it.
+// The cycles are searched for each time the graph is rebuilt, see findCycles.
+// It returns the Cycles, ErrGraphNotReady, or an error.
+func (s *graphService) GetCyclesService(ctx context.Context, minProfitStr string) (*Cycles, error) {
+	if minProfitStr != "" && !validator.IsValidAmount(minProfitStr) {
+		return nil, errors.New("invalid minProfit")
+	}
+
+	snap, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+
+	cycles := &Cycles{
+		Block:     snap.block,
+		MinTvlUSD: snap.cyclesMinTvlUSD,
+		Cycles:    []Cycle{},
+	}
+
+	for _, c := range snap.cycles {
+		if minProfitStr != "" {
+			cmp, err := calc.CompareNumbers(c.GrossProfit, minProfitStr)
+			if err != nil {
+				return nil, errors.New("issue to get cycles")
+			}
+			// The cycles are ordered from the highest profit
+			if cmp < 0 {
+				break
+			}
+		}
+		cycles.Cycles = append(cycles.Cycles, c)
+	}
+
+	return cycles, nil
+}
+
+// priceEdge is an edge of the log-price graph searched for cycles, between the vertices `from` and `to`,
+// weighted by the opposite of the logarithm of its price, so that a cycle whose prices multiply to more than 1
+// has a negative weight.
+type priceEdge struct {
+	edge   *edge
+	from   int
+	to     int
+	weight float64
+}
+
+// findCycles searches the graph of `snap`, restricted to the pools with a TVL of at least `minTvlUSD`,
+// for cycles whose prices multiply to more than 1, i.e. negative cycles of the log-price graph,
+// Bellman-Ford style: every token starts at distance 0, as if reached from a virtual source, and the edges are relaxed
+// once per token. Any vertex still relaxed in the last round is reached from a negative cycle, found by walking back
+// its predecessors. The profits of the cycles are computed again with big.Float, and the cycles that do not hold up
+// are left out. It returns at most maxCycles cycles, from the highest gross profit.
+func findCycles(snap *snapshot, minTvlUSD string) []Cycle {
+	tokens := tokenIDs(snap)
+
+	vertices := make(map[string]int)
+	vertex := func(token string) int {
+		v, ok := vertices[token]
+		if !ok {
+			v = len(vertices)
+			vertices[token] = v
+		}
+		return v
+	}
+
+	var edges []priceEdge
+	for _, token := range tokens {
+		for i := range snap.edges[token] {
+			e := &snap.edges[token][i]
+			cmp, err := calc.CompareNumbers(e.pool.TotalValueLockedUSD, minTvlUSD)
+			if err != nil || cmp < 0 {
+				continue
+			}
+
+			price, _ := edgePrice(e, snap.decimals).Float64()
+			if price <= 0 || math.IsInf(price, 0) {
+				continue
+			}
+
+			edges = append(edges, priceEdge{edge: e, from: vertex(e.from.ID), to: vertex(e.to.ID), weight: -math.Log(price)})
+		}
+	}
+
+	n := len(vertices)
+	dist := make([]float64, n)
+	pred := make([]int, n)
+	for v := range pred {
+		pred[v] = -1
+	}
+
+	var relaxed []int
+	for round := 0; round < n; round++ {
+		relaxed = relaxed[:0]
+		for i, pe := range edges {
+			if dist[pe.from]+pe.weight < dist[pe.to]-cycleEpsilon {
+				dist[pe.to] = dist[pe.from] + pe.weight
+				pred[pe.to] = i
+				relaxed = append(relaxed, pe.to)
+			}
+		}
+		if len(relaxed) == 0 {
+			return []Cycle{}
+		}
+	}
+
+	type found struct {
+		cycle  Cycle
+		profit *big.Float
+	}
+	var candidates []found
+	seen := make(map[string]bool)
+
+	for _, v := range relaxed {
+		// Walking back n predecessors is bound to end up on the cycle
+		for i := 0; i < n && pred[v] != -1; i++ {
+			v = edges[pred[v]].from
+		}
+		if pred[v] == -1 {
+			continue
+		}
+
+		var cycle []int
+		for u := v; len(cycle) == 0 || u != v; u = edges[pred[u]].from {
+			cycle = append(cycle, pred[u])
+			if len(cycle) > n {
+				break
+			}
+		}
+		if len(cycle) > n {
+			continue
+		}
+
+		// The cycle was walked backwards, and is keyed from its lowest edge, wherever it was entered from
+		lowest := 0
+		for i := range cycle {
+			if cycle[i] < cycle[lowest] {
+				lowest = i
+			}
+		}
+		ordered := make([]int, 0, len(cycle))
+		for i := 0; i < len(cycle); i++ {
+			ordered = append(ordered, cycle[(lowest-i+len(cycle))%len(cycle)])
+		}
+		key := ""
+		for _, i := range ordered {
+			key += strconv.Itoa(i) + ","
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		c, profit := cycleOf(snap, edges, ordered)
+		if profit.Sign() > 0 {
+			candidates = append(candidates, found{cycle: c, profit: profit})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].profit.Cmp(candidates[j].profit) > 0
+	})
+	if len(candidates) > maxCycles {
+		candidates = candidates[:maxCycles]
+	}
+
+	cycles := make([]Cycle, 0, len(candidates))
+	for _, c := range candidates {
+		cycles = append(cycles, c.cycle)
+	}
+
+	return cycles
+}
+
+// cycleOf describes the cycle made of the `edges` at the indexes `cycle`, in order,
+// along with its gross profit, the product of its prices minus 1.
+func cycleOf(snap *snapshot, edges []priceEdge, cycle []int) (Cycle, *big.Float) {
+	c := Cycle{Hops: make([]CycleHop, 0, len(cycle))}
+	product := new(big.Float).SetPrec(cyclePrecision).SetInt64(1)
+
+	for _, i := range cycle {
+		e := edges[i].edge
+		price := edgePrice(e, snap.decimals)
+		product.Mul(product, price)
+
+		c.Hops = append(c.Hops, CycleHop{
+			Pool:     e.pool.ID,
+			FeeTier:  e.pool.FeeTier,
+			TokenIn:  e.from,
+			TokenOut: e.to,
+			Price:    price.Text('f', 18),
+		})
+	}
+
+	profit := product.Sub(product, big.NewFloat(1))
+	c.GrossProfit = profit.Text('f', 18)
+
+	return c, profit
+}
+
+// edgePrice returns the price of the edge `e`, the amount of its token out one token in is worth, in whole tokens,
+// with the `decimals` of the tokens. The raw price of token0 in token1 is sqrtPrice^2 / 2^192.
+func edgePrice(e *edge, decimals map[string]int) *big.Float {
+	sqrtPrice := new(big.Float).SetPrec(cyclePrecision).SetInt(e.sqrtPrice)
+	price := new(big.Float).SetPrec(cyclePrecision).Mul(sqrtPrice, sqrtPrice)
+	price.SetMantExp(price, -192)
+
+	// The price in whole tokens scales the raw one by 10^(decimals in - decimals out)
+	exp := decimals[e.from.ID] - decimals[e.to.ID]
+	if !e.zeroForOne {
+		price.Quo(new(big.Float).SetPrec(cyclePrecision).SetInt64(1), price)
+	}
+
+	abs := exp
+	if abs < 0 {
+		abs = -abs
+	}
+	scale := new(big.Float).SetPrec(cyclePrecision).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs)), nil))
+	if exp < 0 {
+		return price.Quo(price, scale)
+	}
+	return price.Mul(price, scale)
+}
+
+// GetCentralityService ranks the tokens of the latest graph by `orderBy` (OrderByPageRank, the default,
+// OrderByDegree or OrderByLiquidity), validating it, and returns the `limitStr` most central ones,
+// defaultCentralityLimit if it is not a valid limit.
+// The centrality of the tokens is computed each time the graph is rebuilt, see tokenCentrality.
+// It returns the Centrality, ErrGraphNotReady, or an error.
+func (s *graphService) GetCentralityService(ctx context.Context, orderBy string, limitStr string) (*Centrality, error) {
+	if orderBy == "" {
+		orderBy = OrderByPageRank
+	}
+
+	if orderBy != OrderByPageRank && orderBy != OrderByDegree && orderBy != OrderByLiquidity {
+		return nil, errors.New("invalid orderBy")
+	}
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || !validator.IsValidLimit(limit) {
+		limit = defaultCentralityLimit
+	}
+
+	snap, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+
+	// The snapshot is shared, so the tokens are ranked in a copy, ties keeping the order by PageRank
+	tokens := make([]TokenCentrality, len(snap.centrality))
+	copy(tokens, snap.centrality)
+
+	switch orderBy {
+	case OrderByDegree:
+		sort.SliceStable(tokens, func(i, j int) bool {
+			return tokens[i].Degree > tokens[j].Degree
+		})
+	case OrderByLiquidity:
+		sort.SliceStable(tokens, func(i, j int) bool {
+			cmp, _ := calc.CompareNumbers(tokens[i].LiquidityWeightedDegree, tokens[j].LiquidityWeightedDegree)
+			return cmp > 0
+		})
+	}
+
+	if len(tokens) > limit {
+		tokens = tokens[:limit]
+	}
+
+	return &Centrality{
+		Block:   snap.block,
+		OrderBy: orderBy,
+		Tokens:  tokens,
+	}, nil
+}
+
+// GetComponentsService returns the connected components of the latest graph and the isolated tokens.
+// The components are computed each time the graph is rebuilt, see connectedComponents.
+// It returns the Components, ErrGraphNotReady, or an error.
+func (s *graphService) GetComponentsService(ctx context.Context) (*Components, error) {
+	snap, err := s.current()
+	if err != nil {
+		return nil, err
+	}
+
+	return snap.components, nil
+}
+
+// tokenIDs returns the IDs of the tokens of the graph of `snap`, in order.
+func tokenIDs(snap *snapshot) []string {
+	ids := make([]string, 0, len(snap.tokens))
+	for id := range snap.tokens {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// poolTvl returns the TVL of the pool `p` in USD as a float, 0 if it cannot be parsed.
+func poolTvl(p *Pool) float64 {
+	tvl, err := strconv.ParseFloat(p.TotalValueLockedUSD, 64)
+	if err != nil || tvl < 0 || math.IsInf(tvl, 0) {
+		return 0
+	}
+	return tvl
+}
+
+// tokenCentrality computes the centrality of each token of the graph of `snap`: its degree, the number of its pools,
+// its liquidity-weighted degree, the TVL of its pools, each pool counting in full for both its tokens,
+// and its PageRank, computed by power iteration over the graph where each token passes its rank on
+// to its neighbors in proportion to the TVL of the pools with them. Tokens whose pools hold no TVL pass
+// their rank on to every token. It returns the tokens ordered from the highest PageRank.
+func tokenCentrality(snap *snapshot) []TokenCentrality {
+	ids := tokenIDs(snap)
+	n := len(ids)
+	if n == 0 {
+		return []TokenCentrality{}
+	}
+
+	index := make(map[string]int, n)
+	for i, id := range ids {
+		index[id] = i
+	}
+
+	type neighbor struct {
+		vertex int
+		weight float64
+	}
+	neighbors := make([][]neighbor, n)
+	strength := make([]float64, n)
+	tvls := make([][]string, n)
+
+	for i := range snap.pools {
+		p := &snap.pools[i]
+		a, b := index[p.Token0.ID], index[p.Token1.ID]
+		w := poolTvl(p)
+
+		neighbors[a] = append(neighbors[a], neighbor{vertex: b, weight: w})
+		neighbors[b] = append(neighbors[b], neighbor{vertex: a, weight: w})
+		strength[a] += w
+		strength[b] += w
+		tvls[a] = append(tvls[a], p.TotalValueLockedUSD)
+		tvls[b] = append(tvls[b], p.TotalValueLockedUSD)
+	}
+
+	rank := make([]float64, n)
+	for v := range rank {
+		rank[v] = 1 / float64(n)
+	}
+
+	next := make([]float64, n)
+	for iteration := 0; iteration < pageRankIterations; iteration++ {
+		dangling := 0.0
+		for v := range next {
+			next[v] = (1 - pageRankDamping) / float64(n)
+			if strength[v] == 0 {
+				dangling += rank[v]
+			}
+		}
+
+		for u, edges := range neighbors {
+			if strength[u] == 0 {
+				continue
+			}
+			for _, e := range edges {
+				next[e.vertex] += pageRankDamping * rank[u] * e.weight / strength[u]
+			}
+		}
+
+		change := 0.0
+		for v := range next {
+			next[v] += pageRankDamping * dangling / float64(n)
+			change += math.Abs(next[v] - rank[v])
+		}
+
+		rank, next = next, rank
+		if change < pageRankTolerance {
+			break
+		}
+	}
+
+	tokens := make([]TokenCentrality, 0, n)
+	for v, id := range ids {
+		liquidity, err := calc.SumDecimals(tvls[v])
+		if err != nil {
+			liquidity = "0"
+		}
+
+		tokens = append(tokens, TokenCentrality{
+			Token:                   snap.tokens[id],
+			Degree:                  len(neighbors[v]),
+			LiquidityWeightedDegree: liquidity,
+			PageRank:                rank[v],
+		})
+	}
+
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return tokens[i].PageRank > tokens[j].PageRank
+	})
+
+	return tokens
+}
+
+// connectedComponents computes the connected components of the graph of `snap` with a union-find over its pools.
+// The components are ordered from the largest, then from the highest TVL, and their tokens by ID.
+// The `tokens` holding enough value to be part of the graph, but with no pool in it, are the isolated ones.
+func connectedComponents(snap *snapshot, tokens []Token) *Components {
+	ids := tokenIDs(snap)
+
+	parent := make(map[string]string, len(ids))
+	for _, id := range ids {
+		parent[id] = id
+	}
+
+	var find func(id string) string
+	find = func(id string) string {
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+
+	for i := range snap.pools {
+		a, b := find(snap.pools[i].Token0.ID), find(snap.pools[i].Token1.ID)
+		if a != b {
+			parent[a] = b
+		}
+	}
+
+	type component struct {
+		tokens []Token
+		pools  int
+		tvls   []string
+		tvl    float64
+	}
+	byRoot := make(map[string]*component)
+	var roots []string
+
+	for _, id := range ids {
+		root := find(id)
+		c, ok := byRoot[root]
+		if !ok {
+			c = &component{}
+			byRoot[root] = c
+			roots = append(roots, root)
+		}
+		c.tokens = append(c.tokens, snap.tokens[id])
+	}
+
+	for i := range snap.pools {
+		c := byRoot[find(snap.pools[i].Token0.ID)]
+		c.pools++
+		c.tvls = append(c.tvls, snap.pools[i].TotalValueLockedUSD)
+		c.tvl += poolTvl(&snap.pools[i])
+	}
+
+	sort.SliceStable(roots, func(i, j int) bool {
+		a, b := byRoot[roots[i]], byRoot[roots[j]]
+		if len(a.tokens) != len(b.tokens) {
+			return len(a.tokens) > len(b.tokens)
+		}
+		return a.tvl > b.tvl
+	})
+
+	components := &Components{
+		Block:      snap.block,
+		Components: make([]Component, 0, len(roots)),
+		Isolated:   []Token{},
+	}
+
+	for _, root := range roots {
+		c := byRoot[root]
+		tvl, err := calc.SumDecimals(c.tvls)
+		if err != nil {
+			tvl = "0"
+		}
+
+		components.Components = append(components.Components, Component{
+			Size:                len(c.tokens),
+			Pools:               c.pools,
+			TotalValueLockedUSD: tvl,
+			Tokens:              c.tokens,
+		})
+	}
+
+	for _, t := range tokens {
+		if _, ok := snap.tokens[t.ID]; !ok {
+			components.Isolated = append(components.Isolated, t)
+		}
+	}
+	sort.SliceStable(components.Isolated, func(i, j int) bool {
+		return components.Isolated[i].ID < components.Isolated[j].ID
+	})
+
+	return components
+}
+
+// hop is a swap of a route being searched, through the `edge`, in raw amounts.
+type hop struct {
+	edge      *edge
+	amountIn  *big.Int
+	amountOut *big.Int
+}
+
+// path is a route being searched, holding the raw `amount` of the `token` it has reached.
+type path struct {
+	token  string
+	amount *big.Int
+	hops   []hop
+}
+
+// visits tells whether the path `p` has been through the `token`, the token it started from included.
+func (p path) visits(token string) bool {
+	for _, h := range p.hops {
+		if h.edge.from.ID == token {
+			return true
+		}
+	}
+	return p.token == token
+}
+
+// findRoutes searches the graph of `snap` for the best routes swapping the raw `amountIn` of `tokenIn`
+// for `tokenOut` in at most `maxHops` hops, executing within `ctx` context.
+// It returns at most maxRoutes routes, from the highest output, or the error of `ctx` if it is done during the search.
+func findRoutes(ctx context.Context, snap *snapshot, tokenIn string, tokenOut string, amountIn *big.Int, maxHops int) ([]path, error) {
+	layer := []path{{token: tokenIn, amount: amountIn}}
+	var found []path
+
+	for n := 1; n <= maxHops && len(layer) > 0; n++ {
+		best := make(map[string][]path)
+
+		for _, p := range layer {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			edges := snap.edges[p.token]
+			for i := range edges {
+				e := &edges[i]
+				if p.visits(e.to.ID) {
+					continue
+				}
+
+				out := estimateSwap(e, p.amount)
+				if out == nil {
+					continue
+				}
+
+				hops := make([]hop, len(p.hops), len(p.hops)+1)
+				copy(hops, p.hops)
+				next := path{token: e.to.ID, amount: out, hops: append(hops, hop{edge: e, amountIn: p.amount, amountOut: out})}
+
+				if e.to.ID == tokenOut {
+					found = append(found, next)
+				} else if n < maxHops {
+					best[e.to.ID] = keepBest(best[e.to.ID], next, beamWidth)
+				}
+			}
+		}
+
+		// The next layer is ordered by token, so that the search does not depend on the order of the map
+		tokens := make([]string, 0, len(best))
+		for token := range best {
+			tokens = append(tokens, token)
+		}
+		sort.Strings(tokens)
+
+		layer = layer[:0:0]
+		for _, token := range tokens {
+			layer = append(layer, best[token]...)
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		return found[i].amount.Cmp(found[j].amount) > 0
+	})
+	if len(found) > maxRoutes {
+		found = found[:maxRoutes]
+	}
+
+	return found, nil
+}
+
+// keepBest inserts the path `p` into the `paths`, ordered from the highest amount, keeping at most `n` of them.
+func keepBest(paths []path, p path, n int) []path {
+	i := sort.Search(len(paths), func(i int) bool {
+		return paths[i].amount.Cmp(p.amount) < 0
+	})
+	if i >= n {
+		return paths
+	}
+
+	paths = append(paths, path{})
+	copy(paths[i+1:], paths[i:])
+	paths[i] = p
+
+	if len(paths) > n {
+		paths = paths[:n]
+	}
+	return paths
+}
+
+// estimateSwap estimates the raw output of swapping the raw `amountIn` through the edge `e`,
+// with the price, active liquidity and fee of its pool, as if the liquidity held over the price move.
+// It returns nil if the swap yields nothing or more than the pool holds.
+func estimateSwap(e *edge, amountIn *big.Int) *big.Int {
+	result, err := v3math.SwapExactInput(e.sqrtPrice, e.tick, e.liquidity, e.fee, nil, e.zeroForOne, amountIn)
+	if err != nil || result.AmountIn.Cmp(amountIn) < 0 {
+		return nil
+	}
+
+	if result.AmountOut.Sign() <= 0 || result.AmountOut.Cmp(e.reserveOut) > 0 {
+		return nil
+	}
+
+	return result.AmountOut
+}
+
+// toRaw converts the decimal `amount` in token units into raw units of a token with `decimals`,
+// truncating the digits beyond the token's precision. It returns false if the amount is not a number.
+func toRaw(amount string, decimals int) (*big.Int, bool) {
+	r, ok := new(big.Rat).SetString(amount)
+	if !ok {
+		return nil, false
+	}
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	return new(big.Int).Quo(r.Num(), r.Denom()), true
+}
+
+// fromRaw formats the raw `amount` of a token with `decimals` in token units.
+func fromRaw(amount *big.Int, decimals int) string {
+	return new(big.Rat).SetFrac(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)).FloatString(decimals)
+}
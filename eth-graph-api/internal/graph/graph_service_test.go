@@ -0,0 +1,377 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"eth-graph-api/internal/meta"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+type MockIndexingStatus struct {
+	mock.Mock
+}
+
+func (m *MockIndexingStatus) GetMetaService(ctx context.Context) (*meta.Meta, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*meta.Meta), args.Error(1)
+}
+
+// indexedAt returns an IndexingStatus telling that the subgraph is at the block `number`.
+func indexedAt(number int) *MockIndexingStatus {
+	status := new(MockIndexingStatus)
+	status.On("GetMetaService", mock.Anything).Return(&meta.Meta{Block: meta.Block{Number: number}}, nil)
+	return status
+}
+
+func (m *MockRepository) GetPools(ctx context.Context, block int, minTvlUSD string) ([]Pool, error) {
+	args := m.Called(ctx, block, minTvlUSD)
+	return args.Get(0).([]Pool), args.Error(1)
+}
+
+func (m *MockRepository) GetTokens(ctx context.Context, block int, minTvlUSD string) ([]Token, error) {
+	args := m.Called(ctx, block, minTvlUSD)
+	return args.Get(0).([]Token), args.Error(1)
+}
+
+const (
+	tokenA = "0x000000000000000000000000000000000000000a"
+	tokenB = "0x000000000000000000000000000000000000000b"
+	tokenC = "0x000000000000000000000000000000000000000c"
+	tokenD = "0x000000000000000000000000000000000000000d"
+
+	// sqrtPrice1 is the square root price of 1, at tick 0
+	sqrtPrice1 = "79228162514264337593543950336"
+)
+
+// testPool is a pool between `token0` and `token1` at the price of 1, with the active `liquidity`
+// and 1000 of each token locked.
+func testPool(id string, token0 string, token1 string, feeTier string, liquidity string) Pool {
+	return Pool{
+		ID:                     id,
+		FeeTier:                feeTier,
+		Token0:                 Token{ID: token0, Symbol: token0[len(token0)-1:], Decimals: "18"},
+		Token1:                 Token{ID: token1, Symbol: token1[len(token1)-1:], Decimals: "18"},
+		Liquidity:              liquidity,
+		SqrtPrice:              sqrtPrice1,
+		Tick:                   "0",
+		TotalValueLockedUSD:    "2000",
+		TotalValueLockedToken0: "1000",
+		TotalValueLockedToken1: "1000",
+	}
+}
+
+// testPools is a graph where A and B are linked by a shallow pool, and through C by two deep ones.
+// The pool to D holds too little D to swap for, and the last pool is not initialized.
+func testPools() []Pool {
+	shallowD := testPool("0xad", tokenA, tokenD, "3000", "1000000000000000000000")
+	shallowD.TotalValueLockedToken1 = "0.01"
+
+	uninitialized := testPool("0xbd", tokenB, tokenD, "3000", "1000000000000000000000")
+	uninitialized.Tick = ""
+
+	return []Pool{
+		testPool("0xab", tokenA, tokenB, "3000", "1000000000000000000"),
+		testPool("0xac", tokenA, tokenC, "500", "1000000000000000000000"),
+		testPool("0xcb", tokenB, tokenC, "500", "1000000000000000000000"),
+		shallowD,
+		uninitialized,
+	}
+}
+
+func newTestService(t *testing.T) Service {
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetPools", mock.Anything, 18319881, MinTvlUSD).Return(testPools(), nil).Once()
+	mockRepo.On("GetTokens", mock.Anything, 18319881, MinTvlUSD).Return([]Token{}, nil).Once()
+
+	svc := NewGraphService(mockRepo, indexedAt(18319881))
+	assert.NoError(t, svc.Refresh(context.Background()))
+
+	mockRepo.AssertExpectations(t)
+
+	return svc
+}
+
+func TestGetRoutesService(t *testing.T) {
+	svc := newTestService(t)
+
+	routes, err := svc.GetRoutesService(context.Background(), "0x000000000000000000000000000000000000000A", tokenB, "1", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 18319881, routes.Block)
+	assert.Equal(t, "1.000000000000000000", routes.AmountIn)
+	assert.Len(t, routes.Routes, 2)
+
+	// The deep pools through C beat the shallow direct pool
+	best := routes.Routes[0]
+	assert.Len(t, best.Hops, 2)
+	assert.Equal(t, "0xac", best.Hops[0].Pool)
+	assert.Equal(t, "0xcb", best.Hops[1].Pool)
+	assert.Equal(t, best.Hops[0].AmountOut, best.Hops[1].AmountIn)
+	assert.Equal(t, best.Hops[1].AmountOut, best.AmountOut)
+	assert.Equal(t, "0.99700", best.AmountOut[:7])
+
+	direct := routes.Routes[1]
+	assert.Len(t, direct.Hops, 1)
+	assert.Equal(t, "0xab", direct.Hops[0].Pool)
+	assert.Equal(t, "0.49924", direct.AmountOut[:7])
+
+	routes, err = svc.GetRoutesService(context.Background(), tokenA, tokenB, "1", "1")
+
+	assert.NoError(t, err)
+	assert.Len(t, routes.Routes, 1)
+	assert.Equal(t, "0xab", routes.Routes[0].Hops[0].Pool)
+}
+
+func TestGetRoutesServiceErrors(t *testing.T) {
+	svc := newTestService(t)
+
+	tests := []struct {
+		name        string
+		tokenIn     string
+		tokenOut    string
+		amountIn    string
+		maxHops     string
+		expectedErr error
+	}{
+		{name: "invalid tokenIn", tokenIn: "0xa", tokenOut: tokenB, amountIn: "1", expectedErr: errors.New("invalid tokenIn")},
+		{name: "invalid amount", tokenIn: tokenA, tokenOut: tokenB, amountIn: "one", expectedErr: errors.New("invalid amountIn")},
+		{name: "too many hops", tokenIn: tokenA, tokenOut: tokenB, amountIn: "1", maxHops: "5", expectedErr: errors.New("invalid maxHops")},
+		{name: "same tokens", tokenIn: tokenA, tokenOut: tokenA, amountIn: "1", expectedErr: errors.New("tokenIn and tokenOut must differ")},
+		{name: "token not in graph", tokenIn: tokenA, tokenOut: "0x000000000000000000000000000000000000000e", amountIn: "1", expectedErr: ErrTokenNotFound},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := svc.GetRoutesService(context.Background(), test.tokenIn, test.tokenOut, test.amountIn, test.maxHops)
+
+			assert.EqualError(t, err, test.expectedErr.Error())
+		})
+	}
+}
+
+func TestGetRoutesServiceNoRoute(t *testing.T) {
+	svc := newTestService(t)
+
+	// D is only reachable through a pool holding less D than the swap would yield
+	routes, err := svc.GetRoutesService(context.Background(), tokenA, tokenD, "1", "")
+
+	assert.NoError(t, err)
+	assert.Empty(t, routes.Routes)
+}
+
+func TestGetRoutesServiceCancelled(t *testing.T) {
+	svc := newTestService(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := svc.GetRoutesService(ctx, tokenA, tokenB, "1", "")
+
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestGraphNotReady(t *testing.T) {
+	status := new(MockIndexingStatus)
+	status.On("GetMetaService", mock.Anything).Return((*meta.Meta)(nil), errors.New("client error")).Once()
+
+	svc := NewGraphService(new(MockRepository), status)
+
+	assert.EqualError(t, svc.Refresh(context.Background()), "client error")
+
+	_, err := svc.GetRoutesService(context.Background(), tokenA, tokenB, "1", "")
+	assert.ErrorIs(t, err, ErrGraphNotReady)
+
+	status.AssertExpectations(t)
+}
+
+func TestGetCyclesService(t *testing.T) {
+	deep := func(p Pool, sqrtPrice string) Pool {
+		p.SqrtPrice = sqrtPrice
+		p.TotalValueLockedUSD = "1000000"
+		return p
+	}
+	shallow := func(p Pool, sqrtPrice string) Pool {
+		p.SqrtPrice = sqrtPrice
+		p.TotalValueLockedUSD = "10"
+		return p
+	}
+
+	// C is worth 1.02 A in the pool between them, and 1 A through B: A -> C -> B -> A makes 2%.
+	// D is worth 1.5 B, and 1 A, but in pools below the TVL searched
+	pools := []Pool{
+		deep(testPool("0xab", tokenA, tokenB, "3000", "1000000000000000000000"), sqrtPrice1),
+		deep(testPool("0xac", tokenA, tokenC, "500", "1000000000000000000000"), "80016521857016594389520272648"),
+		deep(testPool("0xcb", tokenB, tokenC, "500", "1000000000000000000000"), sqrtPrice1),
+		shallow(testPool("0xad", tokenA, tokenD, "500", "1000000000000000000000"), sqrtPrice1),
+		shallow(testPool("0xdb", tokenD, tokenB, "500", "1000000000000000000000"), "97034285709124592626698884146"),
+	}
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetPools", mock.Anything, 18319881, MinTvlUSD).Return(pools, nil).Once()
+	mockRepo.On("GetTokens", mock.Anything, 18319881, MinTvlUSD).Return([]Token{}, nil).Once()
+
+	svc := NewGraphService(mockRepo, indexedAt(18319881))
+	assert.NoError(t, svc.Refresh(context.Background()))
+
+	cycles, err := svc.GetCyclesService(context.Background(), "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 18319881, cycles.Block)
+	assert.Equal(t, CyclesMinTvlUSD, cycles.MinTvlUSD)
+	assert.Len(t, cycles.Cycles, 1)
+
+	cycle := cycles.Cycles[0]
+	assert.Equal(t, "0.0200000000", cycle.GrossProfit[:12])
+	assert.Len(t, cycle.Hops, 3)
+	for i, hop := range cycle.Hops {
+		next := cycle.Hops[(i+1)%len(cycle.Hops)]
+		assert.Equal(t, hop.TokenOut.ID, next.TokenIn.ID)
+	}
+
+	cycles, err = svc.GetCyclesService(context.Background(), "0.05")
+
+	assert.NoError(t, err)
+	assert.Empty(t, cycles.Cycles)
+
+	_, err = svc.GetCyclesService(context.Background(), "2%")
+
+	assert.EqualError(t, err, "invalid minProfit")
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFindCyclesConsistentPrices(t *testing.T) {
+	pools := testPools()
+	for i := range pools {
+		pools[i].TotalValueLockedUSD = "1000000"
+	}
+
+	assert.Empty(t, findCycles(buildSnapshot(18319881, pools), "100000"))
+}
+
+// hubPools is a graph where A is the hub of B, C and D, while E and F only have a pool between them.
+func hubPools() []Pool {
+	withTvl := func(p Pool, tvl string) Pool {
+		p.TotalValueLockedUSD = tvl
+		return p
+	}
+
+	return []Pool{
+		withTvl(testPool("0xab", tokenA, tokenB, "500", "1000000000000000000000"), "1000000"),
+		withTvl(testPool("0xac", tokenA, tokenC, "500", "1000000000000000000000"), "500000"),
+		withTvl(testPool("0xad", tokenA, tokenD, "3000", "1000000000000000000000"), "100000"),
+		withTvl(testPool("0xef", "0x000000000000000000000000000000000000000e", "0x000000000000000000000000000000000000000f", "3000", "1000000000000000000000"), "50000"),
+	}
+}
+
+// hubTokens are the tokens holding enough value to be part of the graph of hubPools: its tokens but F,
+// along with G, which has no pool in it.
+func hubTokens() []Token {
+	ids := []string{tokenA, tokenB, tokenC, tokenD, "0x000000000000000000000000000000000000000e", "0x0000000000000000000000000000000000000010"}
+
+	tokens := make([]Token, 0, len(ids))
+	for _, id := range ids {
+		tokens = append(tokens, Token{ID: id, Decimals: "18"})
+	}
+	return tokens
+}
+
+func newHubService(t *testing.T) Service {
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetPools", mock.Anything, 18319881, MinTvlUSD).Return(hubPools(), nil).Once()
+	mockRepo.On("GetTokens", mock.Anything, 18319881, MinTvlUSD).Return(hubTokens(), nil).Once()
+
+	svc := NewGraphService(mockRepo, indexedAt(18319881))
+	assert.NoError(t, svc.Refresh(context.Background()))
+
+	mockRepo.AssertExpectations(t)
+
+	return svc
+}
+
+func TestGetCentralityService(t *testing.T) {
+	svc := newHubService(t)
+
+	centrality, err := svc.GetCentralityService(context.Background(), "", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 18319881, centrality.Block)
+	assert.Equal(t, OrderByPageRank, centrality.OrderBy)
+	assert.Len(t, centrality.Tokens, 6)
+
+	hub := centrality.Tokens[0]
+	assert.Equal(t, tokenA, hub.Token.ID)
+	assert.Equal(t, 3, hub.Degree)
+	assert.Equal(t, "1600000.0000000000", hub.LiquidityWeightedDegree)
+
+	total := 0.0
+	for i, token := range centrality.Tokens {
+		total += token.PageRank
+		if i > 0 {
+			assert.LessOrEqual(t, token.PageRank, centrality.Tokens[i-1].PageRank)
+		}
+	}
+	assert.InDelta(t, 1, total, 1e-9)
+
+	centrality, err = svc.GetCentralityService(context.Background(), OrderByLiquidity, "2")
+
+	assert.NoError(t, err)
+	assert.Len(t, centrality.Tokens, 2)
+	assert.Equal(t, tokenA, centrality.Tokens[0].Token.ID)
+	assert.Equal(t, tokenB, centrality.Tokens[1].Token.ID)
+
+	centrality, err = svc.GetCentralityService(context.Background(), OrderByDegree, "1")
+
+	assert.NoError(t, err)
+	assert.Len(t, centrality.Tokens, 1)
+	assert.Equal(t, tokenA, centrality.Tokens[0].Token.ID)
+
+	_, err = svc.GetCentralityService(context.Background(), "betweenness", "")
+
+	assert.EqualError(t, err, "invalid orderBy")
+}
+
+func TestGetComponentsService(t *testing.T) {
+	svc := newHubService(t)
+
+	components, err := svc.GetComponentsService(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 18319881, components.Block)
+	assert.Len(t, components.Components, 2)
+
+	main := components.Components[0]
+	assert.Equal(t, 4, main.Size)
+	assert.Equal(t, 3, main.Pools)
+	assert.Equal(t, "1600000.0000000000", main.TotalValueLockedUSD)
+	assert.Equal(t, tokenA, main.Tokens[0].ID)
+
+	assert.Equal(t, 2, components.Components[1].Size)
+	assert.Equal(t, []Token{{ID: "0x0000000000000000000000000000000000000010", Decimals: "18"}}, components.Isolated)
+}
+
+func TestGraphTvlPrecision(t *testing.T) {
+	withTvl := func(p Pool, tvl string) Pool {
+		p.TotalValueLockedUSD = tvl
+		return p
+	}
+	pools := []Pool{
+		withTvl(testPool("0xab", tokenA, tokenB, "500", "1000000000000000000000"), "123456789.123456789123456789"),
+		withTvl(testPool("0xac", tokenA, tokenC, "500", "1000000000000000000000"), "987654321.987654321987654321"),
+	}
+	snap := buildSnapshot(18319881, pools)
+
+	centrality := tokenCentrality(snap)
+	assert.Equal(t, tokenA, centrality[0].Token.ID)
+	assert.Equal(t, "1111111111.11111111111111111", centrality[0].LiquidityWeightedDegree)
+
+	components := connectedComponents(snap, nil)
+	assert.Equal(t, "1111111111.11111111111111111", components.Components[0].TotalValueLockedUSD)
+	assert.Empty(t, components.Isolated)
+}
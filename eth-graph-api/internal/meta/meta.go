@@ -0,0 +1,16 @@
+package meta
+
+// Block is the latest block indexed by the subgraph
+type Block struct {
+	Number    int    `json:"number" graphql:"number"`
+	Hash      string `json:"hash" graphql:"hash"`
+	Timestamp int64  `json:"timestamp" graphql:"timestamp"`
+}
+
+// Meta tells how far the subgraph has indexed the chain: the latest indexed block, the ID of the deployment,
+// and whether indexing has hit errors, in which case the subgraph may be stuck or its data incomplete
+type Meta struct {
+	Block             Block  `json:"block" graphql:"block"`
+	Deployment        string `json:"deployment" graphql:"deployment"`
+	HasIndexingErrors bool   `json:"hasIndexingErrors" graphql:"hasIndexingErrors"`
+}
@@ -0,0 +1,159 @@
+package meta
+
+import (
+	"context"
+	"errors"
+	jh "eth-graph-api/pkg/json_helper"
+	"eth-graph-api/pkg/logger"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// IndexedBlockHeader is the response header telling the latest block indexed by the subgraph
+// at the time the response was built.
+const IndexedBlockHeader = "X-Indexed-Block"
+
+// blockTime is the time between two Ethereum blocks, in seconds. No block newer than the latest indexed one
+// can have been mined before a block time has passed since it, so later times are not indexed yet.
+const blockTime = 12
+
+// Handler is a struct that contains a Service which provides
+// methods for retrieving the indexing status of the subgraph.
+type Handler struct {
+	MetaService Service
+}
+
+// GetMetaHandler is an HTTP handler function that retrieves the indexing status of the subgraph.
+// It responds with JSON-encoded status: the latest indexed block, the deployment ID,
+// and whether indexing has hit errors, or appropriate error responses.
+func (h *Handler) GetMetaHandler(w http.ResponseWriter, r *http.Request) {
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	m, err := h.MetaService.GetMetaService(ctx)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else {
+			err = jh.ErrorJSON(w, err, http.StatusBadRequest)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	err = jh.WriteJSON(w, http.StatusOK, m)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// Indexed is a middleware for the data endpoints that relates their responses to the indexing status of the subgraph.
+// - It attaches the latest indexed block number to the response in the IndexedBlockHeader.
+// - Responds with 503 Service Unavailable if the requested block or time is ahead of the latest indexed block,
+// as the subgraph would answer with missing data.
+// - Clamps the end of a requested range ahead of the latest indexed block to it, as the range is then only partly
+// indexed, e.g. a range ending now while the subgraph is a block behind.
+// The indexing status is served from the cache of the MetaService, and if it cannot be retrieved,
+// the request is served without it.
+// It has to be mounted after routing, e.g. within a chi group, so that the URL parameters are known.
+func (h *Handler) Indexed(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+		m, err := h.MetaService.GetMetaService(ctx)
+		cancel()
+
+		if err != nil {
+			logger.Error("error getting indexing status", "error", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set(IndexedBlockHeader, strconv.Itoa(m.Block.Number))
+
+		err = checkIndexed(r, m)
+		if err != nil {
+			w.Header().Set("Retry-After", strconv.Itoa(blockTime))
+			err = jh.ErrorJSON(w, err, http.StatusServiceUnavailable)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		next.ServeHTTP(w, clampIndexed(r, m))
+	})
+}
+
+// checkIndexed checks the blocks and times requested by `r` against the latest indexed block of `m`.
+// The blocks are taken from the "block" URL parameter and the "fromBlock" query parameter,
+// and the times from the "ts" URL parameter and the "from" query parameter, the ends of ranges being clamped instead,
+// see clampIndexed. Values that are not numbers, such as "latest", are left for the endpoints to validate.
+// It returns an error telling how far the subgraph has indexed if any of them is ahead of it.
+func checkIndexed(r *http.Request, m *Meta) error {
+	query := r.URL.Query()
+
+	for _, value := range []string{chi.URLParam(r, "block"), query.Get("fromBlock")} {
+		block, err := strconv.Atoi(value)
+		if err == nil && block > m.Block.Number {
+			return fmt.Errorf("block %d is not indexed yet, the subgraph is at block %d", block, m.Block.Number)
+		}
+	}
+
+	// Some graph nodes do not tell the timestamp of the indexed block
+	if m.Block.Timestamp == 0 {
+		return nil
+	}
+
+	for _, value := range []string{chi.URLParam(r, "ts"), query.Get("from")} {
+		ts, err := strconv.ParseInt(value, 10, 64)
+		if err == nil && ts > indexedUntil(m) {
+			return fmt.Errorf("time %d is not indexed yet, the subgraph is at block %d mined at %d", ts, m.Block.Number, m.Block.Timestamp)
+		}
+	}
+
+	return nil
+}
+
+// clampIndexed returns `r` with the "toBlock" and "to" query parameters, ending the requested ranges,
+// clamped to the latest indexed block of `m` and the last time it covers.
+// The request is returned as is if neither is ahead of the indexed block.
+func clampIndexed(r *http.Request, m *Meta) *http.Request {
+	query := r.URL.Query()
+	clamped := false
+
+	if block, err := strconv.Atoi(query.Get("toBlock")); err == nil && block > m.Block.Number {
+		query.Set("toBlock", strconv.Itoa(m.Block.Number))
+		clamped = true
+	}
+
+	if ts, err := strconv.ParseInt(query.Get("to"), 10, 64); err == nil && m.Block.Timestamp != 0 && ts > indexedUntil(m) {
+		query.Set("to", strconv.FormatInt(indexedUntil(m), 10))
+		clamped = true
+	}
+
+	if !clamped {
+		return r
+	}
+
+	u := *r.URL
+	u.RawQuery = query.Encode()
+	r = r.WithContext(r.Context())
+	r.URL = &u
+
+	return r
+}
+
+// indexedUntil returns the last time covered by the latest indexed block of `m`:
+// no newer block can have been mined before a block time has passed since it.
+func indexedUntil(m *Meta) int64 {
+	return m.Block.Timestamp + blockTime - 1
+}
@@ -0,0 +1,196 @@
+package meta
+
+import (
+	"context"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type MockMetaService struct {
+	mock.Mock
+}
+
+func (m *MockMetaService) Run(ctx context.Context) {
+	m.Called(ctx)
+}
+
+func (m *MockMetaService) GetMetaService(ctx context.Context) (*Meta, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*Meta), args.Error(1)
+}
+
+func TestGetMetaHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockSvcOutput  *Meta
+		mockSvcErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "valid request",
+			mockSvcOutput:  &Meta{Block: Block{Number: 18319881}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "timeout",
+			mockSvcErr:     context.DeadlineExceeded,
+			expectedStatus: http.StatusRequestTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockMetaService)
+			mockSvc.On("GetMetaService", mock.Anything).Return(test.mockSvcOutput, test.mockSvcErr).Once()
+
+			h := &Handler{
+				MetaService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, "/v1/meta", nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Get("/v1/meta", h.GetMetaHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestIndexed(t *testing.T) {
+	head := &Meta{Block: Block{Number: 18319881, Timestamp: 1697031095}}
+
+	tests := []struct {
+		name           string
+		url            string
+		mockSvcOutput  *Meta
+		mockSvcErr     error
+		expectedStatus int
+		expectedHeader string
+		expectedQuery  string
+	}{
+		{
+			name:           "indexed block",
+			url:            "/v1/blocks/18319881/swaps",
+			mockSvcOutput:  head,
+			expectedStatus: http.StatusOK,
+			expectedHeader: "18319881",
+		},
+		{
+			name:           "block ahead of the index",
+			url:            "/v1/blocks/18319882/swaps",
+			mockSvcOutput:  head,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedHeader: "18319881",
+		},
+		{
+			name:           "block tag",
+			url:            "/v1/blocks/latest/swaps",
+			mockSvcOutput:  head,
+			expectedStatus: http.StatusOK,
+			expectedHeader: "18319881",
+		},
+		{
+			name:           "block range ending ahead of the index",
+			url:            "/v1/blocks/swaps?fromBlock=18319800&toBlock=18319900",
+			mockSvcOutput:  head,
+			expectedStatus: http.StatusOK,
+			expectedHeader: "18319881",
+			expectedQuery:  "fromBlock=18319800&toBlock=18319881",
+		},
+		{
+			name:           "block range ahead of the index",
+			url:            "/v1/blocks/swaps?fromBlock=18319882&toBlock=18319900",
+			mockSvcOutput:  head,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedHeader: "18319881",
+		},
+		{
+			name:           "time within a block time of the index",
+			url:            "/v1/swaps?from=1697030000&to=1697031106",
+			mockSvcOutput:  head,
+			expectedStatus: http.StatusOK,
+			expectedHeader: "18319881",
+			expectedQuery:  "from=1697030000&to=1697031106",
+		},
+		{
+			name:           "time range ending ahead of the index",
+			url:            "/v1/swaps?from=1697030000&to=1697031200",
+			mockSvcOutput:  head,
+			expectedStatus: http.StatusOK,
+			expectedHeader: "18319881",
+			expectedQuery:  "from=1697030000&to=1697031106",
+		},
+		{
+			name:           "time range ahead of the index",
+			url:            "/v1/swaps?from=1697031107&to=1697031200",
+			mockSvcOutput:  head,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedHeader: "18319881",
+		},
+		{
+			name:           "timestamp ahead of the index",
+			url:            "/v1/blocks/by-time/1697031200",
+			mockSvcOutput:  head,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedHeader: "18319881",
+		},
+		{
+			name:           "indexing status unavailable",
+			url:            "/v1/blocks/18319882/swaps",
+			mockSvcErr:     errors.New("issue to get indexing status"),
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockMetaService)
+			mockSvc.On("GetMetaService", mock.Anything).Return(test.mockSvcOutput, test.mockSvcErr).Once()
+
+			h := &Handler{
+				MetaService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			assert.NoError(t, err)
+
+			var query string
+			ok := func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.RawQuery
+				w.WriteHeader(http.StatusOK)
+			}
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Group(func(r chi.Router) {
+				r.Use(h.Indexed)
+				r.Get("/v1/swaps", ok)
+				r.Get("/v1/blocks/swaps", ok)
+				r.Get("/v1/blocks/by-time/{ts}", ok)
+				r.Route("/v1/blocks/{block}", func(r chi.Router) {
+					r.Get("/swaps", ok)
+				})
+			})
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+			assert.Equal(t, test.expectedHeader, rr.Header().Get(IndexedBlockHeader))
+			if test.expectedQuery != "" {
+				assert.Equal(t, test.expectedQuery, query)
+			}
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
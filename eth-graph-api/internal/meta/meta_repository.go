@@ -0,0 +1,56 @@
+package meta
+
+import (
+	"context"
+	"eth-graph-api/pkg/logger"
+)
+
+// GraphClient is an interface that declares a method for making
+// GraphQL queries, which can be implemented by various clients
+// that interact with a GraphQL API.
+// Query sends a GraphQL query to the API and populates the response data
+// into the passed query structure. "variables" parameter is used to provide
+// GraphQL variables in the query. The method returns an error if the query
+// execution fails.
+type GraphClient interface {
+	Query(ctx context.Context, q interface{}, variables map[string]interface{}) error
+}
+
+// Repository is an interface that declares methods for fetching the indexing status of the subgraph.
+// GetMeta retrieves the "_meta" field of the subgraph.
+type Repository interface {
+	GetMeta(ctx context.Context) (*Meta, error)
+}
+
+// metaRepository is a struct that implements the Repository interface,
+// it uses a GraphClient to fetch the indexing status from the subgraph.
+type metaRepository struct {
+	graphClient GraphClient
+}
+
+// NewMetaRepository is a constructor function that returns a new instance of
+// a struct implementing the Repository interface, initializing it with
+// a provided GraphClient.
+func NewMetaRepository(graphClient GraphClient) Repository {
+	return &metaRepository{
+		graphClient: graphClient,
+	}
+}
+
+// GetMeta performs a GraphQL query to retrieve the indexing status of the subgraph,
+// executing within `ctx` context.
+// It returns the Meta or an error if the query operation fails.
+func (mr *metaRepository) GetMeta(ctx context.Context) (*Meta, error) {
+
+	var query struct {
+		Meta Meta `graphql:"_meta"`
+	}
+
+	err := mr.graphClient.Query(ctx, &query, nil)
+	if err != nil {
+		logger.Error("GetMeta error", "error", err)
+		return nil, err
+	}
+
+	return &query.Meta, nil
+}
@@ -0,0 +1,68 @@
+package meta
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+type MockGraphClient struct {
+	mock.Mock
+}
+
+func (m *MockGraphClient) Query(ctx context.Context, query interface{}, vars map[string]interface{}) error {
+	args := m.Called(ctx, query, vars)
+	return args.Error(0)
+}
+
+func TestGetMeta(t *testing.T) {
+	tests := []struct {
+		name           string
+		mockClientFunc func(m *MockGraphClient)
+		expectedResult *Meta
+		expectedError  string
+	}{
+		{
+			name: "success",
+			mockClientFunc: func(m *MockGraphClient) {
+				m.On("Query", mock.Anything, mock.Anything, map[string]interface{}(nil)).
+					Return(nil).
+					Run(func(args mock.Arguments) {
+						arg := args.Get(1).(*struct {
+							Meta Meta `graphql:"_meta"`
+						})
+						arg.Meta = Meta{Block: Block{Number: 18319881, Timestamp: 1697031095}, HasIndexingErrors: true}
+					})
+			},
+			expectedResult: &Meta{Block: Block{Number: 18319881, Timestamp: 1697031095}, HasIndexingErrors: true},
+		},
+		{
+			name: "error from client",
+			mockClientFunc: func(m *MockGraphClient) {
+				m.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("client error"))
+			},
+			expectedError: "client error",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockClient := new(MockGraphClient)
+			test.mockClientFunc(mockClient)
+
+			repo := NewMetaRepository(mockClient)
+			result, err := repo.GetMeta(context.Background())
+
+			if test.expectedError != "" {
+				assert.EqualError(t, err, test.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedResult, result)
+			}
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package meta
+
+import (
+	"context"
+	"errors"
+	"eth-graph-api/pkg/logger"
+	"golang.org/x/sync/singleflight"
+	"sync"
+	"time"
+)
+
+// cacheTTL is how long the indexing status is reused before the subgraph is asked again.
+// It is attached to every data response, so it is cached rather than queried for each request.
+const cacheTTL = 5 * time.Second
+
+// refreshInterval is how often Run refreshes the indexing status, often enough for the cache never to expire.
+const refreshInterval = cacheTTL / 2
+
+// fetchTimeout bounds a single fetch of the indexing status, which is shared by the requests waiting for it
+// and so is not bound to any of them.
+const fetchTimeout = 5 * time.Second
+
+// Service is an interface that defines contracts for retrieving
+// the indexing status of the subgraph.
+type Service interface {
+	Run(ctx context.Context)
+	GetMetaService(ctx context.Context) (*Meta, error)
+}
+
+// metaService is a concrete implementation of the Service interface,
+// retrieving the indexing status using a Repository and caching it for cacheTTL.
+type metaService struct {
+	metaRepo Repository
+
+	group     singleflight.Group
+	mu        sync.RWMutex
+	cached    *Meta
+	fetchedAt time.Time
+}
+
+// NewMetaService constructs a new instance of metaService, using
+// the provided Repository `repo` to fetch data.
+func NewMetaService(repo Repository) Service {
+	return &metaService{
+		metaRepo: repo,
+	}
+}
+
+// Run refreshes the indexing status right away and then every refreshInterval, until `ctx` is done,
+// so that requests are served from the cache. Failures are logged, the requests fetching the status
+// themselves once the cached one expires.
+func (s *metaService) Run(ctx context.Context) {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		_, err := s.fetch()
+		if err != nil {
+			logger.Error("error refreshing indexing status", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetMetaService retrieves the indexing status of the subgraph, executing within `ctx` context.
+// The status is served from the cache while it is younger than cacheTTL. Otherwise, it is fetched once
+// for all the concurrent requests, each waiting for it as long as its `ctx` allows.
+// It returns the Meta or an error.
+func (s *metaService) GetMetaService(ctx context.Context) (*Meta, error) {
+	s.mu.RLock()
+	cached, fetchedAt := s.cached, s.fetchedAt
+	s.mu.RUnlock()
+
+	if cached != nil && time.Since(fetchedAt) < cacheTTL {
+		return cached, nil
+	}
+
+	ch := s.group.DoChan("meta", func() (interface{}, error) {
+		return s.fetch()
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			if errors.Is(res.Err, context.DeadlineExceeded) {
+				return nil, res.Err
+			}
+			return nil, errors.New("issue to get indexing status")
+		}
+		return res.Val.(*Meta), nil
+	}
+}
+
+// fetch retrieves the indexing status using the metaRepo, within fetchTimeout, and caches it.
+// It returns the Meta or an error if the repository fails.
+func (s *metaService) fetch() (*Meta, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	m, err := s.metaRepo.GetMeta(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cached = m
+	s.fetchedAt = time.Now()
+	s.mu.Unlock()
+
+	return m, nil
+}
@@ -0,0 +1,102 @@
+package meta
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"sync"
+	"testing"
+	"time"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetMeta(ctx context.Context) (*Meta, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*Meta), args.Error(1)
+}
+
+func TestGetMetaService(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetMeta", mock.Anything).Return(&Meta{Block: Block{Number: 18319881}}, nil).Once()
+
+	svc := NewMetaService(mockRepo)
+
+	for i := 0; i < 2; i++ {
+		m, err := svc.GetMetaService(context.Background())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 18319881, m.Block.Number)
+	}
+
+	// The status is fetched once and then served from the cache
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetMetaServiceError(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetMeta", mock.Anything).Return((*Meta)(nil), errors.New("client error")).Twice()
+
+	svc := NewMetaService(mockRepo)
+
+	// Failures are not cached
+	for i := 0; i < 2; i++ {
+		_, err := svc.GetMetaService(context.Background())
+		assert.EqualError(t, err, "issue to get indexing status")
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetMetaServiceConcurrent(t *testing.T) {
+	release := make(chan time.Time)
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetMeta", mock.Anything).WaitUntil(release).Return(&Meta{Block: Block{Number: 18319881}}, nil).Once()
+
+	svc := NewMetaService(mockRepo)
+
+	// A caller giving up does not wait for the fetch
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := svc.GetMetaService(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// The concurrent callers share a single fetch
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m, err := svc.GetMetaService(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, 18319881, m.Block.Number)
+		}()
+	}
+
+	close(release)
+	wg.Wait()
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRun(t *testing.T) {
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetMeta", mock.Anything).Return(&Meta{Block: Block{Number: 18319881}}, nil).Once()
+
+	svc := NewMetaService(mockRepo)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	svc.Run(ctx)
+
+	// The status refreshed in the background is served from the cache
+	m, err := svc.GetMetaService(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 18319881, m.Block.Number)
+
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,142 @@
+package pool
+
+type Token struct {
+	ID       string `json:"id" graphql:"id"`
+	Symbol   string `json:"symbol" graphql:"symbol"`
+	Decimals string `json:"decimals" graphql:"decimals"`
+}
+
+type Pool struct {
+	ID                  string `json:"id" graphql:"id"`
+	FeeTier             string `json:"feeTier" graphql:"feeTier"`
+	Token0              Token  `json:"token0" graphql:"token0"`
+	Token1              Token  `json:"token1" graphql:"token1"`
+	Liquidity           string `json:"liquidity" graphql:"liquidity"`
+	SqrtPrice           string `json:"sqrtPrice" graphql:"sqrtPrice"`
+	Tick                string `json:"tick" graphql:"tick"`
+	Token0Price         string `json:"token0Price" graphql:"token0Price"`
+	Token1Price         string `json:"token1Price" graphql:"token1Price"`
+	TotalValueLockedUSD string `json:"totalValueLockedUSD" graphql:"totalValueLockedUSD"`
+	VolumeUSD           string `json:"volumeUSD" graphql:"volumeUSD"`
+	FeesUSD             string `json:"feesUSD" graphql:"feesUSD"`
+}
+
+// HistoryPoint is a single bucket (an hour or a day) of the pool's time series, starting at Date (UNIX timestamp),
+// named after the token time series. The volumes of the tokens are in token units, the other amounts are in USD,
+// and the prices follow the subgraph: Token0Price is the amount of token0 one token1 is worth and vice versa,
+// while Open, High, Low and Close track Token0Price
+type HistoryPoint struct {
+	Date                int64  `json:"date"`
+	VolumeUSD           string `json:"volumeUSD"`
+	VolumeToken0        string `json:"volumeToken0"`
+	VolumeToken1        string `json:"volumeToken1"`
+	FeesUSD             string `json:"feesUSD"`
+	TotalValueLockedUSD string `json:"totalValueLockedUSD"`
+	Liquidity           string `json:"liquidity"`
+	Token0Price         string `json:"token0Price"`
+	Token1Price         string `json:"token1Price"`
+	Open                string `json:"open"`
+	High                string `json:"high"`
+	Low                 string `json:"low"`
+	Close               string `json:"close"`
+	TxCount             string `json:"txCount"`
+}
+
+type PoolDayData struct {
+	Date                int64  `json:"date" graphql:"date"`
+	VolumeUSD           string `json:"volumeUSD" graphql:"volumeUSD"`
+	VolumeToken0        string `json:"volumeToken0" graphql:"volumeToken0"`
+	VolumeToken1        string `json:"volumeToken1" graphql:"volumeToken1"`
+	FeesUSD             string `json:"feesUSD" graphql:"feesUSD"`
+	TotalValueLockedUSD string `json:"totalValueLockedUSD" graphql:"tvlUSD"`
+	Liquidity           string `json:"liquidity" graphql:"liquidity"`
+	Token0Price         string `json:"token0Price" graphql:"token0Price"`
+	Token1Price         string `json:"token1Price" graphql:"token1Price"`
+	Open                string `json:"open" graphql:"open"`
+	High                string `json:"high" graphql:"high"`
+	Low                 string `json:"low" graphql:"low"`
+	Close               string `json:"close" graphql:"close"`
+	TxCount             string `json:"txCount" graphql:"txCount"`
+}
+
+type PoolHourData struct {
+	PeriodStartUnix     int64  `json:"periodStartUnix" graphql:"periodStartUnix"`
+	VolumeUSD           string `json:"volumeUSD" graphql:"volumeUSD"`
+	VolumeToken0        string `json:"volumeToken0" graphql:"volumeToken0"`
+	VolumeToken1        string `json:"volumeToken1" graphql:"volumeToken1"`
+	FeesUSD             string `json:"feesUSD" graphql:"feesUSD"`
+	TotalValueLockedUSD string `json:"totalValueLockedUSD" graphql:"tvlUSD"`
+	Liquidity           string `json:"liquidity" graphql:"liquidity"`
+	Token0Price         string `json:"token0Price" graphql:"token0Price"`
+	Token1Price         string `json:"token1Price" graphql:"token1Price"`
+	Open                string `json:"open" graphql:"open"`
+	High                string `json:"high" graphql:"high"`
+	Low                 string `json:"low" graphql:"low"`
+	Close               string `json:"close" graphql:"close"`
+	TxCount             string `json:"txCount" graphql:"txCount"`
+}
+
+// Swap is a single swap of the pool, with the price (SqrtPriceX96) it left the pool at,
+// used to build candles shorter than the time series allow
+type Swap struct {
+	ID           string `json:"id" graphql:"id"`
+	Timestamp    string `json:"timestamp" graphql:"timestamp"`
+	LogIndex     string `json:"logIndex" graphql:"logIndex"`
+	AmountUSD    string `json:"amountUSD" graphql:"amountUSD"`
+	SqrtPriceX96 string `json:"sqrtPriceX96" graphql:"sqrtPriceX96"`
+}
+
+// Tick is an initialized tick of the pool: crossing it upwards adds LiquidityNet to the active liquidity,
+// and LiquidityGross is the total liquidity of the positions bounded by it
+type Tick struct {
+	TickIdx        string `json:"tickIdx" graphql:"tickIdx"`
+	LiquidityNet   string `json:"liquidityNet" graphql:"liquidityNet"`
+	LiquidityGross string `json:"liquidityGross" graphql:"liquidityGross"`
+}
+
+// LiquidityRange is the liquidity over the range between two consecutive initialized ticks, TickLower included
+// and TickUpper excluded, in raw liquidity and in the amounts of token0 and token1 locked in it.
+// The prices bound the pool price over the range: Token0Price (the amount of token0 one token1 is worth)
+// from Token0PriceLower to Token0PriceUpper, and Token1Price from Token1PriceLower to Token1PriceUpper
+type LiquidityRange struct {
+	TickLower        int    `json:"tickLower"`
+	TickUpper        int    `json:"tickUpper"`
+	Liquidity        string `json:"liquidity"`
+	Amount0          string `json:"amount0"`
+	Amount1          string `json:"amount1"`
+	Token0PriceLower string `json:"token0PriceLower"`
+	Token0PriceUpper string `json:"token0PriceUpper"`
+	Token1PriceLower string `json:"token1PriceLower"`
+	Token1PriceUpper string `json:"token1PriceUpper"`
+	Active           bool   `json:"active"`
+}
+
+// LiquidityDistribution is the liquidity curve of a pool: the liquidity over each range between its initialized ticks,
+// ordered by tick, around the current Tick, whose range is the Active one
+type LiquidityDistribution struct {
+	Pool      string           `json:"pool"`
+	Token0    Token            `json:"token0"`
+	Token1    Token            `json:"token1"`
+	Tick      int              `json:"tick"`
+	Liquidity string           `json:"liquidity"`
+	Ranges    []LiquidityRange `json:"ranges"`
+}
+
+// Quote is the simulated exact input swap of AmountIn of TokenIn for AmountOut of TokenOut through the pool,
+// in token units, Fee being the part of AmountIn paid to the liquidity providers.
+// ExecutionPrice is the amount of TokenOut received per TokenIn, and PriceImpact how much lower it is
+// than the pool price before the swap, as a fraction, fee included. Partial is set if the liquidity of the pool
+// ran out before the whole amount was swapped, AmountIn then being the amount swapped
+type Quote struct {
+	Pool           string `json:"pool"`
+	TokenIn        Token  `json:"tokenIn"`
+	TokenOut       Token  `json:"tokenOut"`
+	AmountIn       string `json:"amountIn"`
+	AmountOut      string `json:"amountOut"`
+	Fee            string `json:"fee"`
+	ExecutionPrice string `json:"executionPrice"`
+	PriceImpact    string `json:"priceImpact"`
+	TicksCrossed   int    `json:"ticksCrossed"`
+	TickAfter      int    `json:"tickAfter"`
+	Partial        bool   `json:"partial"`
+}
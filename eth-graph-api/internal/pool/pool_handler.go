@@ -0,0 +1,294 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"eth-graph-api/pkg/candle"
+	jh "eth-graph-api/pkg/json_helper"
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Handler is a struct that contains a Service which provides
+// methods for pool data retrieval.
+type Handler struct {
+	PoolService Service
+}
+
+// GetPoolHandler is an HTTP handler function that retrieves the details of a specific pool.
+// The pool parameter is extracted from the URL.
+// It responds with JSON-encoded pool data, a 404 if the pool is unknown,
+// or appropriate error responses.
+func (h *Handler) GetPoolHandler(w http.ResponseWriter, r *http.Request) {
+
+	pool := chi.URLParam(r, "pool")
+	if pool == "" {
+		err := jh.ErrorJSON(w, errors.New("pool cannot be empty"), http.StatusBadRequest)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	p, err := h.PoolService.GetPoolService(ctx, pool)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else if errors.Is(err, ErrPoolNotFound) {
+			err = jh.ErrorJSON(w, err, http.StatusNotFound)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else {
+			err = jh.ErrorJSON(w, err, http.StatusBadRequest)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	err = jh.WriteJSON(w, http.StatusOK, p)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// GetHistoryHandler is an HTTP handler function that retrieves the time series of a specific pool,
+// such as its TVL, volume, fees, liquidity and price, for charting.
+// It extracts 'pool' from the URL and 'interval' ("hour" or "day", the default), 'from', and 'to' from the query,
+// where a missing range defaults to the last 30 days, or to the last 24 hours for the hourly series,
+// then utilizes PoolService to retrieve and respond with the time series, or handle errors appropriately.
+func (h *Handler) GetHistoryHandler(w http.ResponseWriter, r *http.Request) {
+
+	pool := chi.URLParam(r, "pool")
+	if pool == "" {
+		err := jh.ErrorJSON(w, errors.New("pool cannot be empty"), http.StatusBadRequest)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	queryParams := r.URL.Query()
+
+	interval := queryParams.Get("interval")
+	if interval == "" {
+		interval = IntervalDay
+	}
+
+	window := 30 * 24 * time.Hour
+	if interval == IntervalHour {
+		window = 24 * time.Hour
+	}
+
+	fromStr, toStr := timeRange(queryParams, window)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	points, err := h.PoolService.GetHistoryService(ctx, pool, fromStr, toStr, interval)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else {
+			err = jh.ErrorJSON(w, err, http.StatusBadRequest)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	err = jh.WriteJSON(w, http.StatusOK, points)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// GetCandlesHandler is an HTTP handler function that retrieves the price candles of a specific pool.
+// It extracts 'pool' from the URL and 'interval' ("5m", "15m", "1h", the default, "4h" or "1d"), 'from', and 'to'
+// from the query, where a missing range defaults to the last candle.DefaultWindow of the interval,
+// then utilizes PoolService to retrieve and respond with the candles, a 404 if the pool is unknown,
+// or handle errors appropriately.
+func (h *Handler) GetCandlesHandler(w http.ResponseWriter, r *http.Request) {
+
+	pool := chi.URLParam(r, "pool")
+	if pool == "" {
+		err := jh.ErrorJSON(w, errors.New("pool cannot be empty"), http.StatusBadRequest)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	queryParams := r.URL.Query()
+
+	interval := queryParams.Get("interval")
+	if interval == "" {
+		interval = "1h"
+	}
+
+	window := 24 * time.Hour
+	if seconds, ok := candle.ParseInterval(interval); ok {
+		window = time.Duration(candle.DefaultWindow(seconds)) * time.Second
+	}
+
+	fromStr, toStr := timeRange(queryParams, window)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	candles, err := h.PoolService.GetCandlesService(ctx, pool, fromStr, toStr, interval)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else if errors.Is(err, ErrPoolNotFound) {
+			err = jh.ErrorJSON(w, err, http.StatusNotFound)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else {
+			err = jh.ErrorJSON(w, err, http.StatusBadRequest)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	err = jh.WriteJSON(w, http.StatusOK, candles)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// GetTicksHandler is an HTTP handler function that retrieves the liquidity distribution of a specific pool,
+// i.e. the liquidity over the ranges between its initialized ticks, to estimate its depth and price impact.
+// The pool parameter is extracted from the URL.
+// It responds with the JSON-encoded distribution, a 404 if the pool is unknown,
+// or appropriate error responses.
+func (h *Handler) GetTicksHandler(w http.ResponseWriter, r *http.Request) {
+
+	pool := chi.URLParam(r, "pool")
+	if pool == "" {
+		err := jh.ErrorJSON(w, errors.New("pool cannot be empty"), http.StatusBadRequest)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	distribution, err := h.PoolService.GetTicksService(ctx, pool)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else if errors.Is(err, ErrPoolNotFound) {
+			err = jh.ErrorJSON(w, err, http.StatusNotFound)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else {
+			err = jh.ErrorJSON(w, err, http.StatusBadRequest)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	err = jh.WriteJSON(w, http.StatusOK, distribution)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// GetQuoteHandler is an HTTP handler function that simulates the swap of an amount of a token through a specific pool.
+// It extracts 'pool' from the URL and 'tokenIn' and 'amountIn' (in token units) from the query,
+// then utilizes PoolService to simulate the swap across the pool's ticks and respond with the quote,
+// a 404 if the pool is unknown, or handle errors appropriately.
+func (h *Handler) GetQuoteHandler(w http.ResponseWriter, r *http.Request) {
+
+	pool := chi.URLParam(r, "pool")
+	if pool == "" {
+		err := jh.ErrorJSON(w, errors.New("pool cannot be empty"), http.StatusBadRequest)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	queryParams := r.URL.Query()
+	tokenIn := queryParams.Get("tokenIn")
+	amountIn := queryParams.Get("amountIn")
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	quote, err := h.PoolService.GetQuoteService(ctx, pool, tokenIn, amountIn)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else if errors.Is(err, ErrPoolNotFound) {
+			err = jh.ErrorJSON(w, err, http.StatusNotFound)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else {
+			err = jh.ErrorJSON(w, err, http.StatusBadRequest)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	err = jh.WriteJSON(w, http.StatusOK, quote)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// timeRange extracts the 'from' and 'to' UNIX timestamps from the query parameters.
+// A missing 'to' defaults to now, and a missing 'from' to `window` before 'to'.
+func timeRange(queryParams url.Values, window time.Duration) (string, string) {
+	to := time.Now().Unix()
+
+	toStr := queryParams.Get("to")
+	if toStr == "" {
+		toStr = strconv.FormatInt(to, 10)
+	} else if ts, err := strconv.ParseInt(toStr, 10, 64); err == nil {
+		to = ts
+	}
+
+	fromStr := queryParams.Get("from")
+	if fromStr == "" {
+		fromStr = strconv.FormatInt(to-int64(window/time.Second), 10)
+	}
+
+	return fromStr, toStr
+}
@@ -0,0 +1,358 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"eth-graph-api/pkg/candle"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type MockPoolService struct {
+	mock.Mock
+}
+
+func (m *MockPoolService) GetPoolService(ctx context.Context, pool string) (*Pool, error) {
+	args := m.Called(ctx, pool)
+	return args.Get(0).(*Pool), args.Error(1)
+}
+
+func (m *MockPoolService) GetHistoryService(ctx context.Context, pool string, fromStr string, toStr string, interval string) ([]HistoryPoint, error) {
+	args := m.Called(ctx, pool, fromStr, toStr, interval)
+	return args.Get(0).([]HistoryPoint), args.Error(1)
+}
+
+func (m *MockPoolService) GetCandlesService(ctx context.Context, pool string, fromStr string, toStr string, interval string) ([]candle.Candle, error) {
+	args := m.Called(ctx, pool, fromStr, toStr, interval)
+	return args.Get(0).([]candle.Candle), args.Error(1)
+}
+
+func (m *MockPoolService) GetTicksService(ctx context.Context, pool string) (*LiquidityDistribution, error) {
+	args := m.Called(ctx, pool)
+	return args.Get(0).(*LiquidityDistribution), args.Error(1)
+}
+
+func (m *MockPoolService) GetQuoteService(ctx context.Context, pool string, tokenIn string, amountInStr string) (*Quote, error) {
+	args := m.Called(ctx, pool, tokenIn, amountInStr)
+	return args.Get(0).(*Quote), args.Error(1)
+}
+
+func TestGetPoolHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		mockSvcOutput  *Pool
+		mockSvcErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "valid request",
+			url:            "/v1/pools/0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",
+			mockSvcOutput:  &Pool{ID: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid pool",
+			url:            "/v1/pools/invalid",
+			mockSvcErr:     errors.New("invalid pool"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown pool",
+			url:            "/v1/pools/0x0000000000000000000000000000000000000000",
+			mockSvcErr:     ErrPoolNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockPoolService)
+			mockSvc.On("GetPoolService", mock.Anything, mock.Anything).Return(test.mockSvcOutput, test.mockSvcErr).Once()
+
+			h := &Handler{
+				PoolService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Get("/v1/pools/{pool}", h.GetPoolHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetHistoryHandler(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+
+	tests := []struct {
+		name           string
+		url            string
+		setup          func(m *MockPoolService)
+		expectedStatus int
+	}{
+		{
+			name: "hourly series",
+			url:  "/v1/pools/" + pool + "/history?interval=hour&from=1697029200&to=1697032800",
+			setup: func(m *MockPoolService) {
+				m.On("GetHistoryService", mock.Anything, pool, "1697029200", "1697032800", "hour").
+					Return([]HistoryPoint{{Date: 1697029200}}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "daily series by default",
+			url:  "/v1/pools/" + pool + "/history",
+			setup: func(m *MockPoolService) {
+				m.On("GetHistoryService", mock.Anything, pool, mock.Anything, mock.Anything, "day").
+					Return([]HistoryPoint{}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "invalid interval",
+			url:  "/v1/pools/" + pool + "/history?interval=week",
+			setup: func(m *MockPoolService) {
+				m.On("GetHistoryService", mock.Anything, pool, mock.Anything, mock.Anything, "week").
+					Return([]HistoryPoint(nil), errors.New("invalid interval")).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "timeout",
+			url:  "/v1/pools/" + pool + "/history?interval=day",
+			setup: func(m *MockPoolService) {
+				m.On("GetHistoryService", mock.Anything, pool, mock.Anything, mock.Anything, "day").
+					Return([]HistoryPoint(nil), context.DeadlineExceeded).Once()
+			},
+			expectedStatus: http.StatusRequestTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockPoolService)
+			test.setup(mockSvc)
+
+			h := &Handler{
+				PoolService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Get("/v1/pools/{pool}/history", h.GetHistoryHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetCandlesHandler(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+
+	tests := []struct {
+		name           string
+		url            string
+		setup          func(m *MockPoolService)
+		expectedStatus int
+	}{
+		{
+			name: "15 minute candles",
+			url:  "/v1/pools/" + pool + "/candles?interval=15m&from=1697029200&to=1697032800",
+			setup: func(m *MockPoolService) {
+				m.On("GetCandlesService", mock.Anything, pool, "1697029200", "1697032800", "15m").
+					Return([]candle.Candle{{Date: 1697029200}}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "hourly candles by default",
+			url:  "/v1/pools/" + pool + "/candles",
+			setup: func(m *MockPoolService) {
+				m.On("GetCandlesService", mock.Anything, pool, mock.Anything, mock.Anything, "1h").
+					Return([]candle.Candle{}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "unknown pool",
+			url:  "/v1/pools/0x0000000000000000000000000000000000000000/candles?interval=5m",
+			setup: func(m *MockPoolService) {
+				m.On("GetCandlesService", mock.Anything, mock.Anything, mock.Anything, mock.Anything, "5m").
+					Return([]candle.Candle(nil), ErrPoolNotFound).Once()
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "invalid interval",
+			url:  "/v1/pools/" + pool + "/candles?interval=1w",
+			setup: func(m *MockPoolService) {
+				m.On("GetCandlesService", mock.Anything, pool, mock.Anything, mock.Anything, "1w").
+					Return([]candle.Candle(nil), errors.New("invalid interval")).Once()
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockPoolService)
+			test.setup(mockSvc)
+
+			h := &Handler{
+				PoolService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Get("/v1/pools/{pool}/candles", h.GetCandlesHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetTicksHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		mockSvcOutput  *LiquidityDistribution
+		mockSvcErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "valid request",
+			url:            "/v1/pools/0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640/ticks",
+			mockSvcOutput:  &LiquidityDistribution{Pool: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", Tick: 200697},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "unknown pool",
+			url:            "/v1/pools/0x0000000000000000000000000000000000000000/ticks",
+			mockSvcErr:     ErrPoolNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "timeout",
+			url:            "/v1/pools/0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640/ticks",
+			mockSvcErr:     context.DeadlineExceeded,
+			expectedStatus: http.StatusRequestTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockPoolService)
+			mockSvc.On("GetTicksService", mock.Anything, mock.Anything).Return(test.mockSvcOutput, test.mockSvcErr).Once()
+
+			h := &Handler{
+				PoolService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Get("/v1/pools/{pool}/ticks", h.GetTicksHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetQuoteHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		mockSvcOutput  *Quote
+		mockSvcErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "valid request",
+			url:            "/v1/pools/0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640/quote?tokenIn=0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48&amountIn=1000",
+			mockSvcOutput:  &Quote{Pool: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", AmountIn: "1000.000000"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "token not in pool",
+			url:            "/v1/pools/0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640/quote?tokenIn=0x6b175474e89094c44da98b954eedeac495271d0f&amountIn=1000",
+			mockSvcErr:     errors.New("tokenIn is not a token of the pool"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown pool",
+			url:            "/v1/pools/0x0000000000000000000000000000000000000000/quote?tokenIn=0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48&amountIn=1000",
+			mockSvcErr:     ErrPoolNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "timeout",
+			url:            "/v1/pools/0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640/quote?tokenIn=0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48&amountIn=1000",
+			mockSvcErr:     context.DeadlineExceeded,
+			expectedStatus: http.StatusRequestTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockSvc := new(MockPoolService)
+			mockSvc.On("GetQuoteService", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(test.mockSvcOutput, test.mockSvcErr).Once()
+
+			h := &Handler{
+				PoolService: mockSvc,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, test.url, nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			r := chi.NewRouter()
+			r.Get("/v1/pools/{pool}/quote", h.GetQuoteHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, test.expectedStatus, rr.Code)
+
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	query, err := url.ParseQuery("to=1697032800")
+	assert.NoError(t, err)
+
+	from, to := timeRange(query, time.Hour)
+
+	assert.Equal(t, "1697029200", from)
+	assert.Equal(t, "1697032800", to)
+}
@@ -0,0 +1,255 @@
+package pool
+
+import (
+	"context"
+	"eth-graph-api/pkg/logger"
+	"eth-graph-api/pkg/paging"
+	"eth-graph-api/pkg/subgraph"
+	"github.com/shurcooL/graphql"
+	"strconv"
+)
+
+// GraphClient is an interface that declares a method for making
+// GraphQL queries, which can be implemented by various clients
+// that interact with a GraphQL API.
+// Query sends a GraphQL query to the API and populates the response data
+// into the passed query structure. "variables" parameter is used to provide
+// GraphQL variables in the query. The method returns an error if the query
+// execution fails.
+type GraphClient interface {
+	Query(ctx context.Context, q interface{}, variables map[string]interface{}) error
+}
+
+// Repository is an interface that declares methods for fetching Pool data,
+// providing a way to access Pool data without exposing details of the data retrieval.
+// GetPool retrieves a single pool by its ID (the pool contract address),
+// and returns nil without an error if the subgraph does not know the pool.
+// GetPoolDayData and GetPoolHourData retrieve the daily and hourly time series of a pool,
+// GetSwaps retrieves the swaps of a pool over a time range, and GetTicks all initialized ticks of a pool.
+type Repository interface {
+	GetPool(ctx context.Context, pool string) (*Pool, error)
+	GetPoolDayData(ctx context.Context, pool string, from int64, to int64) ([]PoolDayData, error)
+	GetPoolHourData(ctx context.Context, pool string, from int64, to int64) ([]PoolHourData, error)
+	GetSwaps(ctx context.Context, pool string, from int64, to int64) ([]Swap, error)
+	GetTicks(ctx context.Context, pool string) ([]Tick, error)
+}
+
+// poolRepository is a struct that implements the Repository interface,
+// it uses a GraphClient to fetch pool data from the subgraph.
+type poolRepository struct {
+	graphClient GraphClient
+}
+
+// NewPoolRepository is a constructor function that returns a new instance of
+// a struct implementing the Repository interface, initializing it with
+// a provided GraphClient.
+func NewPoolRepository(graphClient GraphClient) Repository {
+	return &poolRepository{
+		graphClient: graphClient,
+	}
+}
+
+// GetPool performs a GraphQL query to retrieve the pool with the given `pool` ID,
+// executing within `ctx` context.
+// It returns the Pool, nil if no such pool exists, or an error if the query operation fails.
+func (pr *poolRepository) GetPool(ctx context.Context, pool string) (*Pool, error) {
+
+	var query struct {
+		Pool *Pool `graphql:"pool(id: $pool)"`
+	}
+
+	vars := map[string]interface{}{
+		"pool": graphql.ID(pool),
+	}
+
+	err := pr.graphClient.Query(ctx, &query, vars)
+	if err != nil {
+		logger.Error("GetPool error", "error", err)
+		return nil, err
+	}
+
+	return query.Pool, nil
+}
+
+// GetPoolDayData performs GraphQL queries to retrieve daily data associated
+// with the given `pool` for the days starting between `from` and `to` timestamps,
+// ordered by date, executing within `ctx` context. It walks all pages of the range.
+// It returns slices of PoolDayData, paging.ErrTooManyItems, or an error if the query operation fails.
+func (pr *poolRepository) GetPoolDayData(ctx context.Context, pool string, from int64, to int64) ([]PoolDayData, error) {
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]PoolDayData, error) {
+		after, err := timeCursor(cursor, from)
+		if err != nil {
+			return nil, err
+		}
+
+		var query struct {
+			PoolDayDatas []PoolDayData `graphql:"poolDayDatas(first: $first, orderBy: date, orderDirection: asc, where: {pool: $pool, date_gt: $after, date_lte: $to})"`
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"pool":  graphql.String(pool),
+			"after": graphql.Int(after),
+			"to":    graphql.Int(to),
+		}
+
+		err = pr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("GetPoolDayData error", "error", err)
+			return nil, err
+		}
+
+		return query.PoolDayDatas, nil
+	}
+
+	cursorOf := func(d PoolDayData) string {
+		return strconv.FormatInt(d.Date, 10)
+	}
+
+	return paging.CollectAll(ctx, fetch, cursorOf)
+}
+
+// GetPoolHourData performs GraphQL queries to retrieve hourly data associated
+// with the given `pool` for the hours starting between `from` and `to` timestamps,
+// ordered by time, executing within `ctx` context. It walks all pages of the range.
+// It returns slices of PoolHourData, paging.ErrTooManyItems, or an error if the query operation fails.
+func (pr *poolRepository) GetPoolHourData(ctx context.Context, pool string, from int64, to int64) ([]PoolHourData, error) {
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]PoolHourData, error) {
+		after, err := timeCursor(cursor, from)
+		if err != nil {
+			return nil, err
+		}
+
+		var query struct {
+			PoolHourDatas []PoolHourData `graphql:"poolHourDatas(first: $first, orderBy: periodStartUnix, orderDirection: asc, where: {pool: $pool, periodStartUnix_gt: $after, periodStartUnix_lte: $to})"`
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"pool":  graphql.String(pool),
+			"after": graphql.Int(after),
+			"to":    graphql.Int(to),
+		}
+
+		err = pr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("GetPoolHourData error", "error", err)
+			return nil, err
+		}
+
+		return query.PoolHourDatas, nil
+	}
+
+	cursorOf := func(d PoolHourData) string {
+		return strconv.FormatInt(d.PeriodStartUnix, 10)
+	}
+
+	return paging.CollectAll(ctx, fetch, cursorOf)
+}
+
+// GetSwaps performs GraphQL queries to retrieve all swaps of the given `pool`
+// with timestamps between `from` and `to` inclusive, executing within `ctx` context.
+// It walks the swaps ordered by ID, requesting each page after the last ID of the previous one,
+// then orders them the way they were executed, by timestamp and then by log index.
+// It returns slices of Swap, paging.ErrTooManyItems, or an error if the query operation fails.
+func (pr *poolRepository) GetSwaps(ctx context.Context, pool string, from int64, to int64) ([]Swap, error) {
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Swap, error) {
+		var query struct {
+			Swaps []Swap `graphql:"swaps(first: $first, orderBy: id, orderDirection: asc, where: $where)"`
+		}
+
+		where := subgraph.Swap_filter{
+			"pool":          pool,
+			"timestamp_gte": strconv.FormatInt(from, 10),
+			"timestamp_lte": strconv.FormatInt(to, 10),
+		}
+		if cursor != "" {
+			where["id_gt"] = cursor
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"where": where,
+		}
+
+		err := pr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("GetSwaps error", "error", err)
+			return nil, err
+		}
+
+		return query.Swaps, nil
+	}
+
+	cursorOf := func(s Swap) string {
+		return s.ID
+	}
+
+	swaps, err := paging.CollectAll(ctx, fetch, cursorOf)
+	if err != nil {
+		return nil, err
+	}
+
+	return subgraph.InExecutionOrder(swaps, func(s Swap) (string, string) {
+		return s.Timestamp, s.LogIndex
+	}), nil
+}
+
+// GetTicks performs GraphQL queries to retrieve all initialized ticks of the given `pool`,
+// i.e. the ticks bounding at least one position, ordered by tick index, executing within `ctx` context.
+// It walks all pages, requesting each page after the last tick index of the previous one.
+// It returns slices of Tick, paging.ErrTooManyItems, or an error if the query operation fails.
+func (pr *poolRepository) GetTicks(ctx context.Context, pool string) ([]Tick, error) {
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Tick, error) {
+		var query struct {
+			Ticks []Tick `graphql:"ticks(first: $first, orderBy: tickIdx, orderDirection: asc, where: $where)"`
+		}
+
+		where := subgraph.Tick_filter{
+			"pool":              pool,
+			"liquidityGross_gt": "0",
+		}
+		if cursor != "" {
+			where["tickIdx_gt"] = cursor
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"where": where,
+		}
+
+		err := pr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("GetTicks error", "error", err)
+			return nil, err
+		}
+
+		return query.Ticks, nil
+	}
+
+	cursorOf := func(t Tick) string {
+		return t.TickIdx
+	}
+
+	return paging.CollectAll(ctx, fetch, cursorOf)
+}
+
+// timeCursor turns the cursor of a time series, the start of the last bucket seen,
+// into the timestamp the next bucket has to start after. Without a cursor,
+// the series starts at `from`.
+func timeCursor(cursor string, from int64) (int64, error) {
+	if cursor == "" {
+		return from - 1, nil
+	}
+
+	after, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil {
+		return 0, paging.ErrInvalidCursor
+	}
+
+	return after, nil
+}
@@ -0,0 +1,162 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"eth-graph-api/pkg/subgraph"
+	"github.com/shurcooL/graphql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+type MockGraphClient struct {
+	mock.Mock
+}
+
+func (m *MockGraphClient) Query(ctx context.Context, query interface{}, vars map[string]interface{}) error {
+	args := m.Called(ctx, query, vars)
+	return args.Error(0)
+}
+
+func TestGetPool(t *testing.T) {
+	tests := []struct {
+		name           string
+		pool           string
+		mockClientFunc func(m *MockGraphClient)
+		expectedResult *Pool
+		expectedError  string
+	}{
+		{
+			name: "success",
+			pool: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",
+			mockClientFunc: func(m *MockGraphClient) {
+				m.On("Query", mock.Anything, mock.Anything, map[string]interface{}{"pool": graphql.ID("0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640")}).
+					Return(nil).
+					Run(func(args mock.Arguments) {
+						arg := args.Get(1).(*struct {
+							Pool *Pool `graphql:"pool(id: $pool)"`
+						})
+						arg.Pool = &Pool{ID: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", FeeTier: "500"}
+					})
+			},
+			expectedResult: &Pool{ID: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", FeeTier: "500"},
+		},
+		{
+			name: "unknown pool",
+			pool: "0x0000000000000000000000000000000000000000",
+			mockClientFunc: func(m *MockGraphClient) {
+				m.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+			},
+			expectedResult: nil,
+		},
+		{
+			name: "error from client",
+			pool: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",
+			mockClientFunc: func(m *MockGraphClient) {
+				m.On("Query", mock.Anything, mock.Anything, mock.Anything).
+					Return(errors.New("client error"))
+			},
+			expectedError: "client error",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockClient := new(MockGraphClient)
+			test.mockClientFunc(mockClient)
+
+			repo := NewPoolRepository(mockClient)
+			result, err := repo.GetPool(context.Background(), test.pool)
+
+			if test.expectedError != "" {
+				assert.Error(t, err)
+				assert.Equal(t, test.expectedError, err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedResult, result)
+			}
+
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetPoolDayData(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+
+	mockClient := new(MockGraphClient)
+	mockClient.On("Query", mock.Anything, mock.Anything, map[string]interface{}{
+		"first": graphql.Int(1000),
+		"pool":  graphql.String(pool),
+		"after": graphql.Int(1696895999),
+		"to":    graphql.Int(1697068800),
+	}).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(1).(*struct {
+			PoolDayDatas []PoolDayData `graphql:"poolDayDatas(first: $first, orderBy: date, orderDirection: asc, where: {pool: $pool, date_gt: $after, date_lte: $to})"`
+		})
+		arg.PoolDayDatas = []PoolDayData{{Date: 1696896000, TotalValueLockedUSD: "150000000"}}
+	}).Once()
+
+	repo := NewPoolRepository(mockClient)
+	result, err := repo.GetPoolDayData(context.Background(), pool, 1696896000, 1697068800)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []PoolDayData{{Date: 1696896000, TotalValueLockedUSD: "150000000"}}, result)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetSwaps(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+
+	mockClient := new(MockGraphClient)
+	mockClient.On("Query", mock.Anything, mock.Anything, map[string]interface{}{
+		"first": graphql.Int(1000),
+		"where": subgraph.Swap_filter{"pool": pool, "timestamp_gte": "1697029200", "timestamp_lte": "1697030999"},
+	}).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(1).(*struct {
+			Swaps []Swap `graphql:"swaps(first: $first, orderBy: id, orderDirection: asc, where: $where)"`
+		})
+		arg.Swaps = []Swap{
+			{ID: "0xa#2", Timestamp: "1697029223", LogIndex: "3"},
+			{ID: "0xb#1", Timestamp: "1697029211", LogIndex: "40"},
+			{ID: "0xc#3", Timestamp: "1697029211", LogIndex: "7"},
+		}
+	}).Once()
+
+	repo := NewPoolRepository(mockClient)
+	swaps, err := repo.GetSwaps(context.Background(), pool, 1697029200, 1697030999)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Swap{
+		{ID: "0xc#3", Timestamp: "1697029211", LogIndex: "7"},
+		{ID: "0xb#1", Timestamp: "1697029211", LogIndex: "40"},
+		{ID: "0xa#2", Timestamp: "1697029223", LogIndex: "3"},
+	}, swaps)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetTicks(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+
+	mockClient := new(MockGraphClient)
+	mockClient.On("Query", mock.Anything, mock.Anything, map[string]interface{}{
+		"first": graphql.Int(1000),
+		"where": subgraph.Tick_filter{"pool": pool, "liquidityGross_gt": "0"},
+	}).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(1).(*struct {
+			Ticks []Tick `graphql:"ticks(first: $first, orderBy: tickIdx, orderDirection: asc, where: $where)"`
+		})
+		arg.Ticks = []Tick{{TickIdx: "-887270", LiquidityNet: "1000", LiquidityGross: "1000"}}
+	}).Once()
+
+	repo := NewPoolRepository(mockClient)
+	ticks, err := repo.GetTicks(context.Background(), pool)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Tick{{TickIdx: "-887270", LiquidityNet: "1000", LiquidityGross: "1000"}}, ticks)
+
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,637 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"eth-graph-api/pkg/calc"
+	"eth-graph-api/pkg/candle"
+	"eth-graph-api/pkg/paging"
+	"eth-graph-api/pkg/v3math"
+	"eth-graph-api/pkg/validator"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// IntervalHour selects the hourly buckets of the subgraph's time series
+	IntervalHour = "hour"
+	// IntervalDay selects the daily buckets of the subgraph's time series
+	IntervalDay = "day"
+)
+
+// tickPrecision is the precision, in bits, of the big.Float math turning ticks into prices and liquidity into amounts.
+const tickPrecision = 256
+
+// ErrPoolNotFound is returned when the subgraph has no pool with the requested ID.
+var ErrPoolNotFound = errors.New("pool not found")
+
+// Service is an interface that defines contracts for interacting
+// with pool data, ensuring implementations provide methods for
+// retrieving pool details, time series, candles, the liquidity distribution and swap quotes.
+type Service interface {
+	GetPoolService(ctx context.Context, pool string) (*Pool, error)
+	GetHistoryService(ctx context.Context, pool string, fromStr string, toStr string, interval string) ([]HistoryPoint, error)
+	GetCandlesService(ctx context.Context, pool string, fromStr string, toStr string, interval string) ([]candle.Candle, error)
+	GetTicksService(ctx context.Context, pool string) (*LiquidityDistribution, error)
+	GetQuoteService(ctx context.Context, pool string, tokenIn string, amountInStr string) (*Quote, error)
+}
+
+// poolService is a concrete implementation of the Service interface,
+// facilitating retrieval of pool-related data using a Repository.
+type poolService struct {
+	poolRepo Repository
+}
+
+// NewPoolService constructs a new instance of poolService, using
+// the provided Repository `repo` to fetch data.
+func NewPoolService(repo Repository) Service {
+	return &poolService{
+		poolRepo: repo,
+	}
+}
+
+// GetPoolService retrieves the details of a single pool, ensuring
+// the pool ID is validated and normalized to the lowercase form used by the subgraph,
+// executing within `ctx` context. It returns the Pool, ErrPoolNotFound, or an error.
+func (s *poolService) GetPoolService(ctx context.Context, pool string) (*Pool, error) {
+	if !validator.IsValidPool(pool) {
+		return nil, errors.New("invalid pool")
+	}
+
+	p, err := s.poolRepo.GetPool(ctx, strings.ToLower(pool))
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		return nil, errors.New("issue to get pool")
+	}
+
+	if p == nil {
+		return nil, ErrPoolNotFound
+	}
+
+	return p, nil
+}
+
+// GetHistoryService retrieves the pool time series within the specified range,
+// one point per hour or day (depending on `interval`) starting between `from` and `to`,
+// ensuring pool, fromStr, toStr and interval inputs are validated and parsed correctly,
+// executing within `ctx` context.
+// It returns a slice of HistoryPoint ordered by date or an error.
+func (s *poolService) GetHistoryService(ctx context.Context, pool string, fromStr string, toStr string, interval string) ([]HistoryPoint, error) {
+
+	if !validator.IsValidPool(pool) {
+		return nil, errors.New("invalid pool")
+	}
+
+	if !validator.IsValidRange(fromStr, toStr) {
+		return nil, errors.New("invalid range")
+	}
+
+	from, _ := strconv.ParseInt(fromStr, 10, 64)
+	to, _ := strconv.ParseInt(toStr, 10, 64)
+
+	switch interval {
+	case IntervalDay:
+		dayData, err := s.poolRepo.GetPoolDayData(ctx, strings.ToLower(pool), from, to)
+		if err != nil {
+			return nil, paging.RangeError(err, interval, "issue to get pool history")
+		}
+
+		points := make([]HistoryPoint, 0, len(dayData))
+		for _, d := range dayData {
+			points = append(points, HistoryPoint{
+				Date:                d.Date,
+				VolumeUSD:           d.VolumeUSD,
+				VolumeToken0:        d.VolumeToken0,
+				VolumeToken1:        d.VolumeToken1,
+				FeesUSD:             d.FeesUSD,
+				TotalValueLockedUSD: d.TotalValueLockedUSD,
+				Liquidity:           d.Liquidity,
+				Token0Price:         d.Token0Price,
+				Token1Price:         d.Token1Price,
+				Open:                d.Open,
+				High:                d.High,
+				Low:                 d.Low,
+				Close:               d.Close,
+				TxCount:             d.TxCount,
+			})
+		}
+
+		return points, nil
+	case IntervalHour:
+		hourData, err := s.poolRepo.GetPoolHourData(ctx, strings.ToLower(pool), from, to)
+		if err != nil {
+			return nil, paging.RangeError(err, interval, "issue to get pool history")
+		}
+
+		points := make([]HistoryPoint, 0, len(hourData))
+		for _, d := range hourData {
+			points = append(points, HistoryPoint{
+				Date:                d.PeriodStartUnix,
+				VolumeUSD:           d.VolumeUSD,
+				VolumeToken0:        d.VolumeToken0,
+				VolumeToken1:        d.VolumeToken1,
+				FeesUSD:             d.FeesUSD,
+				TotalValueLockedUSD: d.TotalValueLockedUSD,
+				Liquidity:           d.Liquidity,
+				Token0Price:         d.Token0Price,
+				Token1Price:         d.Token1Price,
+				Open:                d.Open,
+				High:                d.High,
+				Low:                 d.Low,
+				Close:               d.Close,
+				TxCount:             d.TxCount,
+			})
+		}
+
+		return points, nil
+	}
+
+	return nil, errors.New("invalid interval")
+}
+
+// GetCandlesService retrieves the candles of the pool price (token0Price, the amount of token0 one token1 is worth)
+// of the given `interval` ("5m", "15m", "1h", "4h" or "1d") starting between `from` and `to`,
+// ensuring pool, fromStr, toStr and interval inputs are validated and parsed correctly,
+// executing within `ctx` context.
+// - It fetches the pool, for its token decimals, and to tell an unknown pool whatever the interval,
+// - Daily candles come from the daily time series of the pool, and hourly and 4-hour ones from the hourly one,
+// - Shorter candles are built out of the swaps of the pool, each pricing the pool by the sqrtPriceX96 it left it at.
+// It returns a slice of candle.Candle ordered by date, ErrPoolNotFound, or an error.
+func (s *poolService) GetCandlesService(ctx context.Context, pool string, fromStr string, toStr string, interval string) ([]candle.Candle, error) {
+
+	if !validator.IsValidPool(pool) {
+		return nil, errors.New("invalid pool")
+	}
+
+	if !validator.IsValidRange(fromStr, toStr) {
+		return nil, errors.New("invalid range")
+	}
+
+	seconds, ok := candle.ParseInterval(interval)
+	if !ok {
+		return nil, errors.New("invalid interval")
+	}
+
+	pool = strings.ToLower(pool)
+
+	p, err := s.poolRepo.GetPool(ctx, pool)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		return nil, errors.New("issue to get pool")
+	}
+	if p == nil {
+		return nil, ErrPoolNotFound
+	}
+
+	from, _ := strconv.ParseInt(fromStr, 10, 64)
+	to, _ := strconv.ParseInt(toStr, 10, 64)
+	start, end := candle.Span(from, to, seconds)
+	if start > end {
+		return []candle.Candle{}, nil
+	}
+
+	var candles []candle.Candle
+
+	switch {
+	case seconds%candle.Day == 0:
+		dayData, err := s.poolRepo.GetPoolDayData(ctx, pool, start, end)
+		if err != nil {
+			return nil, paging.RangeError(err, interval, "issue to get pool candles")
+		}
+
+		for _, d := range dayData {
+			candles = append(candles, candle.Candle{Date: d.Date, Open: d.Open, High: d.High, Low: d.Low, Close: d.Close, VolumeUSD: d.VolumeUSD})
+		}
+	case seconds%candle.Hour == 0:
+		hourData, err := s.poolRepo.GetPoolHourData(ctx, pool, start, end)
+		if err != nil {
+			return nil, paging.RangeError(err, interval, "issue to get pool candles")
+		}
+
+		for _, d := range hourData {
+			candles = append(candles, candle.Candle{Date: d.PeriodStartUnix, Open: d.Open, High: d.High, Low: d.Low, Close: d.Close, VolumeUSD: d.VolumeUSD})
+		}
+	default:
+		swaps, err := s.poolRepo.GetSwaps(ctx, pool, start, end)
+		if err != nil {
+			return nil, paging.RangeError(err, interval, "issue to get pool candles")
+		}
+
+		ticks := make([]candle.Tick, 0, len(swaps))
+		for _, swap := range swaps {
+			price, err := calc.PriceFromSqrtPriceX96(swap.SqrtPriceX96, p.Token0.Decimals, p.Token1.Decimals)
+			if err != nil {
+				return nil, errors.New("issue to get pool candles")
+			}
+			ts, _ := strconv.ParseInt(swap.Timestamp, 10, 64)
+			ticks = append(ticks, candle.Tick{Time: ts, Price: price, VolumeUSD: swap.AmountUSD})
+		}
+
+		candles, err = candle.FromTicks(ticks, seconds)
+		if err != nil {
+			return nil, errors.New("issue to get pool candles")
+		}
+
+		return candles, nil
+	}
+
+	candles, err = candle.Merge(candles, seconds)
+	if err != nil {
+		return nil, errors.New("issue to get pool candles")
+	}
+
+	return candles, nil
+}
+
+// GetTicksService retrieves the liquidity distribution of a pool, ensuring the pool ID is validated
+// and normalized to the lowercase form used by the subgraph, executing within `ctx` context.
+// - It fetches the pool, for its current tick, price, active liquidity and token decimals,
+// - Fetches all initialized ticks of the pool,
+// - Computes the liquidity over each range between consecutive initialized ticks, starting from the active
+// liquidity of the range of the current tick, and adding (upwards) or removing (downwards) the net liquidity
+// of each tick crossed,
+// - And converts the liquidity of each range into the amounts of the tokens locked in it and its price bounds.
+// It returns the LiquidityDistribution, ErrPoolNotFound, or an error.
+func (s *poolService) GetTicksService(ctx context.Context, pool string) (*LiquidityDistribution, error) {
+	if !validator.IsValidPool(pool) {
+		return nil, errors.New("invalid pool")
+	}
+
+	pool = strings.ToLower(pool)
+
+	p, err := s.poolRepo.GetPool(ctx, pool)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		return nil, errors.New("issue to get pool")
+	}
+
+	if p == nil {
+		return nil, ErrPoolNotFound
+	}
+
+	distribution := &LiquidityDistribution{
+		Pool:      p.ID,
+		Token0:    p.Token0,
+		Token1:    p.Token1,
+		Liquidity: p.Liquidity,
+		Ranges:    []LiquidityRange{},
+	}
+
+	// A pool that has not been initialized yet has neither a price nor liquidity
+	if p.Tick == "" {
+		return distribution, nil
+	}
+
+	current, err := strconv.Atoi(p.Tick)
+	if err != nil {
+		return nil, errors.New("issue to get pool ticks")
+	}
+	distribution.Tick = current
+
+	ticks, err := s.poolRepo.GetTicks(ctx, pool)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		if errors.Is(err, paging.ErrTooManyItems) {
+			return nil, errors.New("too many ticks")
+		}
+		return nil, errors.New("issue to get pool ticks")
+	}
+
+	ranges, err := liquidityRanges(p, current, ticks)
+	if err != nil {
+		return nil, errors.New("issue to get pool ticks")
+	}
+	distribution.Ranges = ranges
+
+	return distribution, nil
+}
+
+// liquidityRanges computes the liquidity over each range between the consecutive initialized `ticks`,
+// ordered by tick index, of the pool `p` at the `current` tick.
+func liquidityRanges(p *Pool, current int, ticks []Tick) ([]LiquidityRange, error) {
+	n := len(ticks)
+	if n < 2 {
+		return []LiquidityRange{}, nil
+	}
+
+	parsed, err := tickLiquidity(ticks)
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]int, n)
+	nets := make([]*big.Int, n)
+	for i, t := range parsed {
+		indexes[i], nets[i] = t.Index, t.LiquidityNet
+	}
+
+	active, ok := new(big.Int).SetString(p.Liquidity, 10)
+	if !ok {
+		return nil, errors.New("invalid liquidity: " + p.Liquidity)
+	}
+
+	// liquidity[i] is the liquidity from indexes[i] up to indexes[i+1], the range of the current tick
+	// ending right before the first tick above it
+	liquidity := make([]*big.Int, n)
+	above := sort.Search(n, func(i int) bool { return indexes[i] > current })
+	if above > 0 {
+		liquidity[above-1] = active
+		for i := above - 1; i > 0; i-- {
+			liquidity[i-1] = new(big.Int).Sub(liquidity[i], nets[i])
+		}
+	}
+	prev := active
+	for i := above; i < n; i++ {
+		prev = new(big.Int).Add(prev, nets[i])
+		liquidity[i] = prev
+	}
+
+	sqrtPrice, ok := new(big.Float).SetPrec(tickPrecision).SetString(p.SqrtPrice)
+	if !ok {
+		return nil, errors.New("invalid sqrtPrice: " + p.SqrtPrice)
+	}
+	sqrtPrice.Quo(sqrtPrice, new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 96)))
+
+	decimals0, err := strconv.Atoi(p.Token0.Decimals)
+	if err != nil {
+		return nil, err
+	}
+	decimals1, err := strconv.Atoi(p.Token1.Decimals)
+	if err != nil {
+		return nil, err
+	}
+	// scale turns a raw price of token0 in token1 (1.0001^tick) into the price in whole tokens
+	scale := pow10(decimals0 - decimals1)
+
+	ranges := make([]LiquidityRange, 0, n-1)
+	for i := 0; i < n-1; i++ {
+		lower, upper := indexes[i], indexes[i+1]
+		sqrtLower, sqrtUpper := tickSqrtPrice(lower), tickSqrtPrice(upper)
+		amount0, amount1 := rangeAmounts(new(big.Float).SetPrec(tickPrecision).SetInt(liquidity[i]), sqrtPrice, sqrtLower, sqrtUpper)
+
+		price1Lower := new(big.Float).SetPrec(tickPrecision).Mul(new(big.Float).Mul(sqrtLower, sqrtLower), scale)
+		price1Upper := new(big.Float).SetPrec(tickPrecision).Mul(new(big.Float).Mul(sqrtUpper, sqrtUpper), scale)
+		one := new(big.Float).SetPrec(tickPrecision).SetInt64(1)
+
+		ranges = append(ranges, LiquidityRange{
+			TickLower:        lower,
+			TickUpper:        upper,
+			Liquidity:        liquidity[i].String(),
+			Amount0:          amount0.Quo(amount0, pow10(decimals0)).Text('f', decimals0),
+			Amount1:          amount1.Quo(amount1, pow10(decimals1)).Text('f', decimals1),
+			Token0PriceLower: new(big.Float).SetPrec(tickPrecision).Quo(one, price1Upper).Text('f', 18),
+			Token0PriceUpper: new(big.Float).SetPrec(tickPrecision).Quo(one, price1Lower).Text('f', 18),
+			Token1PriceLower: price1Lower.Text('f', 18),
+			Token1PriceUpper: price1Upper.Text('f', 18),
+			Active:           lower <= current && current < upper,
+		})
+	}
+
+	return ranges, nil
+}
+
+// rangeAmounts returns the raw amounts of token0 and token1 that the `liquidity` over the range
+// from `sqrtLower` to `sqrtUpper` holds at `sqrtPrice`, all square roots of raw prices of token0 in token1.
+// Above the range, the liquidity is all in token1, and below it, all in token0.
+func rangeAmounts(liquidity, sqrtPrice, sqrtLower, sqrtUpper *big.Float) (*big.Float, *big.Float) {
+	price := sqrtPrice
+	if price.Cmp(sqrtLower) < 0 {
+		price = sqrtLower
+	} else if price.Cmp(sqrtUpper) > 0 {
+		price = sqrtUpper
+	}
+
+	// amount0 = L * (sqrtUpper - price) / (price * sqrtUpper)
+	amount0 := new(big.Float).SetPrec(tickPrecision).Sub(sqrtUpper, price)
+	amount0.Mul(amount0, liquidity)
+	amount0.Quo(amount0, new(big.Float).SetPrec(tickPrecision).Mul(price, sqrtUpper))
+
+	// amount1 = L * (price - sqrtLower)
+	amount1 := new(big.Float).SetPrec(tickPrecision).Sub(price, sqrtLower)
+	amount1.Mul(amount1, liquidity)
+
+	return amount0, amount1
+}
+
+// tickSqrtPrice returns the square root of the raw price of token0 in token1 at the `tick`, sqrt(1.0001^tick).
+func tickSqrtPrice(tick int) *big.Float {
+	base, _ := new(big.Float).SetPrec(tickPrecision).SetString("1.0001")
+
+	exp := tick
+	if exp < 0 {
+		exp = -exp
+	}
+
+	result := new(big.Float).SetPrec(tickPrecision).SetInt64(1)
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			result.Mul(result, base)
+		}
+		base.Mul(base, base)
+	}
+
+	if tick < 0 {
+		result.Quo(new(big.Float).SetPrec(tickPrecision).SetInt64(1), result)
+	}
+
+	return result.Sqrt(result)
+}
+
+// pow10 returns 10^exp, where `exp` may be negative.
+func pow10(exp int) *big.Float {
+	abs := exp
+	if abs < 0 {
+		abs = -abs
+	}
+
+	p := new(big.Float).SetPrec(tickPrecision).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs)), nil))
+	if exp < 0 {
+		p.Quo(new(big.Float).SetPrec(tickPrecision).SetInt64(1), p)
+	}
+
+	return p
+}
+
+// tickLiquidity parses the initialized `ticks` of a pool into their index and net liquidity.
+func tickLiquidity(ticks []Tick) ([]v3math.TickLiquidity, error) {
+	parsed := make([]v3math.TickLiquidity, len(ticks))
+	for i, t := range ticks {
+		idx, err := strconv.Atoi(t.TickIdx)
+		if err != nil {
+			return nil, err
+		}
+		net, ok := new(big.Int).SetString(t.LiquidityNet, 10)
+		if !ok {
+			return nil, errors.New("invalid liquidityNet: " + t.LiquidityNet)
+		}
+		parsed[i] = v3math.TickLiquidity{Index: idx, LiquidityNet: net}
+	}
+	return parsed, nil
+}
+
+// GetQuoteService simulates the swap of `amountInStr` (in token units) of `tokenIn` through a specific pool,
+// validating the pool, the token and the amount:
+// - It fetches the pool, for its current price, tick, active liquidity, fee tier and tokens,
+// - Fetches all initialized ticks of the pool,
+// - And swaps across them as the pool contract would, with the exact tick and price math of v3math,
+// crossing ticks until the amount is spent or the liquidity runs out.
+// It returns the Quote, ErrPoolNotFound, or an error.
+func (s *poolService) GetQuoteService(ctx context.Context, pool string, tokenIn string, amountInStr string) (*Quote, error) {
+	if !validator.IsValidPool(pool) {
+		return nil, errors.New("invalid pool")
+	}
+
+	if !validator.IsValidToken(tokenIn) {
+		return nil, errors.New("invalid tokenIn")
+	}
+
+	if !validator.IsValidAmount(amountInStr) {
+		return nil, errors.New("invalid amountIn")
+	}
+
+	pool = strings.ToLower(pool)
+	tokenIn = strings.ToLower(tokenIn)
+
+	p, err := s.poolRepo.GetPool(ctx, pool)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		return nil, errors.New("issue to get pool")
+	}
+
+	if p == nil {
+		return nil, ErrPoolNotFound
+	}
+
+	var zeroForOne bool
+	quote := &Quote{Pool: p.ID}
+	switch tokenIn {
+	case p.Token0.ID:
+		zeroForOne = true
+		quote.TokenIn, quote.TokenOut = p.Token0, p.Token1
+	case p.Token1.ID:
+		quote.TokenIn, quote.TokenOut = p.Token1, p.Token0
+	default:
+		return nil, errors.New("tokenIn is not a token of the pool")
+	}
+
+	// A pool that has not been initialized yet has no price to swap at
+	if p.Tick == "" {
+		return nil, errors.New("pool not initialized")
+	}
+
+	decimalsIn, err := strconv.Atoi(quote.TokenIn.Decimals)
+	if err != nil {
+		return nil, errors.New("issue to quote swap")
+	}
+	decimalsOut, err := strconv.Atoi(quote.TokenOut.Decimals)
+	if err != nil {
+		return nil, errors.New("issue to quote swap")
+	}
+
+	amountIn := toRaw(amountInStr, decimalsIn)
+	if amountIn.Sign() == 0 {
+		return nil, errors.New("amountIn too small")
+	}
+
+	ticks, err := s.poolRepo.GetTicks(ctx, pool)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		if errors.Is(err, paging.ErrTooManyItems) {
+			return nil, errors.New("too many ticks")
+		}
+		return nil, errors.New("issue to get pool ticks")
+	}
+
+	result, spot, err := simulateSwap(p, ticks, zeroForOne, amountIn)
+	if err != nil {
+		return nil, errors.New("issue to quote swap")
+	}
+
+	quote.AmountIn = fromRaw(result.AmountIn, decimalsIn).FloatString(decimalsIn)
+	quote.AmountOut = fromRaw(result.AmountOut, decimalsOut).FloatString(decimalsOut)
+	quote.Fee = fromRaw(result.FeeAmount, decimalsIn).FloatString(decimalsIn)
+	quote.TicksCrossed = result.TicksCrossed
+	quote.TickAfter = result.Tick
+	quote.Partial = result.AmountIn.Cmp(amountIn) < 0
+
+	// The prices are in whole tokens of TokenOut per TokenIn
+	execution := new(big.Rat)
+	if result.AmountIn.Sign() > 0 {
+		execution.Quo(fromRaw(result.AmountOut, decimalsOut), fromRaw(result.AmountIn, decimalsIn))
+	}
+	spot.Mul(spot, new(big.Rat).Quo(fromRaw(big.NewInt(1), decimalsOut), fromRaw(big.NewInt(1), decimalsIn)))
+
+	impact := new(big.Rat).Sub(big.NewRat(1, 1), new(big.Rat).Quo(execution, spot))
+	quote.ExecutionPrice = execution.FloatString(18)
+	quote.PriceImpact = impact.FloatString(18)
+
+	return quote, nil
+}
+
+// simulateSwap swaps the raw `amountIn` of token0 (`zeroForOne`) or token1 through the pool `p`
+// over its initialized `ticks`. It also returns the raw price of the output token per input token before the swap.
+func simulateSwap(p *Pool, ticks []Tick, zeroForOne bool, amountIn *big.Int) (*v3math.SwapResult, *big.Rat, error) {
+	sqrtPrice, ok := new(big.Int).SetString(p.SqrtPrice, 10)
+	if !ok || sqrtPrice.Sign() <= 0 {
+		return nil, nil, errors.New("invalid sqrtPrice: " + p.SqrtPrice)
+	}
+
+	liquidity, ok := new(big.Int).SetString(p.Liquidity, 10)
+	if !ok {
+		return nil, nil, errors.New("invalid liquidity: " + p.Liquidity)
+	}
+
+	tick, err := strconv.Atoi(p.Tick)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fee, err := strconv.ParseInt(p.FeeTier, 10, 64)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parsed, err := tickLiquidity(ticks)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, err := v3math.SwapExactInput(sqrtPrice, tick, liquidity, fee, parsed, zeroForOne, amountIn)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The raw price of token0 in token1 is sqrtPrice^2 / 2^192
+	spot := new(big.Rat).SetFrac(new(big.Int).Mul(sqrtPrice, sqrtPrice), new(big.Int).Lsh(big.NewInt(1), 192))
+	if !zeroForOne {
+		spot.Inv(spot)
+	}
+
+	return result, spot, nil
+}
+
+// toRaw converts the valid decimal `amount` in token units into raw units of a token with `decimals`,
+// truncating the digits beyond the token's precision.
+func toRaw(amount string, decimals int) *big.Int {
+	r, _ := new(big.Rat).SetString(amount)
+	r.Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)))
+	return new(big.Int).Quo(r.Num(), r.Denom())
+}
+
+// fromRaw converts the raw `amount` of a token with `decimals` into token units.
+func fromRaw(amount *big.Int, decimals int) *big.Rat {
+	return new(big.Rat).SetFrac(amount, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil))
+}
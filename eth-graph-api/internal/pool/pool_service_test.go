@@ -0,0 +1,613 @@
+package pool
+
+import (
+	"context"
+	"errors"
+	"eth-graph-api/pkg/candle"
+	"eth-graph-api/pkg/paging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetPool(ctx context.Context, pool string) (*Pool, error) {
+	args := m.Called(ctx, pool)
+	return args.Get(0).(*Pool), args.Error(1)
+}
+
+func (m *MockRepository) GetPoolDayData(ctx context.Context, pool string, from int64, to int64) ([]PoolDayData, error) {
+	args := m.Called(ctx, pool, from, to)
+	return args.Get(0).([]PoolDayData), args.Error(1)
+}
+
+func (m *MockRepository) GetPoolHourData(ctx context.Context, pool string, from int64, to int64) ([]PoolHourData, error) {
+	args := m.Called(ctx, pool, from, to)
+	return args.Get(0).([]PoolHourData), args.Error(1)
+}
+
+func (m *MockRepository) GetSwaps(ctx context.Context, pool string, from int64, to int64) ([]Swap, error) {
+	args := m.Called(ctx, pool, from, to)
+	return args.Get(0).([]Swap), args.Error(1)
+}
+
+func (m *MockRepository) GetTicks(ctx context.Context, pool string) ([]Tick, error) {
+	args := m.Called(ctx, pool)
+	return args.Get(0).([]Tick), args.Error(1)
+}
+
+func TestGetPoolService(t *testing.T) {
+	tests := []struct {
+		name           string
+		pool           string
+		mockRepoFn     func(m *MockRepository)
+		expectedOutput *Pool
+		expectedErr    error
+	}{
+		{
+			name: "valid pool",
+			pool: "0x88E6A0c2dDD26FEEb64F039a2c41296FcB3f5640",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640").
+					Return(&Pool{ID: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"}, nil).Once()
+			},
+			expectedOutput: &Pool{ID: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"},
+		},
+		{
+			name:        "invalid pool",
+			pool:        "invalid",
+			mockRepoFn:  func(m *MockRepository) {},
+			expectedErr: errors.New("invalid pool"),
+		},
+		{
+			name: "unknown pool",
+			pool: "0x0000000000000000000000000000000000000000",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, mock.Anything).Return((*Pool)(nil), nil).Once()
+			},
+			expectedErr: ErrPoolNotFound,
+		},
+		{
+			name: "repo returns error",
+			pool: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, mock.Anything).Return((*Pool)(nil), errors.New("some error")).Once()
+			},
+			expectedErr: errors.New("issue to get pool"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			test.mockRepoFn(mockRepo)
+
+			svc := NewPoolService(mockRepo)
+			output, err := svc.GetPoolService(context.Background(), test.pool)
+
+			if test.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, test.expectedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedOutput, output)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetHistoryService(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+
+	tests := []struct {
+		name           string
+		pool           string
+		fromStr        string
+		toStr          string
+		interval       string
+		mockRepoFn     func(m *MockRepository)
+		expectedOutput []HistoryPoint
+		expectedErr    error
+	}{
+		{
+			name:     "daily buckets",
+			pool:     "0x88E6A0c2dDD26FEEb64F039a2c41296FcB3f5640",
+			fromStr:  "1696896000",
+			toStr:    "1697068800",
+			interval: "day",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPoolDayData", mock.Anything, pool, int64(1696896000), int64(1697068800)).
+					Return([]PoolDayData{
+						{Date: 1696896000, VolumeUSD: "180000000", FeesUSD: "90000", TotalValueLockedUSD: "150000000", Open: "1580", Close: "1567"},
+						{Date: 1696982400, VolumeUSD: "210000000", FeesUSD: "105000", TotalValueLockedUSD: "151000000", Open: "1567", Close: "1565"},
+					}, nil).Once()
+			},
+			expectedOutput: []HistoryPoint{
+				{Date: 1696896000, VolumeUSD: "180000000", FeesUSD: "90000", TotalValueLockedUSD: "150000000", Open: "1580", Close: "1567"},
+				{Date: 1696982400, VolumeUSD: "210000000", FeesUSD: "105000", TotalValueLockedUSD: "151000000", Open: "1567", Close: "1565"},
+			},
+		},
+		{
+			name:     "hourly buckets",
+			pool:     pool,
+			fromStr:  "1697029200",
+			toStr:    "1697032800",
+			interval: "hour",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPoolHourData", mock.Anything, pool, int64(1697029200), int64(1697032800)).
+					Return([]PoolHourData{
+						{PeriodStartUnix: 1697029200, VolumeUSD: "9000000", Liquidity: "16529384766925640437", TxCount: "410"},
+					}, nil).Once()
+			},
+			expectedOutput: []HistoryPoint{
+				{Date: 1697029200, VolumeUSD: "9000000", Liquidity: "16529384766925640437", TxCount: "410"},
+			},
+		},
+		{
+			name:     "hourly range too long",
+			pool:     pool,
+			fromStr:  "1600000000",
+			toStr:    "1697032800",
+			interval: "hour",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPoolHourData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return([]PoolHourData(nil), paging.ErrTooManyItems).Once()
+			},
+			expectedErr: errors.New("range too long for hour interval"),
+		},
+		{
+			name:     "repo returns error",
+			pool:     pool,
+			fromStr:  "1696896000",
+			toStr:    "1697068800",
+			interval: "day",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPoolDayData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return([]PoolDayData(nil), errors.New("some error")).Once()
+			},
+			expectedErr: errors.New("issue to get pool history"),
+		},
+		{
+			name:        "invalid interval",
+			pool:        pool,
+			fromStr:     "1696896000",
+			toStr:       "1697068800",
+			interval:    "week",
+			mockRepoFn:  func(m *MockRepository) {},
+			expectedErr: errors.New("invalid interval"),
+		},
+		{
+			name:        "invalid range",
+			pool:        pool,
+			fromStr:     "1697068800",
+			toStr:       "1696896000",
+			interval:    "day",
+			mockRepoFn:  func(m *MockRepository) {},
+			expectedErr: errors.New("invalid range"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			test.mockRepoFn(mockRepo)
+
+			svc := NewPoolService(mockRepo)
+			output, err := svc.GetHistoryService(context.Background(), test.pool, test.fromStr, test.toStr, test.interval)
+
+			if test.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, test.expectedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedOutput, output)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetCandlesService(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+	usdcWeth := &Pool{
+		ID:     pool,
+		Token0: Token{ID: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Symbol: "USDC", Decimals: "6"},
+		Token1: Token{ID: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", Symbol: "WETH", Decimals: "18"},
+	}
+
+	tests := []struct {
+		name           string
+		fromStr        string
+		toStr          string
+		interval       string
+		mockRepoFn     func(m *MockRepository)
+		expectedOutput []candle.Candle
+		expectedErr    error
+	}{
+		{
+			name:     "4 hour candles from the hourly series",
+			fromStr:  "1697011200",
+			toStr:    "1697025600",
+			interval: "4h",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, pool).Return(usdcWeth, nil).Once()
+				m.On("GetPoolHourData", mock.Anything, pool, int64(1697011200), int64(1697039999)).
+					Return([]PoolHourData{
+						{PeriodStartUnix: 1697011200, Open: "1560", High: "1565", Low: "1558", Close: "1562", VolumeUSD: "10"},
+						{PeriodStartUnix: 1697014800, Open: "1562", High: "1580", Low: "1561", Close: "1575", VolumeUSD: "20"},
+						{PeriodStartUnix: 1697025600, Open: "1575", High: "1576", Low: "1555", Close: "1557", VolumeUSD: "30"},
+					}, nil).Once()
+			},
+			expectedOutput: []candle.Candle{
+				{Date: 1697011200, Open: "1560", High: "1580", Low: "1558", Close: "1575", VolumeUSD: "30.0000000000"},
+				{Date: 1697025600, Open: "1575", High: "1576", Low: "1555", Close: "1557", VolumeUSD: "30"},
+			},
+		},
+		{
+			name:     "daily candles from the daily series",
+			fromStr:  "1696896000",
+			toStr:    "1696982400",
+			interval: "1d",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, pool).Return(usdcWeth, nil).Once()
+				m.On("GetPoolDayData", mock.Anything, pool, int64(1696896000), int64(1697068799)).
+					Return([]PoolDayData{
+						{Date: 1696896000, Open: "1580", High: "1592", Low: "1561", Close: "1567", VolumeUSD: "180000000"},
+					}, nil).Once()
+			},
+			expectedOutput: []candle.Candle{
+				{Date: 1696896000, Open: "1580", High: "1592", Low: "1561", Close: "1567", VolumeUSD: "180000000"},
+			},
+		},
+		{
+			name:     "15 minute candles from the swaps",
+			fromStr:  "1697029200",
+			toStr:    "1697030100",
+			interval: "15m",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, pool).Return(usdcWeth, nil).Once()
+				m.On("GetSwaps", mock.Anything, pool, int64(1697029200), int64(1697030999)).
+					Return([]Swap{
+						{Timestamp: "1697029211", LogIndex: "5", AmountUSD: "1000", SqrtPriceX96: "1998326233819040146329034457185493"},
+						{Timestamp: "1697029211", LogIndex: "9", AmountUSD: "500", SqrtPriceX96: "1996326233819040146329034457185493"},
+					}, nil).Once()
+			},
+			expectedOutput: []candle.Candle{
+				{
+					Date:      1697029200,
+					Open:      "1571.905334915384021085",
+					High:      "1575.056508721148594577",
+					Low:       "1571.905334915384021085",
+					Close:     "1575.056508721148594577",
+					VolumeUSD: "1500.0000000000",
+				},
+			},
+		},
+		{
+			name:     "daily candles of an unknown pool",
+			fromStr:  "1696896000",
+			toStr:    "1696982400",
+			interval: "1d",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, pool).Return((*Pool)(nil), nil).Once()
+			},
+			expectedErr: ErrPoolNotFound,
+		},
+		{
+			name:     "swaps of an unknown pool",
+			fromStr:  "1697029200",
+			toStr:    "1697030100",
+			interval: "5m",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, pool).Return((*Pool)(nil), nil).Once()
+			},
+			expectedErr: ErrPoolNotFound,
+		},
+		{
+			name:     "too many swaps",
+			fromStr:  "1690000000",
+			toStr:    "1697030100",
+			interval: "5m",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, pool).Return(usdcWeth, nil).Once()
+				m.On("GetSwaps", mock.Anything, pool, mock.Anything, mock.Anything).Return([]Swap(nil), paging.ErrTooManyItems).Once()
+			},
+			expectedErr: errors.New("range too long for 5m interval"),
+		},
+		{
+			name:        "invalid interval",
+			fromStr:     "1697029200",
+			toStr:       "1697030100",
+			interval:    "1w",
+			mockRepoFn:  func(m *MockRepository) {},
+			expectedErr: errors.New("invalid interval"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			test.mockRepoFn(mockRepo)
+
+			svc := NewPoolService(mockRepo)
+			output, err := svc.GetCandlesService(context.Background(), pool, test.fromStr, test.toStr, test.interval)
+
+			if test.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, test.expectedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedOutput, output)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetTicksService(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+
+	// Two positions: 1e18 of liquidity over [-60, 60) and 2e18 over [0, 120), with the price at tick 30
+	p := &Pool{
+		ID:        pool,
+		Token0:    Token{ID: "0xa", Symbol: "A", Decimals: "18"},
+		Token1:    Token{ID: "0xb", Symbol: "B", Decimals: "18"},
+		Liquidity: "3000000000000000000",
+		SqrtPrice: "79347087983666005045280518414",
+		Tick:      "30",
+	}
+	ticks := []Tick{
+		{TickIdx: "-60", LiquidityNet: "1000000000000000000", LiquidityGross: "1000000000000000000"},
+		{TickIdx: "0", LiquidityNet: "2000000000000000000", LiquidityGross: "2000000000000000000"},
+		{TickIdx: "60", LiquidityNet: "-1000000000000000000", LiquidityGross: "1000000000000000000"},
+		{TickIdx: "120", LiquidityNet: "-2000000000000000000", LiquidityGross: "2000000000000000000"},
+	}
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetPool", mock.Anything, pool).Return(p, nil).Once()
+	mockRepo.On("GetTicks", mock.Anything, pool).Return(ticks, nil).Once()
+
+	svc := NewPoolService(mockRepo)
+	output, err := svc.GetTicksService(context.Background(), "0x88E6A0c2dDD26FEEb64F039a2c41296FcB3f5640")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 30, output.Tick)
+	assert.Len(t, output.Ranges, 3)
+
+	zero := "0.000000000000000000"
+
+	below := output.Ranges[0]
+	assert.Equal(t, -60, below.TickLower)
+	assert.Equal(t, 0, below.TickUpper)
+	assert.Equal(t, "1000000000000000000", below.Liquidity)
+	assert.Equal(t, zero, below.Amount0)
+	assert.Equal(t, "0.00299535495591", below.Amount1[:16])
+	assert.Equal(t, "1.000000000000000000", below.Token1PriceUpper)
+	assert.False(t, below.Active)
+
+	active := output.Ranges[1]
+	assert.Equal(t, "3000000000000000000", active.Liquidity)
+	assert.NotEqual(t, zero, active.Amount0)
+	assert.NotEqual(t, zero, active.Amount1)
+	assert.Equal(t, "1.006017734268818165", active.Token1PriceUpper)
+	assert.True(t, active.Active)
+
+	above := output.Ranges[2]
+	assert.Equal(t, "2000000000000000000", above.Liquidity)
+	assert.Equal(t, "0.00597276560919", above.Amount0[:16])
+	assert.Equal(t, zero, above.Amount1)
+	assert.False(t, above.Active)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetTicksServiceErrors(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+
+	tests := []struct {
+		name        string
+		mockRepoFn  func(m *MockRepository)
+		expectedErr error
+	}{
+		{
+			name: "unknown pool",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, pool).Return((*Pool)(nil), nil).Once()
+			},
+			expectedErr: ErrPoolNotFound,
+		},
+		{
+			name: "too many ticks",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, pool).Return(&Pool{ID: pool, Tick: "200000"}, nil).Once()
+				m.On("GetTicks", mock.Anything, pool).Return([]Tick(nil), paging.ErrTooManyItems).Once()
+			},
+			expectedErr: errors.New("too many ticks"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			test.mockRepoFn(mockRepo)
+
+			svc := NewPoolService(mockRepo)
+			_, err := svc.GetTicksService(context.Background(), pool)
+
+			assert.Error(t, err)
+			assert.Equal(t, test.expectedErr.Error(), err.Error())
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetQuoteService(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+
+	// The pool of TestGetTicksService, with the 0.3% fee tier
+	p := &Pool{
+		ID:        pool,
+		FeeTier:   "3000",
+		Token0:    Token{ID: "0x6b175474e89094c44da98b954eedeac495271d0f", Symbol: "A", Decimals: "18"},
+		Token1:    Token{ID: "0xdac17f958d2ee523a2206206994597c13d831ec7", Symbol: "B", Decimals: "18"},
+		Liquidity: "3000000000000000000",
+		SqrtPrice: "79347087983666005045280518414",
+		Tick:      "30",
+	}
+	ticks := []Tick{
+		{TickIdx: "-60", LiquidityNet: "1000000000000000000", LiquidityGross: "1000000000000000000"},
+		{TickIdx: "0", LiquidityNet: "2000000000000000000", LiquidityGross: "2000000000000000000"},
+		{TickIdx: "60", LiquidityNet: "-1000000000000000000", LiquidityGross: "1000000000000000000"},
+		{TickIdx: "120", LiquidityNet: "-2000000000000000000", LiquidityGross: "2000000000000000000"},
+	}
+
+	tests := []struct {
+		name     string
+		tokenIn  string
+		amountIn string
+		check    func(t *testing.T, quote *Quote)
+	}{
+		{
+			name:     "within the active range",
+			tokenIn:  "0xdAC17F958D2ee523a2206206994597C13D831ec7",
+			amountIn: "0.001",
+			check: func(t *testing.T, quote *Quote) {
+				assert.Equal(t, "B", quote.TokenIn.Symbol)
+				assert.Equal(t, "A", quote.TokenOut.Symbol)
+				assert.Equal(t, "0.001000000000000000", quote.AmountIn)
+				assert.Equal(t, "0.000993683891784284", quote.AmountOut)
+				assert.Equal(t, "0.000003000000000000", quote.Fee)
+				assert.Equal(t, "0.993683891784284000", quote.ExecutionPrice)
+				assert.Equal(t, "0.00333072997835", quote.PriceImpact[:16])
+				assert.Equal(t, 0, quote.TicksCrossed)
+				assert.False(t, quote.Partial)
+			},
+		},
+		{
+			name:     "across a tick",
+			tokenIn:  "0x6b175474e89094c44da98b954eedeac495271d0f",
+			amountIn: "0.005",
+			check: func(t *testing.T, quote *Quote) {
+				assert.Equal(t, "0.005000000000000000", quote.AmountIn)
+				assert.Equal(t, 1, quote.TicksCrossed)
+				assert.True(t, quote.TickAfter >= -60 && quote.TickAfter < 0)
+				assert.False(t, quote.Partial)
+			},
+		},
+		{
+			name:     "until the liquidity runs out",
+			tokenIn:  "0xdac17f958d2ee523a2206206994597c13d831ec7",
+			amountIn: "1",
+			check: func(t *testing.T, quote *Quote) {
+				assert.Equal(t, 2, quote.TicksCrossed)
+				assert.True(t, quote.Partial)
+				assert.Equal(t, "0.0104", quote.AmountOut[:6])
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			mockRepo.On("GetPool", mock.Anything, pool).Return(p, nil).Once()
+			mockRepo.On("GetTicks", mock.Anything, pool).Return(ticks, nil).Once()
+
+			svc := NewPoolService(mockRepo)
+			output, err := svc.GetQuoteService(context.Background(), pool, test.tokenIn, test.amountIn)
+
+			if assert.NoError(t, err) {
+				test.check(t, output)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetQuoteServiceErrors(t *testing.T) {
+	pool := "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640"
+	token := "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	p := &Pool{
+		ID:      pool,
+		FeeTier: "500",
+		Token0:  Token{ID: token, Symbol: "USDC", Decimals: "6"},
+		Token1:  Token{ID: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", Symbol: "WETH", Decimals: "18"},
+		Tick:    "200000",
+	}
+
+	tests := []struct {
+		name        string
+		tokenIn     string
+		amountIn    string
+		mockRepoFn  func(m *MockRepository)
+		expectedErr error
+	}{
+		{
+			name:        "invalid amount",
+			tokenIn:     token,
+			amountIn:    "-1",
+			mockRepoFn:  func(m *MockRepository) {},
+			expectedErr: errors.New("invalid amountIn"),
+		},
+		{
+			name:     "unknown pool",
+			tokenIn:  token,
+			amountIn: "1",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, pool).Return((*Pool)(nil), nil).Once()
+			},
+			expectedErr: ErrPoolNotFound,
+		},
+		{
+			name:     "token not in pool",
+			tokenIn:  "0x6b175474e89094c44da98b954eedeac495271d0f",
+			amountIn: "1",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, pool).Return(p, nil).Once()
+			},
+			expectedErr: errors.New("tokenIn is not a token of the pool"),
+		},
+		{
+			name:     "amount below token precision",
+			tokenIn:  token,
+			amountIn: "0.0000001",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, pool).Return(p, nil).Once()
+			},
+			expectedErr: errors.New("amountIn too small"),
+		},
+		{
+			name:     "too many ticks",
+			tokenIn:  token,
+			amountIn: "1",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetPool", mock.Anything, pool).Return(p, nil).Once()
+				m.On("GetTicks", mock.Anything, pool).Return([]Tick(nil), paging.ErrTooManyItems).Once()
+			},
+			expectedErr: errors.New("too many ticks"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			test.mockRepoFn(mockRepo)
+
+			svc := NewPoolService(mockRepo)
+			_, err := svc.GetQuoteService(context.Background(), pool, test.tokenIn, test.amountIn)
+
+			assert.Error(t, err)
+			assert.Equal(t, test.expectedErr.Error(), err.Error())
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
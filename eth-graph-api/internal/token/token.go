@@ -1,13 +1,152 @@
 package token
 
+type Token struct {
+	ID       string `json:"id" graphql:"id"`
+	Symbol   string `json:"symbol" graphql:"symbol"`
+	Decimals string `json:"decimals" graphql:"decimals"`
+}
+
+// TokenData is the token entity as indexed by the subgraph
+type TokenData struct {
+	ID                  string `json:"id" graphql:"id"`
+	Symbol              string `json:"symbol" graphql:"symbol"`
+	Name                string `json:"name" graphql:"name"`
+	Decimals            string `json:"decimals" graphql:"decimals"`
+	TotalSupply         string `json:"totalSupply" graphql:"totalSupply"`
+	DerivedETH          string `json:"derivedETH" graphql:"derivedETH"`
+	TotalValueLockedUSD string `json:"totalValueLockedUSD" graphql:"totalValueLockedUSD"`
+	PoolCount           string `json:"poolCount" graphql:"poolCount"`
+	TxCount             string `json:"txCount" graphql:"txCount"`
+	VolumeUSD           string `json:"volumeUSD" graphql:"volumeUSD"`
+}
+
+// Bundle holds the subgraph-wide values, such as the price of ETH in USD
+type Bundle struct {
+	EthPriceUSD string `json:"ethPriceUSD" graphql:"ethPriceUSD"`
+}
+
+// TokenDetails describes a token along with its current price in USD
+type TokenDetails struct {
+	ID                  string `json:"id"`
+	Symbol              string `json:"symbol"`
+	Name                string `json:"name"`
+	Decimals            string `json:"decimals"`
+	TotalSupply         string `json:"totalSupply"`
+	DerivedETH          string `json:"derivedETH"`
+	PriceUSD            string `json:"priceUSD"`
+	TotalValueLockedUSD string `json:"totalValueLockedUSD"`
+	PoolCount           string `json:"poolCount"`
+	TxCount             string `json:"txCount"`
+	VolumeUSD           string `json:"volumeUSD"`
+}
+
 type Pool struct {
-	ID string `json:"id"`
+	ID                  string `json:"id" graphql:"id"`
+	FeeTier             string `json:"feeTier" graphql:"feeTier"`
+	Token0              Token  `json:"token0" graphql:"token0"`
+	Token1              Token  `json:"token1" graphql:"token1"`
+	TotalValueLockedUSD string `json:"totalValueLockedUSD" graphql:"totalValueLockedUSD"`
+	VolumeUSD           string `json:"volumeUSD" graphql:"volumeUSD"`
+	TxCount             string `json:"txCount" graphql:"txCount"`
+}
+
+// TokenPool is a pool as seen from one of its tokens, with the other token as Counterparty
+type TokenPool struct {
+	ID                  string `json:"id"`
+	FeeTier             string `json:"feeTier"`
+	Counterparty        Token  `json:"counterparty"`
+	TotalValueLockedUSD string `json:"totalValueLockedUSD"`
+	VolumeUSD           string `json:"volumeUSD"`
+	TxCount             string `json:"txCount"`
+}
+
+// PoolsParams holds the raw query parameters of a pools-by-token request
+type PoolsParams struct {
+	Limit          string
+	Cursor         string
+	OrderBy        string
+	OrderDirection string
+	FeeTier        string
+	MinTvlUSD      string
+	Counterparty   string
+}
+
+// PoolsFilter holds the validated paging, ordering and filtering of a pools-by-token query.
+// Empty FeeTier, MinTvlUSD and Counterparty mean no filtering on that attribute
+type PoolsFilter struct {
+	Limit          int
+	Cursor         string
+	OrderBy        string
+	OrderDirection string
+	FeeTier        string
+	MinTvlUSD      string
+	Counterparty   string
+}
+
+// VolumeParams holds the raw query parameters of a volume request
+type VolumeParams struct {
+	From     string
+	To       string
+	Interval string
+	Exact    string
 }
 
 type Volume struct {
-	Volume string `json:"volume"`
+	Volume   string `json:"volume"`
+	Interval string `json:"interval,omitempty"`
+	Exact    bool   `json:"exact"`
+}
+
+// Swap is a single swap of the token, used to measure volume more precisely than the time series allow,
+// and to price the token over shorter intervals than they have. The token is either Token0 of the swap,
+// its amount being Amount0, or token1, its amount being Amount1
+type Swap struct {
+	ID        string `json:"id" graphql:"id"`
+	Timestamp string `json:"timestamp" graphql:"timestamp"`
+	LogIndex  string `json:"logIndex" graphql:"logIndex"`
+	Token0    Token  `json:"token0" graphql:"token0"`
+	Amount0   string `json:"amount0" graphql:"amount0"`
+	Amount1   string `json:"amount1" graphql:"amount1"`
+	AmountUSD string `json:"amountUSD" graphql:"amountUSD"`
+}
+
+// VolumePoint is a single bucket (an hour or a day) of the token's volume time series, starting at Date (UNIX timestamp).
+// Volume is in token units, the other amounts are in USD
+type VolumePoint struct {
+	Date                int64  `json:"date"`
+	VolumeUSD           string `json:"volumeUSD"`
+	Volume              string `json:"volume"`
+	FeesUSD             string `json:"feesUSD"`
+	TotalValueLockedUSD string `json:"totalValueLockedUSD"`
+	PriceUSD            string `json:"priceUSD"`
+	Open                string `json:"open"`
+	High                string `json:"high"`
+	Low                 string `json:"low"`
+	Close               string `json:"close"`
 }
 
 type TokenDayData struct {
-	VolumeUSD string `json:"volumeUSD"graphql:"volumeUSD"`
+	Date                int64  `json:"date" graphql:"date"`
+	VolumeUSD           string `json:"volumeUSD" graphql:"volumeUSD"`
+	Volume              string `json:"volume" graphql:"volume"`
+	FeesUSD             string `json:"feesUSD" graphql:"feesUSD"`
+	TotalValueLockedUSD string `json:"totalValueLockedUSD" graphql:"totalValueLockedUSD"`
+	PriceUSD            string `json:"priceUSD" graphql:"priceUSD"`
+	Open                string `json:"open" graphql:"open"`
+	High                string `json:"high" graphql:"high"`
+	Low                 string `json:"low" graphql:"low"`
+	Close               string `json:"close" graphql:"close"`
+}
+
+type TokenHourData struct {
+	PeriodStartUnix     int64  `json:"periodStartUnix" graphql:"periodStartUnix"`
+	VolumeUSD           string `json:"volumeUSD" graphql:"volumeUSD"`
+	Volume              string `json:"volume" graphql:"volume"`
+	FeesUSD             string `json:"feesUSD" graphql:"feesUSD"`
+	TotalValueLockedUSD string `json:"totalValueLockedUSD" graphql:"totalValueLockedUSD"`
+	PriceUSD            string `json:"priceUSD" graphql:"priceUSD"`
+	Open                string `json:"open" graphql:"open"`
+	High                string `json:"high" graphql:"high"`
+	Low                 string `json:"low" graphql:"low"`
+	Close               string `json:"close" graphql:"close"`
 }
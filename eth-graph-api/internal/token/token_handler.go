@@ -3,9 +3,12 @@ package token
 import (
 	"context"
 	"errors"
+	"eth-graph-api/pkg/candle"
 	jh "eth-graph-api/pkg/json_helper"
+	"eth-graph-api/pkg/paging"
 	"github.com/go-chi/chi/v5"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 )
@@ -16,9 +19,57 @@ type Handler struct {
 	TokenService Service
 }
 
+// GetTokenHandler is an HTTP handler function that retrieves the details of a specific token.
+// The token parameter is extracted from the URL.
+// It responds with JSON-encoded token data, a 404 if the token is unknown,
+// or appropriate error responses.
+func (h *Handler) GetTokenHandler(w http.ResponseWriter, r *http.Request) {
+
+	token := chi.URLParam(r, "token")
+	if token == "" {
+		err := jh.ErrorJSON(w, errors.New("token cannot be empty"), http.StatusBadRequest)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	details, err := h.TokenService.GetTokenService(ctx, token)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else if errors.Is(err, ErrTokenNotFound) {
+			err = jh.ErrorJSON(w, err, http.StatusNotFound)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else {
+			err = jh.ErrorJSON(w, err, http.StatusBadRequest)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	err = jh.WriteJSON(w, http.StatusOK, details)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
 // GetPoolsByTokenHandler is an HTTP handler function that retrieves pool data for a specific token.
 // The token parameter is extracted from the URL,
-// and optionally a 'first' query parameter can specify the maximum number of results to return.
+// and optionally a 'limit' (or 'first') query parameter can specify the maximum number of results to return,
+// 'cursor' the 'next_cursor' of a previous response to continue after,
+// 'orderBy' (tvl, volumeUSD, feeTier, txCount) and 'orderDirection' (asc, desc) their order,
+// while 'feeTier', 'minTvlUSD' and 'counterparty' narrow down the returned pools.
 // It responds with JSON-encoded pool data or appropriate error responses.
 func (h *Handler) GetPoolsByTokenHandler(w http.ResponseWriter, r *http.Request) {
 
@@ -32,15 +83,26 @@ func (h *Handler) GetPoolsByTokenHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	queryParams := r.URL.Query()
-	firstStr := queryParams.Get("first")
-	if firstStr == "" {
-		firstStr = "5"
+	params := PoolsParams{
+		Limit:          queryParams.Get("limit"),
+		Cursor:         queryParams.Get("cursor"),
+		OrderBy:        queryParams.Get("orderBy"),
+		OrderDirection: queryParams.Get("orderDirection"),
+		FeeTier:        queryParams.Get("feeTier"),
+		MinTvlUSD:      queryParams.Get("minTvlUSD"),
+		Counterparty:   queryParams.Get("counterparty"),
+	}
+	if params.Limit == "" {
+		params.Limit = queryParams.Get("first")
+	}
+	if params.Limit == "" {
+		params.Limit = "5"
 	}
 
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	pools, err := h.TokenService.GetPoolsByTokenService(ctx, token, firstStr)
+	pools, err := h.TokenService.GetPoolsByTokenService(ctx, token, params)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
@@ -56,14 +118,14 @@ func (h *Handler) GetPoolsByTokenHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = jh.WriteJSON(w, http.StatusOK, pools)
+	err = jh.WriteJSON(w, http.StatusOK, pools, paging.LinkHeader(r, pools.NextCursor))
 	if err != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
 // GetVolumeHandler is an HTTP handler function that retrieves token volume data.
-// It extracts 'token', 'from', and 'to' parameters from the request,
+// It extracts 'token', 'from', 'to', 'interval' (hour or day) and 'exact' parameters from the request,
 // then utilizes TokenService to retrieve and respond with the volume data,
 // or handle errors appropriately.
 func (h *Handler) GetVolumeHandler(w http.ResponseWriter, r *http.Request) {
@@ -77,25 +139,73 @@ func (h *Handler) GetVolumeHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	queryParams := r.URL.Query()
-	now := time.Now()
-	nowTimestamp := now.Unix()
+	fromStr, toStr := timeRange(queryParams, 24*time.Hour)
+	params := VolumeParams{
+		From:     fromStr,
+		To:       toStr,
+		Interval: queryParams.Get("interval"),
+		Exact:    queryParams.Get("exact"),
+	}
 
-	toStr := queryParams.Get("to")
-	if toStr == "" {
-		toStr = strconv.FormatInt(nowTimestamp, 10)
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	volume, err := h.TokenService.GetVolumeService(ctx, token, params)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else {
+			err = jh.ErrorJSON(w, err, http.StatusBadRequest)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}
+		return
 	}
 
-	fromStr := queryParams.Get("from")
-	if fromStr == "" {
-		yesterday := now.Add(-24 * time.Hour)
-		yesterdayTimestamp := yesterday.Unix()
-		fromStr = strconv.FormatInt(yesterdayTimestamp, 10)
+	err = jh.WriteJSON(w, http.StatusOK, volume)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// GetDailyVolumeHandler is an HTTP handler function that retrieves the token volume time series,
+// one row per day. It extracts 'token', 'from', and 'to' parameters from the request,
+// defaulting to the last 30 days, then utilizes TokenService to retrieve and respond
+// with the time series, or handle errors appropriately.
+func (h *Handler) GetDailyVolumeHandler(w http.ResponseWriter, r *http.Request) {
+	h.writeVolumeSeries(w, r, IntervalDay, 30*24*time.Hour)
+}
+
+// GetHourlyVolumeHandler is an HTTP handler function that retrieves the token volume time series,
+// one row per hour. It extracts 'token', 'from', and 'to' parameters from the request,
+// defaulting to the last 24 hours, then utilizes TokenService to retrieve and respond
+// with the time series, or handle errors appropriately.
+func (h *Handler) GetHourlyVolumeHandler(w http.ResponseWriter, r *http.Request) {
+	h.writeVolumeSeries(w, r, IntervalHour, 24*time.Hour)
+}
+
+// writeVolumeSeries responds with the token volume time series of the given `interval`,
+// where a missing range defaults to the last `window`.
+func (h *Handler) writeVolumeSeries(w http.ResponseWriter, r *http.Request, interval string, window time.Duration) {
+	token := chi.URLParam(r, "token")
+	if token == "" {
+		err := jh.ErrorJSON(w, errors.New("token cannot be empty"), http.StatusBadRequest)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
 	}
 
+	fromStr, toStr := timeRange(r.URL.Query(), window)
+
 	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
 	defer cancel()
 
-	volume, err := h.TokenService.GetVolumeService(ctx, token, fromStr, toStr)
+	points, err := h.TokenService.GetVolumeSeriesService(ctx, token, fromStr, toStr, interval)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
@@ -111,8 +221,81 @@ func (h *Handler) GetVolumeHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = jh.WriteJSON(w, http.StatusOK, volume)
+	err = jh.WriteJSON(w, http.StatusOK, points)
+	if err != nil {
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+// GetCandlesHandler is an HTTP handler function that retrieves the candles of the token price in USD.
+// It extracts 'token' from the URL and 'interval' ("5m", "15m", "1h", the default, "4h" or "1d"), 'from', and 'to'
+// from the query, where a missing range defaults to the last candle.DefaultWindow of the interval,
+// then utilizes TokenService to retrieve and respond with the candles, or handle errors appropriately.
+func (h *Handler) GetCandlesHandler(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	if token == "" {
+		err := jh.ErrorJSON(w, errors.New("token cannot be empty"), http.StatusBadRequest)
+		if err != nil {
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	queryParams := r.URL.Query()
+
+	interval := queryParams.Get("interval")
+	if interval == "" {
+		interval = "1h"
+	}
+
+	window := 24 * time.Hour
+	if seconds, ok := candle.ParseInterval(interval); ok {
+		window = time.Duration(candle.DefaultWindow(seconds)) * time.Second
+	}
+
+	fromStr, toStr := timeRange(queryParams, window)
+
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	candles, err := h.TokenService.GetCandlesService(ctx, token, fromStr, toStr, interval)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			err = jh.ErrorJSON(w, errors.New("request timeout"), http.StatusRequestTimeout)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		} else {
+			err = jh.ErrorJSON(w, err, http.StatusBadRequest)
+			if err != nil {
+				http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			}
+		}
+		return
+	}
+
+	err = jh.WriteJSON(w, http.StatusOK, candles)
 	if err != nil {
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
+
+// timeRange extracts the 'from' and 'to' UNIX timestamps from the query parameters.
+// A missing 'to' defaults to now, and a missing 'from' to `window` before 'to'.
+func timeRange(queryParams url.Values, window time.Duration) (string, string) {
+	to := time.Now().Unix()
+
+	toStr := queryParams.Get("to")
+	if toStr == "" {
+		toStr = strconv.FormatInt(to, 10)
+	} else if ts, err := strconv.ParseInt(toStr, 10, 64); err == nil {
+		to = ts
+	}
+
+	fromStr := queryParams.Get("from")
+	if fromStr == "" {
+		fromStr = strconv.FormatInt(to-int64(window/time.Second), 10)
+	}
+
+	return fromStr, toStr
+}
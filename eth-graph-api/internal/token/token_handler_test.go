@@ -2,26 +2,100 @@ package token
 
 import (
 	"context"
+	"errors"
+	"eth-graph-api/pkg/candle"
+	"eth-graph-api/pkg/paging"
 	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"testing"
+	"time"
 )
 
 type MockService struct {
 	mock.Mock
 }
 
-func (m *MockService) GetVolumeService(ctx context.Context, token, fromStr, toStr string) (*Volume, error) {
-	args := m.Called(ctx, token, fromStr, toStr)
+func (m *MockService) GetTokenService(ctx context.Context, token string) (*TokenDetails, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(*TokenDetails), args.Error(1)
+}
+
+func (m *MockService) GetVolumeService(ctx context.Context, token string, params VolumeParams) (*Volume, error) {
+	args := m.Called(ctx, token, params)
 	return args.Get(0).(*Volume), args.Error(1)
 }
 
-func (m *MockService) GetPoolsByTokenService(ctx context.Context, token string, firstStr string) ([]Pool, error) {
-	args := m.Called(ctx, token, firstStr)
-	return args.Get(0).([]Pool), args.Error(1)
+func (m *MockService) GetVolumeSeriesService(ctx context.Context, token, fromStr, toStr, interval string) ([]VolumePoint, error) {
+	args := m.Called(ctx, token, fromStr, toStr, interval)
+	return args.Get(0).([]VolumePoint), args.Error(1)
+}
+
+func (m *MockService) GetCandlesService(ctx context.Context, token, fromStr, toStr, interval string) ([]candle.Candle, error) {
+	args := m.Called(ctx, token, fromStr, toStr, interval)
+	return args.Get(0).([]candle.Candle), args.Error(1)
+}
+
+func (m *MockService) GetPoolsByTokenService(ctx context.Context, token string, params PoolsParams) (*paging.Page[TokenPool], error) {
+	args := m.Called(ctx, token, params)
+	return args.Get(0).(*paging.Page[TokenPool]), args.Error(1)
+}
+
+func TestGetTokenHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		token          string
+		mockSvcOutput  *TokenDetails
+		mockSvcErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			token:          "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			mockSvcOutput:  &TokenDetails{ID: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", Symbol: "WETH"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid token",
+			token:          "invalid",
+			mockSvcErr:     errors.New("invalid token"),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unknown token",
+			token:          "0x0000000000000000000000000000000000000000",
+			mockSvcErr:     ErrTokenNotFound,
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("GetTokenService", mock.Anything, tt.token).Return(tt.mockSvcOutput, tt.mockSvcErr).Once()
+
+			handler := &Handler{
+				TokenService: mockService,
+			}
+
+			req, err := http.NewRequest("GET", "/v1/tokens/"+tt.token, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Get("/v1/tokens/{token}", handler.GetTokenHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
 }
 
 func TestGetPoolsByTokenHandler(t *testing.T) {
@@ -34,6 +108,7 @@ func TestGetPoolsByTokenHandler(t *testing.T) {
 	tests := []struct {
 		name           string
 		token          string
+		query          string
 		mockServiceFn  func()
 		expectedStatus int
 	}{
@@ -41,8 +116,24 @@ func TestGetPoolsByTokenHandler(t *testing.T) {
 			name:  "success",
 			token: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
 			mockServiceFn: func() {
-				mockService.On("GetPoolsByTokenService", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", "5").
-					Return([]Pool{{ID: "0x0000d36ab86d213c14d93cd5ae78615a20596505"}}, nil).Once()
+				mockService.On("GetPoolsByTokenService", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", PoolsParams{Limit: "5"}).
+					Return(&paging.Page[TokenPool]{Data: []TokenPool{{ID: "0x0000d36ab86d213c14d93cd5ae78615a20596505"}}}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "success with ordering and filters",
+			token: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			query: "?first=20&cursor=100,0xabc&orderBy=volumeUSD&orderDirection=asc&feeTier=500&minTvlUSD=1000",
+			mockServiceFn: func() {
+				mockService.On("GetPoolsByTokenService", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", PoolsParams{
+					Limit:          "20",
+					Cursor:         "100,0xabc",
+					OrderBy:        "volumeUSD",
+					OrderDirection: "asc",
+					FeeTier:        "500",
+					MinTvlUSD:      "1000",
+				}).Return(&paging.Page[TokenPool]{Data: []TokenPool{}}, nil).Once()
 			},
 			expectedStatus: http.StatusOK,
 		},
@@ -50,7 +141,7 @@ func TestGetPoolsByTokenHandler(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			req, err := http.NewRequest("GET", "/v1/tokens/"+tt.token+"/pools", nil)
+			req, err := http.NewRequest("GET", "/v1/tokens/"+tt.token+"/pools"+tt.query, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -86,7 +177,9 @@ func TestGetVolumeHandler(t *testing.T) {
 		{
 			name: "success",
 			mockServiceFn: func() {
-				mockService.On("GetVolumeService", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", mock.Anything, mock.Anything).
+				mockService.On("GetVolumeService", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", mock.MatchedBy(func(p VolumeParams) bool {
+					return p.From != "" && p.To != "" && p.Interval == "" && p.Exact == ""
+				})).
 					Return(&Volume{Volume: "8039962301.3240150269"}, nil)
 			},
 			token:          "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
@@ -116,3 +209,165 @@ func TestGetVolumeHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestGetVolumeHandlerExactTimeout(t *testing.T) {
+	token := "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetSwapsByToken", mock.Anything, token, int64(1633046400), int64(1633050000)).
+		Return([]Swap(nil), context.DeadlineExceeded).Once()
+
+	handler := &Handler{
+		TokenService: NewTokenService(mockRepo),
+	}
+
+	req, err := http.NewRequest("GET", "/v1/tokens/"+token+"/volume?from=1633046400&to=1633050000&exact=true", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	r := chi.NewRouter()
+	r.Get("/v1/tokens/{token}/volume", handler.GetVolumeHandler)
+	r.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusRequestTimeout, rr.Code)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetDailyVolumeHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		mockSvcOutput  []VolumePoint
+		mockSvcErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			url:            "/v1/tokens/0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2/volume/daily?from=1633036800&to=1633209600",
+			mockSvcOutput:  []VolumePoint{{Date: 1633046400, VolumeUSD: "100"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid range",
+			url:            "/v1/tokens/0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2/volume/daily?from=1633209600&to=1633036800",
+			mockSvcErr:     errors.New("invalid range"),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("GetVolumeSeriesService", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", mock.Anything, mock.Anything, "day").
+				Return(tt.mockSvcOutput, tt.mockSvcErr).Once()
+
+			handler := &Handler{
+				TokenService: mockService,
+			}
+
+			req, err := http.NewRequest("GET", tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Get("/v1/tokens/{token}/volume/daily", handler.GetDailyVolumeHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetCandlesHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		url            string
+		interval       string
+		mockSvcOutput  []candle.Candle
+		mockSvcErr     error
+		expectedStatus int
+	}{
+		{
+			name:           "success",
+			url:            "/v1/tokens/0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2/candles?interval=5m&from=1697029200&to=1697030100",
+			interval:       "5m",
+			mockSvcOutput:  []candle.Candle{{Date: 1697029200, Open: "1575.5"}},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "hourly by default",
+			url:            "/v1/tokens/0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2/candles",
+			interval:       "1h",
+			mockSvcOutput:  []candle.Candle{},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "range too long",
+			url:            "/v1/tokens/0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2/candles?interval=5m&from=1690000000&to=1697030100",
+			interval:       "5m",
+			mockSvcErr:     errors.New("range too long for 5m interval"),
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockService)
+			mockService.On("GetCandlesService", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", mock.Anything, mock.Anything, tt.interval).
+				Return(tt.mockSvcOutput, tt.mockSvcErr).Once()
+
+			handler := &Handler{
+				TokenService: mockService,
+			}
+
+			req, err := http.NewRequest("GET", tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rr := httptest.NewRecorder()
+
+			r := chi.NewRouter()
+			r.Get("/v1/tokens/{token}/candles", handler.GetCandlesHandler)
+			r.ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.expectedStatus, rr.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantFrom string
+		wantTo   string
+	}{
+		{name: "full range", query: "from=1633036800&to=1633209600", wantFrom: "1633036800", wantTo: "1633209600"},
+		{name: "only to", query: "to=1633209600", wantFrom: "1633123200", wantTo: "1633209600"},
+		{name: "only from", query: "from=1633036800", wantFrom: "1633036800"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			assert.NoError(t, err)
+
+			from, to := timeRange(query, 24*time.Hour)
+
+			assert.Equal(t, tt.wantFrom, from)
+			if tt.wantTo != "" {
+				assert.Equal(t, tt.wantTo, to)
+			}
+		})
+	}
+
+	from, to := timeRange(url.Values{}, 24*time.Hour)
+	fromTs, _ := strconv.ParseInt(from, 10, 64)
+	toTs, _ := strconv.ParseInt(to, 10, 64)
+	assert.Equal(t, int64(86400), toTs-fromTs)
+}
@@ -3,7 +3,11 @@ package token
 import (
 	"context"
 	"eth-graph-api/pkg/logger"
+	"eth-graph-api/pkg/paging"
+	"eth-graph-api/pkg/subgraph"
 	"github.com/shurcooL/graphql"
+	"strconv"
+	"strings"
 )
 
 // GraphClient Defines the GraphClient interface which encapsulates the ability
@@ -20,8 +24,12 @@ type GraphClient interface {
 // Repository represents an interface defining contracts for retrieving token data.
 // Implementers should provide mechanisms to retrieve data about token pools and volume.
 type Repository interface {
-	GetPoolsByToken(ctx context.Context, token string, first int) ([]Pool, error)
-	GetVolume(ctx context.Context, token string, from int64, to int64) ([]TokenDayData, error)
+	GetToken(ctx context.Context, token string) (*TokenData, error)
+	GetBundle(ctx context.Context) (*Bundle, error)
+	GetPoolsByToken(ctx context.Context, token string, filter PoolsFilter) ([]Pool, string, error)
+	GetTokenDayData(ctx context.Context, token string, from int64, to int64) ([]TokenDayData, error)
+	GetTokenHourData(ctx context.Context, token string, from int64, to int64) ([]TokenHourData, error)
+	GetSwapsByToken(ctx context.Context, token string, from int64, to int64) ([]Swap, error)
 }
 
 // tokenRepository is a structure that implements the Repository interface,
@@ -37,49 +45,290 @@ func NewTokenRepository(graphClient GraphClient) Repository {
 	}
 }
 
-// GetPoolsByToken performs a GraphQL query to retrieve pools associated
-// with the given `token`, and limited by the `first` parameter, executing within `ctx` context.
-// It returns slices of Pool or an error if the query operation fails.
-func (tr *tokenRepository) GetPoolsByToken(ctx context.Context, token string, first int) ([]Pool, error) {
+// GetToken performs a GraphQL query to retrieve the token with the given `token` ID,
+// executing within `ctx` context.
+// It returns the TokenData, nil if no such token exists, or an error if the query operation fails.
+func (tr *tokenRepository) GetToken(ctx context.Context, token string) (*TokenData, error) {
 
 	var query struct {
-		Pools []Pool `graphql:"pools(first: $first, where: { or: [{ token0: $token }, { token1: $token }] })"`
+		Token *TokenData `graphql:"token(id: $token)"`
 	}
 
 	vars := map[string]interface{}{
-		"token": graphql.String(token),
-		"first": graphql.Int(first),
+		"token": graphql.ID(token),
 	}
 
 	err := tr.graphClient.Query(ctx, &query, vars)
 	if err != nil {
-		logger.Error("GetPoolsByToken error", "error", err)
+		logger.Error("GetToken error", "error", err)
 		return nil, err
 	}
 
-	return query.Pools, nil
+	return query.Token, nil
 }
 
-// GetVolume performs a GraphQL query to retrieve volume data associated
-// with the given `token` between `from` and `to` timestamps, executing within `ctx` context.
-// It returns slices of TokenDayData or an error if the query operation fails.
-func (tr *tokenRepository) GetVolume(ctx context.Context, token string, from int64, to int64) ([]TokenDayData, error) {
+// GetBundle performs a GraphQL query to retrieve the subgraph-wide values,
+// such as the price of ETH in USD, executing within `ctx` context.
+// It returns the Bundle or an error if the query operation fails.
+func (tr *tokenRepository) GetBundle(ctx context.Context) (*Bundle, error) {
 
 	var query struct {
-		TokenDayDatas []TokenDayData `graphql:"tokenDayDatas(where: {token: $token, date_gte: $from, date_lte: $to})"`
+		Bundle Bundle `graphql:"bundle(id: \"1\")"`
 	}
 
-	vars := map[string]interface{}{
-		"token": graphql.String(token),
-		"from":  graphql.Int(from),
-		"to":    graphql.Int(to),
+	err := tr.graphClient.Query(ctx, &query, nil)
+	if err != nil {
+		logger.Error("GetBundle error", "error", err)
+		return nil, err
 	}
 
-	err := tr.graphClient.Query(ctx, &query, vars)
+	return &query.Bundle, nil
+}
+
+// GetPoolsByToken performs GraphQL queries to retrieve pools associated
+// with the given `token`, ordered and filtered according to `filter`,
+// executing within `ctx` context. It walks the pools page by page, starting after `filter.Cursor`,
+// until `filter.Limit` pools are gathered.
+// It returns slices of Pool, the cursor to continue after them (empty if there are no more pools),
+// or an error if the query operation fails.
+func (tr *tokenRepository) GetPoolsByToken(ctx context.Context, token string, filter PoolsFilter) ([]Pool, string, error) {
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Pool, error) {
+		where, err := poolsWhere(token, filter, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		var query struct {
+			Pools []Pool `graphql:"pools(first: $first, orderBy: $orderBy, orderDirection: $orderDirection, where: $where)"`
+		}
+
+		vars := map[string]interface{}{
+			"first":          graphql.Int(first),
+			"orderBy":        subgraph.Pool_orderBy(filter.OrderBy),
+			"orderDirection": subgraph.OrderDirection(filter.OrderDirection),
+			"where":          where,
+		}
+
+		err = tr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("GetPoolsByToken error", "error", err)
+			return nil, err
+		}
+
+		return query.Pools, nil
+	}
+
+	cursorOf := func(p Pool) string {
+		return poolSortValue(p, filter.OrderBy) + "," + p.ID
+	}
+
+	return paging.Collect(ctx, fetch, cursorOf, filter.Cursor, filter.Limit)
+}
+
+// poolSortValue returns the value of the pool field named by `orderBy`.
+func poolSortValue(p Pool, orderBy string) string {
+	switch orderBy {
+	case "volumeUSD":
+		return p.VolumeUSD
+	case "feeTier":
+		return p.FeeTier
+	case "txCount":
+		return p.TxCount
+	}
+
+	return p.TotalValueLockedUSD
+}
+
+// poolsWhere builds the "where" argument matching pools that contain `token` on either side.
+// The fee tier and TVL filters go in both sides, as the subgraph rejects them next to "or".
+// Pools are ordered by the `filter.OrderBy` field and then by ID, so a non-empty `cursor`,
+// holding the value of that field and the ID of the last pool seen, splits every branch in two:
+// pools past the value, and pools with the same value past the ID.
+func poolsWhere(token string, filter PoolsFilter, cursor string) (subgraph.Pool_filter, error) {
+	side0 := subgraph.Pool_filter{"token0": token}
+	side1 := subgraph.Pool_filter{"token1": token}
+
+	if filter.Counterparty != "" {
+		side0["token1"] = filter.Counterparty
+		side1["token0"] = filter.Counterparty
+	}
+
+	for _, side := range []subgraph.Pool_filter{side0, side1} {
+		if filter.FeeTier != "" {
+			side["feeTier"] = filter.FeeTier
+		}
+		if filter.MinTvlUSD != "" {
+			side["totalValueLockedUSD_gte"] = filter.MinTvlUSD
+		}
+	}
+
+	if cursor == "" {
+		return subgraph.Pool_filter{"or": []subgraph.Pool_filter{side0, side1}}, nil
+	}
+
+	value, id, ok := strings.Cut(cursor, ",")
+	if !ok || value == "" || id == "" {
+		return nil, paging.ErrInvalidCursor
+	}
+
+	op := "_gt"
+	if filter.OrderDirection == "desc" {
+		op = "_lt"
+	}
+
+	var branches []subgraph.Pool_filter
+	for _, side := range []subgraph.Pool_filter{side0, side1} {
+		pastValue := subgraph.Pool_filter{filter.OrderBy + op: value}
+		pastID := subgraph.Pool_filter{filter.OrderBy: value, "id" + op: id}
+		for k, v := range side {
+			pastValue[k] = v
+			pastID[k] = v
+		}
+		branches = append(branches, pastValue, pastID)
+	}
+
+	return subgraph.Pool_filter{"or": branches}, nil
+}
+
+// GetTokenDayData performs GraphQL queries to retrieve daily data associated
+// with the given `token` for the days starting between `from` and `to` timestamps,
+// ordered by date, executing within `ctx` context. It walks all pages of the range.
+// It returns slices of TokenDayData, paging.ErrTooManyItems, or an error if the query operation fails.
+func (tr *tokenRepository) GetTokenDayData(ctx context.Context, token string, from int64, to int64) ([]TokenDayData, error) {
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]TokenDayData, error) {
+		after, err := timeCursor(cursor, from)
+		if err != nil {
+			return nil, err
+		}
+
+		var query struct {
+			TokenDayDatas []TokenDayData `graphql:"tokenDayDatas(first: $first, orderBy: date, orderDirection: asc, where: {token: $token, date_gt: $after, date_lte: $to})"`
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"token": graphql.String(token),
+			"after": graphql.Int(after),
+			"to":    graphql.Int(to),
+		}
+
+		err = tr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("GetTokenDayData error", "error", err)
+			return nil, err
+		}
+
+		return query.TokenDayDatas, nil
+	}
+
+	cursorOf := func(d TokenDayData) string {
+		return strconv.FormatInt(d.Date, 10)
+	}
+
+	return paging.CollectAll(ctx, fetch, cursorOf)
+}
+
+// GetTokenHourData performs GraphQL queries to retrieve hourly data associated
+// with the given `token` for the hours starting between `from` and `to` timestamps,
+// ordered by time, executing within `ctx` context. It walks all pages of the range.
+// It returns slices of TokenHourData, paging.ErrTooManyItems, or an error if the query operation fails.
+func (tr *tokenRepository) GetTokenHourData(ctx context.Context, token string, from int64, to int64) ([]TokenHourData, error) {
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]TokenHourData, error) {
+		after, err := timeCursor(cursor, from)
+		if err != nil {
+			return nil, err
+		}
+
+		var query struct {
+			TokenHourDatas []TokenHourData `graphql:"tokenHourDatas(first: $first, orderBy: periodStartUnix, orderDirection: asc, where: {token: $token, periodStartUnix_gt: $after, periodStartUnix_lte: $to})"`
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"token": graphql.String(token),
+			"after": graphql.Int(after),
+			"to":    graphql.Int(to),
+		}
+
+		err = tr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("GetTokenHourData error", "error", err)
+			return nil, err
+		}
+
+		return query.TokenHourDatas, nil
+	}
+
+	cursorOf := func(d TokenHourData) string {
+		return strconv.FormatInt(d.PeriodStartUnix, 10)
+	}
+
+	return paging.CollectAll(ctx, fetch, cursorOf)
+}
+
+// timeCursor turns the cursor of a time series, the start of the last bucket seen,
+// into the timestamp the next bucket has to start after. Without a cursor,
+// the series starts at `from`.
+func timeCursor(cursor string, from int64) (int64, error) {
+	if cursor == "" {
+		return from - 1, nil
+	}
+
+	after, err := strconv.ParseInt(cursor, 10, 64)
 	if err != nil {
-		logger.Error("GetVolume error", "error", err)
-		return nil, err
+		return 0, paging.ErrInvalidCursor
+	}
+
+	return after, nil
+}
+
+// GetSwapsByToken performs GraphQL queries to retrieve all swaps of pools that contain the given `token`
+// with timestamps between `from` and `to` inclusive, executing within `ctx` context.
+// It walks the swaps ordered by ID, requesting each page after the last ID of the previous one.
+// It returns slices of Swap, paging.ErrTooManyItems, or an error if the query operation fails.
+func (tr *tokenRepository) GetSwapsByToken(ctx context.Context, token string, from int64, to int64) ([]Swap, error) {
+
+	fetch := func(ctx context.Context, cursor string, first int) ([]Swap, error) {
+		var query struct {
+			Swaps []Swap `graphql:"swaps(first: $first, orderBy: id, orderDirection: asc, where: $where)"`
+		}
+
+		vars := map[string]interface{}{
+			"first": graphql.Int(first),
+			"where": swapsWhere(token, from, to, cursor),
+		}
+
+		err := tr.graphClient.Query(ctx, &query, vars)
+		if err != nil {
+			logger.Error("GetSwapsByToken error", "error", err)
+			return nil, err
+		}
+
+		return query.Swaps, nil
+	}
+
+	cursorOf := func(s Swap) string {
+		return s.ID
+	}
+
+	return paging.CollectAll(ctx, fetch, cursorOf)
+}
+
+// swapsWhere builds the "where" argument matching swaps of pools that contain `token` on either side,
+// made between `from` and `to` inclusive, with IDs greater than `cursor` unless it is empty.
+func swapsWhere(token string, from int64, to int64, cursor string) subgraph.Swap_filter {
+	sides := []subgraph.Swap_filter{{"token0": token}, {"token1": token}}
+
+	for _, side := range sides {
+		side["timestamp_gte"] = strconv.FormatInt(from, 10)
+		side["timestamp_lte"] = strconv.FormatInt(to, 10)
+		if cursor != "" {
+			side["id_gt"] = cursor
+		}
 	}
 
-	return query.TokenDayDatas, nil
+	return subgraph.Swap_filter{"or": sides}
 }
@@ -2,6 +2,9 @@ package token
 
 import (
 	"context"
+	"eth-graph-api/pkg/paging"
+	"eth-graph-api/pkg/subgraph"
+	"github.com/shurcooL/graphql"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
@@ -16,24 +19,74 @@ func (m *MockGraphClient) Query(ctx context.Context, query interface{}, variable
 	return args.Error(0)
 }
 
+func TestGetToken(t *testing.T) {
+	mockClient := new(MockGraphClient)
+	repo := NewTokenRepository(mockClient)
+	token := "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+
+	mockClient.On("Query", mock.Anything, mock.Anything, map[string]interface{}{"token": graphql.ID(token)}).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(1).(*struct {
+			Token *TokenData `graphql:"token(id: $token)"`
+		})
+		arg.Token = &TokenData{ID: token, Symbol: "WETH", DerivedETH: "1"}
+	})
+
+	data, err := repo.GetToken(context.Background(), token)
+
+	assert.NoError(t, err)
+	assert.Equal(t, &TokenData{ID: token, Symbol: "WETH", DerivedETH: "1"}, data)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestGetBundle(t *testing.T) {
+	mockClient := new(MockGraphClient)
+	repo := NewTokenRepository(mockClient)
+
+	mockClient.On("Query", mock.Anything, mock.Anything, map[string]interface{}(nil)).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(1).(*struct {
+			Bundle Bundle `graphql:"bundle(id: \"1\")"`
+		})
+		arg.Bundle = Bundle{EthPriceUSD: "1600.25"}
+	})
+
+	bundle, err := repo.GetBundle(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1600.25", bundle.EthPriceUSD)
+
+	mockClient.AssertExpectations(t)
+}
+
 func TestGetPoolsByToken(t *testing.T) {
 	mockClient := new(MockGraphClient)
 
 	repo := NewTokenRepository(mockClient)
 
 	token := "test-token"
-	first := 5
-
-	mockClient.On("Query", mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+	filter := PoolsFilter{Limit: 5, OrderBy: "totalValueLockedUSD", OrderDirection: "desc"}
+
+	expectedVars := map[string]interface{}{
+		"first":          graphql.Int(6),
+		"orderBy":        subgraph.Pool_orderBy("totalValueLockedUSD"),
+		"orderDirection": subgraph.OrderDirection("desc"),
+		"where": subgraph.Pool_filter{"or": []subgraph.Pool_filter{
+			{"token0": "test-token"},
+			{"token1": "test-token"},
+		}},
+	}
+
+	mockClient.On("Query", mock.Anything, mock.Anything, expectedVars).Return(nil).Run(func(args mock.Arguments) {
 		arg := args.Get(1).(*struct {
-			Pools []Pool "graphql:\"pools(first: $first, where: { or: [{ token0: $token }, { token1: $token }] })\""
+			Pools []Pool "graphql:\"pools(first: $first, orderBy: $orderBy, orderDirection: $orderDirection, where: $where)\""
 		})
 		arg.Pools = []Pool{{ID: "pool1"}, {ID: "pool2"}}
 	})
 
-	pools, err := repo.GetPoolsByToken(context.Background(), token, first)
+	pools, next, err := repo.GetPoolsByToken(context.Background(), token, filter)
 
 	assert.NoError(t, err)
+	assert.Equal(t, "", next)
 	assert.Len(t, pools, 2)
 	assert.Equal(t, "pool1", pools[0].ID)
 	assert.Equal(t, "pool2", pools[1].ID)
@@ -41,7 +94,36 @@ func TestGetPoolsByToken(t *testing.T) {
 	mockClient.AssertExpectations(t)
 }
 
-func TestGetVolume(t *testing.T) {
+func TestPoolsWhere(t *testing.T) {
+	filter := PoolsFilter{OrderBy: "totalValueLockedUSD", OrderDirection: "desc", FeeTier: "500", MinTvlUSD: "1000", Counterparty: "other-token"}
+
+	where, err := poolsWhere("test-token", filter, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, subgraph.Pool_filter{"or": []subgraph.Pool_filter{
+		{"token0": "test-token", "token1": "other-token", "feeTier": "500", "totalValueLockedUSD_gte": "1000"},
+		{"token1": "test-token", "token0": "other-token", "feeTier": "500", "totalValueLockedUSD_gte": "1000"},
+	}}, where)
+}
+
+func TestPoolsWhereWithCursor(t *testing.T) {
+	filter := PoolsFilter{OrderBy: "volumeUSD", OrderDirection: "desc"}
+
+	where, err := poolsWhere("test-token", filter, "1500.5,0xpool")
+
+	assert.NoError(t, err)
+	assert.Equal(t, subgraph.Pool_filter{"or": []subgraph.Pool_filter{
+		{"token0": "test-token", "volumeUSD_lt": "1500.5"},
+		{"token0": "test-token", "volumeUSD": "1500.5", "id_lt": "0xpool"},
+		{"token1": "test-token", "volumeUSD_lt": "1500.5"},
+		{"token1": "test-token", "volumeUSD": "1500.5", "id_lt": "0xpool"},
+	}}, where)
+
+	_, err = poolsWhere("test-token", filter, "malformed")
+	assert.ErrorIs(t, err, paging.ErrInvalidCursor)
+}
+
+func TestGetTokenDayData(t *testing.T) {
 	mockClient := new(MockGraphClient)
 	repo := NewTokenRepository(mockClient)
 	ctx := context.TODO()
@@ -49,23 +131,82 @@ func TestGetVolume(t *testing.T) {
 	from := int64(1633036800)
 	to := int64(1633123200)
 
-	mockClient.On("Query", ctx, mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+	expectedVars := map[string]interface{}{
+		"first": graphql.Int(paging.PageSize),
+		"token": graphql.String(token),
+		"after": graphql.Int(from - 1),
+		"to":    graphql.Int(to),
+	}
+
+	mockClient.On("Query", ctx, mock.Anything, expectedVars).Return(nil).Run(func(args mock.Arguments) {
 
 		arg := args.Get(1).(*struct {
-			TokenDayDatas []TokenDayData `graphql:"tokenDayDatas(where: {token: $token, date_gte: $from, date_lte: $to})"`
+			TokenDayDatas []TokenDayData `graphql:"tokenDayDatas(first: $first, orderBy: date, orderDirection: asc, where: {token: $token, date_gt: $after, date_lte: $to})"`
 		})
 		arg.TokenDayDatas = []TokenDayData{
-			{VolumeUSD: "100.10"},
-			{VolumeUSD: "200.20"},
+			{Date: 1633046400, VolumeUSD: "100.10"},
+			{Date: 1633132800, VolumeUSD: "200.20"},
 		}
 	})
 
-	volume, err := repo.GetVolume(ctx, token, from, to)
+	volume, err := repo.GetTokenDayData(ctx, token, from, to)
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(volume))
+	assert.Equal(t, int64(1633046400), volume[0].Date)
 	assert.Equal(t, "100.10", volume[0].VolumeUSD)
 	assert.Equal(t, "200.20", volume[1].VolumeUSD)
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestGetSwapsByToken(t *testing.T) {
+	mockClient := new(MockGraphClient)
+	repo := NewTokenRepository(mockClient)
+	token := "0xtoken"
+
+	fullPage := make([]Swap, paging.PageSize)
+	for i := range fullPage {
+		fullPage[i] = Swap{ID: "0xa", AmountUSD: "1"}
+	}
+	fullPage[paging.PageSize-1].ID = "0xlast"
+
+	firstPageVars := map[string]interface{}{
+		"first": graphql.Int(paging.PageSize),
+		"where": swapsWhere(token, 100, 200, ""),
+	}
+	secondPageVars := map[string]interface{}{
+		"first": graphql.Int(paging.PageSize),
+		"where": swapsWhere(token, 100, 200, "0xlast"),
+	}
+
+	mockClient.On("Query", mock.Anything, mock.Anything, firstPageVars).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(1).(*struct {
+			Swaps []Swap `graphql:"swaps(first: $first, orderBy: id, orderDirection: asc, where: $where)"`
+		})
+		arg.Swaps = fullPage
+	}).Once()
+	mockClient.On("Query", mock.Anything, mock.Anything, secondPageVars).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(1).(*struct {
+			Swaps []Swap `graphql:"swaps(first: $first, orderBy: id, orderDirection: asc, where: $where)"`
+		})
+		arg.Swaps = []Swap{{ID: "0xz", AmountUSD: "2"}}
+	}).Once()
+
+	swaps, err := repo.GetSwapsByToken(context.Background(), token, 100, 200)
+
+	assert.NoError(t, err)
+	assert.Len(t, swaps, paging.PageSize+1)
+	assert.Equal(t, "0xz", swaps[paging.PageSize].ID)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestSwapsWhere(t *testing.T) {
+	where := swapsWhere("0xtoken", 100, 200, "0xlast")
+
+	assert.Equal(t, subgraph.Swap_filter{"or": []subgraph.Swap_filter{
+		{"token0": "0xtoken", "timestamp_gte": "100", "timestamp_lte": "200", "id_gt": "0xlast"},
+		{"token1": "0xtoken", "timestamp_gte": "100", "timestamp_lte": "200", "id_gt": "0xlast"},
+	}}, where)
+}
@@ -4,17 +4,49 @@ import (
 	"context"
 	"errors"
 	"eth-graph-api/pkg/calc"
+	"eth-graph-api/pkg/candle"
 	"eth-graph-api/pkg/logger"
+	"eth-graph-api/pkg/paging"
+	"eth-graph-api/pkg/subgraph"
 	"eth-graph-api/pkg/validator"
 	"strconv"
+	"strings"
 )
 
+const (
+	// IntervalHour selects the hourly buckets of the subgraph's time series
+	IntervalHour = "hour"
+	// IntervalDay selects the daily buckets of the subgraph's time series
+	IntervalDay = "day"
+
+	// autoHourlyRange is the longest range for which the volume uses hourly buckets when no interval is given
+	autoHourlyRange = 7 * 24 * 60 * 60
+	// daySeconds is the length of a daily bucket, which always starts at midnight UTC
+	daySeconds = 24 * 60 * 60
+)
+
+// ErrTokenNotFound is returned when the subgraph has no token with the requested ID.
+var ErrTokenNotFound = errors.New("token not found")
+
+// poolsOrderBy maps the accepted values of the pools "orderBy" parameter
+// to the pool fields of the subgraph.
+var poolsOrderBy = map[string]string{
+	"tvl":                 "totalValueLockedUSD",
+	"totalValueLockedUSD": "totalValueLockedUSD",
+	"volumeUSD":           "volumeUSD",
+	"feeTier":             "feeTier",
+	"txCount":             "txCount",
+}
+
 // Service is an interface that defines contracts for interacting
 // with token data, ensuring implementations provide methods for
-// retrieving token pools and volume.
+// retrieving token details, pools, volume and price candles.
 type Service interface {
-	GetPoolsByTokenService(ctx context.Context, token string, firstStr string) ([]Pool, error)
-	GetVolumeService(ctx context.Context, token string, from string, to string) (*Volume, error)
+	GetTokenService(ctx context.Context, token string) (*TokenDetails, error)
+	GetPoolsByTokenService(ctx context.Context, token string, params PoolsParams) (*paging.Page[TokenPool], error)
+	GetVolumeService(ctx context.Context, token string, params VolumeParams) (*Volume, error)
+	GetVolumeSeriesService(ctx context.Context, token string, from string, to string, interval string) ([]VolumePoint, error)
+	GetCandlesService(ctx context.Context, token string, fromStr string, toStr string, interval string) ([]candle.Candle, error)
 }
 
 // tokenService is a concrete implementation of the Service interface,
@@ -31,54 +63,197 @@ func NewTokenService(repo Repository) Service {
 	}
 }
 
-// GetPoolsByTokenService retrieves pools by token, ensuring
-// token and firstStr inputs are validated and parsed correctly,
-// executing within `ctx` context. It returns a slice of Pool or an error.
-func (s *tokenService) GetPoolsByTokenService(ctx context.Context, token string, firstStr string) ([]Pool, error) {
+// GetTokenService retrieves the details of a token, ensuring the token ID
+// is validated and normalized, and prices the token in USD using the ETH price of the subgraph,
+// executing within `ctx` context. It returns TokenDetails, ErrTokenNotFound, or an error.
+func (s *tokenService) GetTokenService(ctx context.Context, token string) (*TokenDetails, error) {
 	if !validator.IsValidToken(token) {
 		return nil, errors.New("invalid token")
 	}
 
-	firstNum, err := strconv.Atoi(firstStr)
+	t, err := s.tokenRepo.GetToken(ctx, strings.ToLower(token))
 	if err != nil {
-		firstNum = 5
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		return nil, errors.New("issue to get token")
 	}
 
-	if !validator.IsValidFirst(firstNum) {
-		firstNum = 5
+	if t == nil {
+		return nil, ErrTokenNotFound
 	}
 
-	return s.tokenRepo.GetPoolsByToken(ctx, token, firstNum)
-}
+	bundle, err := s.tokenRepo.GetBundle(ctx)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, err
+		}
+		return nil, errors.New("issue to get ETH price")
+	}
 
-// GetVolumeService retrieves token volume data within the specified range,
-// ensuring token, fromStr, and toStr inputs are validated and parsed correctly,
-// executing within `ctx` context. It returns a Volume struct or an error.
-func (s *tokenService) GetVolumeService(ctx context.Context, token string, fromStr string, toStr string) (*Volume, error) {
+	priceUSD, err := calc.MultiplyNumbers(t.DerivedETH, bundle.EthPriceUSD)
+	if err != nil {
+		logger.Error("GetTokenService error", "error", err)
+		return nil, errors.New("issue to get token price")
+	}
+
+	details := &TokenDetails{
+		ID:                  t.ID,
+		Symbol:              t.Symbol,
+		Name:                t.Name,
+		Decimals:            t.Decimals,
+		TotalSupply:         t.TotalSupply,
+		DerivedETH:          t.DerivedETH,
+		PriceUSD:            priceUSD,
+		TotalValueLockedUSD: t.TotalValueLockedUSD,
+		PoolCount:           t.PoolCount,
+		TxCount:             t.TxCount,
+		VolumeUSD:           t.VolumeUSD,
+	}
 
+	return details, nil
+}
+
+// GetPoolsByTokenService retrieves pools by token, ensuring
+// token and the paging, ordering and filtering `params` are validated and parsed correctly,
+// executing within `ctx` context. It returns a page of TokenPool,
+// each describing the pool from the token's side, or an error.
+func (s *tokenService) GetPoolsByTokenService(ctx context.Context, token string, params PoolsParams) (*paging.Page[TokenPool], error) {
 	if !validator.IsValidToken(token) {
 		return nil, errors.New("invalid token")
 	}
+	token = strings.ToLower(token)
 
-	if !validator.IsValidRange(fromStr, toStr) {
-		return nil, errors.New("invalid range")
+	limitNum, err := strconv.Atoi(params.Limit)
+	if err != nil {
+		limitNum = 5
 	}
 
-	from, _ := strconv.ParseInt(fromStr, 10, 64)
-	to, _ := strconv.ParseInt(toStr, 10, 64)
+	if !validator.IsValidLimit(limitNum) {
+		limitNum = 5
+	}
+
+	cursor, err := paging.DecodeCursor(params.Cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	filter := PoolsFilter{
+		Limit:          limitNum,
+		Cursor:         cursor,
+		OrderBy:        "totalValueLockedUSD",
+		OrderDirection: "desc",
+	}
+
+	if params.OrderBy != "" {
+		orderBy, ok := poolsOrderBy[params.OrderBy]
+		if !ok {
+			return nil, errors.New("invalid orderBy")
+		}
+		filter.OrderBy = orderBy
+	}
+
+	if params.OrderDirection != "" {
+		if params.OrderDirection != "asc" && params.OrderDirection != "desc" {
+			return nil, errors.New("invalid orderDirection")
+		}
+		filter.OrderDirection = params.OrderDirection
+	}
+
+	if params.FeeTier != "" {
+		if !validator.IsValidFeeTier(params.FeeTier) {
+			return nil, errors.New("invalid feeTier")
+		}
+		filter.FeeTier = params.FeeTier
+	}
+
+	if params.MinTvlUSD != "" {
+		if !validator.IsValidAmount(params.MinTvlUSD) {
+			return nil, errors.New("invalid minTvlUSD")
+		}
+		filter.MinTvlUSD = params.MinTvlUSD
+	}
+
+	if params.Counterparty != "" {
+		if !validator.IsValidToken(params.Counterparty) {
+			return nil, errors.New("invalid counterparty")
+		}
+		filter.Counterparty = strings.ToLower(params.Counterparty)
+	}
+
+	pools, next, err := s.tokenRepo.GetPoolsByToken(ctx, token, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenPools := make([]TokenPool, 0, len(pools))
+	for _, p := range pools {
+		counterparty := p.Token1
+		if p.Token1.ID == token {
+			counterparty = p.Token0
+		}
+
+		tokenPools = append(tokenPools, TokenPool{
+			ID:                  p.ID,
+			FeeTier:             p.FeeTier,
+			Counterparty:        counterparty,
+			TotalValueLockedUSD: p.TotalValueLockedUSD,
+			VolumeUSD:           p.VolumeUSD,
+			TxCount:             p.TxCount,
+		})
+	}
+
+	return paging.NewPage(tokenPools, next), nil
+}
+
+// GetVolumeService retrieves the total token volume within the specified range,
+// summing up the volume time series of the given interval, see GetVolumeSeriesService.
+// When the interval is empty, ranges up to a week are summed from hourly buckets, longer ones from daily buckets.
+// With `exact` set to "true", the volume matches the range to the second instead, see exactVolume,
+// and the interval must be empty.
+// It returns a Volume struct or an error.
+func (s *tokenService) GetVolumeService(ctx context.Context, token string, params VolumeParams) (*Volume, error) {
+
+	exact := false
+	if params.Exact != "" {
+		var err error
+		exact, err = strconv.ParseBool(params.Exact)
+		if err != nil {
+			return nil, errors.New("invalid exact")
+		}
+	}
+
+	if exact {
+		if params.Interval != "" {
+			return nil, errors.New("interval cannot be combined with exact")
+		}
+		return s.exactVolume(ctx, token, params.From, params.To)
+	}
+
+	interval := params.Interval
+	if interval == "" {
+		interval = IntervalDay
+		if validator.IsValidRange(params.From, params.To) {
+			from, _ := strconv.ParseInt(params.From, 10, 64)
+			to, _ := strconv.ParseInt(params.To, 10, 64)
+			if to-from <= autoHourlyRange {
+				interval = IntervalHour
+			}
+		}
+	}
 
-	tokenVolumes, err := s.tokenRepo.GetVolume(ctx, token, from, to)
+	points, err := s.GetVolumeSeriesService(ctx, token, params.From, params.To, interval)
 	if err != nil {
-		return nil, errors.New("issue to get token volume")
+		return nil, err
 	}
 
-	if len(tokenVolumes) == 0 {
+	if len(points) == 0 {
 		return nil, errors.New("no token volume")
 	}
 
 	var volumes []string
-	for _, v := range tokenVolumes {
-		volumes = append(volumes, v.VolumeUSD)
+	for _, p := range points {
+		volumes = append(volumes, p.VolumeUSD)
 	}
 
 	sumStr, err := calc.SumNumbers(volumes)
@@ -87,9 +262,290 @@ func (s *tokenService) GetVolumeService(ctx context.Context, token string, fromS
 		return nil, errors.New("issue to get sum of volume")
 	}
 
+	volume := &Volume{
+		Volume:   sumStr,
+		Interval: interval,
+	}
+
+	return volume, nil
+}
+
+// exactVolume retrieves the token volume of exactly the range between `from` and `to`.
+// The days that lie entirely within the range are taken from the daily buckets,
+// while the partial days at its edges are summed up from the individual swaps.
+// The volumes are summed exactly, and the Volume has no interval, as it is not bound to the buckets.
+// It returns a Volume struct, with a volume of zero if nothing was swapped in the range, or an error.
+func (s *tokenService) exactVolume(ctx context.Context, token string, fromStr string, toStr string) (*Volume, error) {
+
+	if !validator.IsValidToken(token) {
+		return nil, errors.New("invalid token")
+	}
+	token = strings.ToLower(token)
+
+	if !validator.IsValidRange(fromStr, toStr) {
+		return nil, errors.New("invalid range")
+	}
+
+	from, _ := strconv.ParseInt(fromStr, 10, 64)
+	to, _ := strconv.ParseInt(toStr, 10, 64)
+
+	// Full days are [firstDay, lastDay), the day starting at lastDay contains `to`
+	firstDay := (from + daySeconds - 1) / daySeconds * daySeconds
+	lastDay := to / daySeconds * daySeconds
+
+	var swapRanges [][2]int64
+	var volumes []string
+
+	if firstDay >= lastDay {
+		swapRanges = append(swapRanges, [2]int64{from, to})
+	} else {
+		if from < firstDay {
+			swapRanges = append(swapRanges, [2]int64{from, firstDay - 1})
+		}
+		swapRanges = append(swapRanges, [2]int64{lastDay, to})
+
+		dayData, err := s.tokenRepo.GetTokenDayData(ctx, token, firstDay, lastDay-daySeconds)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, err
+			}
+			return nil, errors.New("issue to get token volume")
+		}
+		for _, d := range dayData {
+			volumes = append(volumes, d.VolumeUSD)
+		}
+	}
+
+	for _, r := range swapRanges {
+		swaps, err := s.tokenRepo.GetSwapsByToken(ctx, token, r[0], r[1])
+		if err != nil {
+			if errors.Is(err, paging.ErrTooManyItems) {
+				return nil, errors.New("too many swaps in range")
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, err
+			}
+			return nil, errors.New("issue to get token swaps")
+		}
+		for _, sw := range swaps {
+			volumes = append(volumes, sw.AmountUSD)
+		}
+	}
+
+	// The sum is exact, and a range without swaps has a volume of zero
+	sumStr, err := calc.SumDecimals(volumes)
+	if err != nil {
+		logger.Error("exactVolume error", "error", err)
+		return nil, errors.New("issue to get sum of volume")
+	}
+
 	volume := &Volume{
 		Volume: sumStr,
+		Exact:  true,
 	}
 
 	return volume, nil
 }
+
+// GetVolumeSeriesService retrieves the token volume time series within the specified range,
+// one point per hour or day (depending on `interval`) starting between `from` and `to`,
+// ensuring token, fromStr, toStr and interval inputs are validated and parsed correctly,
+// executing within `ctx` context.
+// It returns a slice of VolumePoint ordered by date or an error.
+func (s *tokenService) GetVolumeSeriesService(ctx context.Context, token string, fromStr string, toStr string, interval string) ([]VolumePoint, error) {
+
+	if !validator.IsValidToken(token) {
+		return nil, errors.New("invalid token")
+	}
+
+	if !validator.IsValidRange(fromStr, toStr) {
+		return nil, errors.New("invalid range")
+	}
+
+	from, _ := strconv.ParseInt(fromStr, 10, 64)
+	to, _ := strconv.ParseInt(toStr, 10, 64)
+
+	switch interval {
+	case IntervalDay:
+		dayData, err := s.tokenRepo.GetTokenDayData(ctx, strings.ToLower(token), from, to)
+		if err != nil {
+			if errors.Is(err, paging.ErrTooManyItems) {
+				return nil, errors.New("range too long for day interval")
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, err
+			}
+			return nil, errors.New("issue to get token volume")
+		}
+
+		points := make([]VolumePoint, 0, len(dayData))
+		for _, d := range dayData {
+			points = append(points, VolumePoint{
+				Date:                d.Date,
+				VolumeUSD:           d.VolumeUSD,
+				Volume:              d.Volume,
+				FeesUSD:             d.FeesUSD,
+				TotalValueLockedUSD: d.TotalValueLockedUSD,
+				PriceUSD:            d.PriceUSD,
+				Open:                d.Open,
+				High:                d.High,
+				Low:                 d.Low,
+				Close:               d.Close,
+			})
+		}
+
+		return points, nil
+	case IntervalHour:
+		hourData, err := s.tokenRepo.GetTokenHourData(ctx, strings.ToLower(token), from, to)
+		if err != nil {
+			if errors.Is(err, paging.ErrTooManyItems) {
+				return nil, errors.New("range too long for hour interval")
+			}
+			if errors.Is(err, context.DeadlineExceeded) {
+				return nil, err
+			}
+			return nil, errors.New("issue to get token volume")
+		}
+
+		points := make([]VolumePoint, 0, len(hourData))
+		for _, d := range hourData {
+			points = append(points, VolumePoint{
+				Date:                d.PeriodStartUnix,
+				VolumeUSD:           d.VolumeUSD,
+				Volume:              d.Volume,
+				FeesUSD:             d.FeesUSD,
+				TotalValueLockedUSD: d.TotalValueLockedUSD,
+				PriceUSD:            d.PriceUSD,
+				Open:                d.Open,
+				High:                d.High,
+				Low:                 d.Low,
+				Close:               d.Close,
+			})
+		}
+
+		return points, nil
+	}
+
+	return nil, errors.New("invalid interval")
+}
+
+// GetCandlesService retrieves the candles of the token price in USD of the given `interval`
+// ("5m", "15m", "1h", "4h" or "1d") starting between `from` and `to`,
+// ensuring token, fromStr, toStr and interval inputs are validated and parsed correctly,
+// executing within `ctx` context.
+// - Daily candles come from the daily time series of the token, and hourly and 4-hour ones from the hourly one,
+// - Shorter candles are built out of the swaps of the token, each pricing it by the USD value of the swap
+// over the amount of the token swapped; swaps of pools without a USD price are left out.
+// It returns a slice of candle.Candle ordered by date or an error.
+func (s *tokenService) GetCandlesService(ctx context.Context, token string, fromStr string, toStr string, interval string) ([]candle.Candle, error) {
+
+	if !validator.IsValidToken(token) {
+		return nil, errors.New("invalid token")
+	}
+
+	if !validator.IsValidRange(fromStr, toStr) {
+		return nil, errors.New("invalid range")
+	}
+
+	seconds, ok := candle.ParseInterval(interval)
+	if !ok {
+		return nil, errors.New("invalid interval")
+	}
+
+	from, _ := strconv.ParseInt(fromStr, 10, 64)
+	to, _ := strconv.ParseInt(toStr, 10, 64)
+	start, end := candle.Span(from, to, seconds)
+	if start > end {
+		return []candle.Candle{}, nil
+	}
+
+	token = strings.ToLower(token)
+
+	var candles []candle.Candle
+
+	switch {
+	case seconds%candle.Day == 0:
+		dayData, err := s.tokenRepo.GetTokenDayData(ctx, token, start, end)
+		if err != nil {
+			return nil, paging.RangeError(err, interval, "issue to get token candles")
+		}
+
+		for _, d := range dayData {
+			candles = append(candles, candle.Candle{Date: d.Date, Open: d.Open, High: d.High, Low: d.Low, Close: d.Close, VolumeUSD: d.VolumeUSD})
+		}
+	case seconds%candle.Hour == 0:
+		hourData, err := s.tokenRepo.GetTokenHourData(ctx, token, start, end)
+		if err != nil {
+			return nil, paging.RangeError(err, interval, "issue to get token candles")
+		}
+
+		for _, d := range hourData {
+			candles = append(candles, candle.Candle{Date: d.PeriodStartUnix, Open: d.Open, High: d.High, Low: d.Low, Close: d.Close, VolumeUSD: d.VolumeUSD})
+		}
+	default:
+		swaps, err := s.tokenRepo.GetSwapsByToken(ctx, token, start, end)
+		if err != nil {
+			return nil, paging.RangeError(err, interval, "issue to get token candles")
+		}
+
+		ticks, err := swapTicks(token, swaps)
+		if err != nil {
+			logger.Error("swapTicks error", "error", err)
+			return nil, errors.New("issue to get token candles")
+		}
+
+		candles, err = candle.FromTicks(ticks, seconds)
+		if err != nil {
+			return nil, errors.New("issue to get token candles")
+		}
+
+		return candles, nil
+	}
+
+	candles, err := candle.Merge(candles, seconds)
+	if err != nil {
+		return nil, errors.New("issue to get token candles")
+	}
+
+	return candles, nil
+}
+
+// swapTicks prices the `token` by each of the `swaps`, in the order they were executed,
+// dividing the USD value of the swap by the amount of the token swapped.
+// Swaps without a USD value, made in pools the subgraph cannot price, are skipped.
+func swapTicks(token string, swaps []Swap) ([]candle.Tick, error) {
+	swaps = subgraph.InExecutionOrder(swaps, func(s Swap) (string, string) {
+		return s.Timestamp, s.LogIndex
+	})
+
+	ticks := make([]candle.Tick, 0, len(swaps))
+	for _, sw := range swaps {
+		amount := sw.Amount1
+		if sw.Token0.ID == token {
+			amount = sw.Amount0
+		}
+		amount = strings.TrimPrefix(amount, "-")
+
+		priced, err := calc.CompareNumbers(sw.AmountUSD, "0")
+		if err != nil {
+			return nil, err
+		}
+		swapped, err := calc.CompareNumbers(amount, "0")
+		if err != nil {
+			return nil, err
+		}
+		if priced <= 0 || swapped <= 0 {
+			continue
+		}
+
+		price, err := calc.DivideNumbers(sw.AmountUSD, amount)
+		if err != nil {
+			return nil, err
+		}
+
+		ts, _ := strconv.ParseInt(sw.Timestamp, 10, 64)
+		ticks = append(ticks, candle.Tick{Time: ts, Price: price, VolumeUSD: sw.AmountUSD})
+	}
+
+	return ticks, nil
+}
@@ -3,6 +3,8 @@ package token
 import (
 	"context"
 	"errors"
+	"eth-graph-api/pkg/candle"
+	"eth-graph-api/pkg/paging"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
@@ -12,63 +14,215 @@ type MockRepository struct {
 	mock.Mock
 }
 
-func (m *MockRepository) GetPoolsByToken(ctx context.Context, token string, first int) ([]Pool, error) {
-	args := m.Called(ctx, token, first)
-	return args.Get(0).([]Pool), args.Error(1)
+func (m *MockRepository) GetToken(ctx context.Context, token string) (*TokenData, error) {
+	args := m.Called(ctx, token)
+	return args.Get(0).(*TokenData), args.Error(1)
 }
 
-func (m *MockRepository) GetVolume(ctx context.Context, token string, from int64, to int64) ([]TokenDayData, error) {
+func (m *MockRepository) GetBundle(ctx context.Context) (*Bundle, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*Bundle), args.Error(1)
+}
+
+func (m *MockRepository) GetTokenHourData(ctx context.Context, token string, from int64, to int64) ([]TokenHourData, error) {
+	args := m.Called(ctx, token, from, to)
+	return args.Get(0).([]TokenHourData), args.Error(1)
+}
+
+func (m *MockRepository) GetSwapsByToken(ctx context.Context, token string, from int64, to int64) ([]Swap, error) {
+	args := m.Called(ctx, token, from, to)
+	return args.Get(0).([]Swap), args.Error(1)
+}
+
+func (m *MockRepository) GetPoolsByToken(ctx context.Context, token string, filter PoolsFilter) ([]Pool, string, error) {
+	args := m.Called(ctx, token, filter)
+	return args.Get(0).([]Pool), args.String(1), args.Error(2)
+}
+
+func (m *MockRepository) GetTokenDayData(ctx context.Context, token string, from int64, to int64) ([]TokenDayData, error) {
 	args := m.Called(ctx, token, from, to)
 	return args.Get(0).([]TokenDayData), args.Error(1)
 }
 
+func TestGetTokenService(t *testing.T) {
+	ctx := context.Background()
+	weth := "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+
+	tests := []struct {
+		name           string
+		token          string
+		mockRepoFn     func(m *MockRepository)
+		expectedOutput *TokenDetails
+		expectedErr    error
+	}{
+		{
+			name:  "valid token",
+			token: "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetToken", mock.Anything, weth).
+					Return(&TokenData{ID: weth, Symbol: "WETH", Name: "Wrapped Ether", Decimals: "18", DerivedETH: "1", PoolCount: "9000"}, nil).Once()
+				m.On("GetBundle", mock.Anything).Return(&Bundle{EthPriceUSD: "1600.25"}, nil).Once()
+			},
+			expectedOutput: &TokenDetails{ID: weth, Symbol: "WETH", Name: "Wrapped Ether", Decimals: "18", DerivedETH: "1", PriceUSD: "1600.2500000000", PoolCount: "9000"},
+		},
+		{
+			name:        "invalid token",
+			token:       "invalid",
+			mockRepoFn:  func(m *MockRepository) {},
+			expectedErr: errors.New("invalid token"),
+		},
+		{
+			name:  "unknown token",
+			token: "0x0000000000000000000000000000000000000000",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetToken", mock.Anything, mock.Anything).Return((*TokenData)(nil), nil).Once()
+			},
+			expectedErr: ErrTokenNotFound,
+		},
+		{
+			name:  "repository error",
+			token: weth,
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetToken", mock.Anything, mock.Anything).Return((*TokenData)(nil), errors.New("mock error")).Once()
+			},
+			expectedErr: errors.New("issue to get token"),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			tc.mockRepoFn(mockRepo)
+
+			svc := NewTokenService(mockRepo)
+			details, err := svc.GetTokenService(ctx, tc.token)
+
+			if tc.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedOutput, details)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestGetPoolsByTokenService(t *testing.T) {
 	mockRepo := new(MockRepository)
 	service := NewTokenService(mockRepo)
 	ctx := context.TODO()
 
+	weth := Token{ID: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", Symbol: "WETH", Decimals: "18"}
+	usdc := Token{ID: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Symbol: "USDC", Decimals: "6"}
+	dai := Token{ID: "0x6b175474e89094c44da98b954eedeac495271d0f", Symbol: "DAI", Decimals: "18"}
+
+	defaultFilter := PoolsFilter{Limit: 5, OrderBy: "totalValueLockedUSD", OrderDirection: "desc"}
+
 	cases := []struct {
 		name          string
 		token         string
-		firstStr      string
+		params        PoolsParams
 		setupMocks    func()
-		expectedPools []Pool
+		expectedPools *paging.Page[TokenPool]
 		expectedErr   error
 	}{
 		{
-			name:     "get pools by token with valid input",
-			token:    "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
-			firstStr: "5",
+			name:   "get pools by token with valid input",
+			token:  "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			params: PoolsParams{Limit: "5"},
 			setupMocks: func() {
-				mockRepo.On("GetPoolsByToken", ctx, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", 5).Return([]Pool{{ID: "0x0000d36ab86d213c14d93cd5ae78615a20596505"}, {ID: "0x0001fcbba8eb491c3ccfeddc5a5caba1a98c4c28"}}, nil).Once()
+				mockRepo.On("GetPoolsByToken", ctx, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", defaultFilter).Return([]Pool{
+					{ID: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", FeeTier: "500", Token0: usdc, Token1: weth, TotalValueLockedUSD: "100"},
+					{ID: "0xc2e9f25be6257c210d7adf0d4cd6e3e881ba25f8", FeeTier: "3000", Token0: weth, Token1: dai, TotalValueLockedUSD: "50"},
+				}, "50,0xc2e9f25be6257c210d7adf0d4cd6e3e881ba25f8", nil).Once()
 			},
-			expectedPools: []Pool{{ID: "0x0000d36ab86d213c14d93cd5ae78615a20596505"}, {ID: "0x0001fcbba8eb491c3ccfeddc5a5caba1a98c4c28"}},
-			expectedErr:   nil,
+			expectedPools: &paging.Page[TokenPool]{
+				Data: []TokenPool{
+					{ID: "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640", FeeTier: "500", Counterparty: usdc, TotalValueLockedUSD: "100"},
+					{ID: "0xc2e9f25be6257c210d7adf0d4cd6e3e881ba25f8", FeeTier: "3000", Counterparty: dai, TotalValueLockedUSD: "50"},
+				},
+				NextCursor: paging.EncodeCursor("50,0xc2e9f25be6257c210d7adf0d4cd6e3e881ba25f8"),
+				HasMore:    true,
+			},
+			expectedErr: nil,
 		},
 		{
 			name:          "get pools by token with invalid token",
 			token:         "invalidToken",
-			firstStr:      "5",
+			params:        PoolsParams{Limit: "5"},
 			setupMocks:    func() {},
 			expectedPools: nil,
 			expectedErr:   errors.New("invalid token"),
 		},
 		{
-			name:     "get pools by token with non-numeric first",
-			token:    "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
-			firstStr: "nonNumeric",
+			name:   "get pools by token with non-numeric first",
+			token:  "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+			params: PoolsParams{Limit: "nonNumeric"},
+			setupMocks: func() {
+				mockRepo.On("GetPoolsByToken", ctx, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", defaultFilter).Return([]Pool{}, "", nil).Once()
+			},
+			expectedPools: &paging.Page[TokenPool]{Data: []TokenPool{}},
+			expectedErr:   nil,
+		},
+		{
+			name:  "get pools by token with ordering and filters",
+			token: "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			params: PoolsParams{
+				Limit:          "10",
+				Cursor:         paging.EncodeCursor("100,0xabc"),
+				OrderBy:        "volumeUSD",
+				OrderDirection: "asc",
+				FeeTier:        "500",
+				MinTvlUSD:      "1000.5",
+				Counterparty:   "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48",
+			},
 			setupMocks: func() {
-				mockRepo.On("GetPoolsByToken", ctx, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", 5).Return([]Pool{{ID: "0x0000d36ab86d213c14d93cd5ae78615a20596505"}, {ID: "0x0001fcbba8eb491c3ccfeddc5a5caba1a98c4c28"}}, nil).Once()
+				mockRepo.On("GetPoolsByToken", ctx, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", PoolsFilter{
+					Limit:          10,
+					Cursor:         "100,0xabc",
+					OrderBy:        "volumeUSD",
+					OrderDirection: "asc",
+					FeeTier:        "500",
+					MinTvlUSD:      "1000.5",
+					Counterparty:   "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+				}).Return([]Pool{}, "", nil).Once()
 			},
-			expectedPools: []Pool{{ID: "0x0000d36ab86d213c14d93cd5ae78615a20596505"}, {ID: "0x0001fcbba8eb491c3ccfeddc5a5caba1a98c4c28"}},
+			expectedPools: &paging.Page[TokenPool]{Data: []TokenPool{}},
 			expectedErr:   nil,
 		},
+		{
+			name:          "get pools by token with invalid orderBy",
+			token:         "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			params:        PoolsParams{Limit: "5", OrderBy: "name"},
+			setupMocks:    func() {},
+			expectedPools: nil,
+			expectedErr:   errors.New("invalid orderBy"),
+		},
+		{
+			name:          "get pools by token with invalid cursor",
+			token:         "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			params:        PoolsParams{Limit: "5", Cursor: "100,0xabc"},
+			setupMocks:    func() {},
+			expectedPools: nil,
+			expectedErr:   errors.New("invalid cursor"),
+		},
+		{
+			name:          "get pools by token with invalid feeTier",
+			token:         "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			params:        PoolsParams{Limit: "5", FeeTier: "42"},
+			setupMocks:    func() {},
+			expectedPools: nil,
+			expectedErr:   errors.New("invalid feeTier"),
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			tc.setupMocks()
-			returnedPools, err := service.GetPoolsByTokenService(ctx, tc.token, tc.firstStr)
+			returnedPools, err := service.GetPoolsByTokenService(ctx, tc.token, tc.params)
 			if tc.expectedErr != nil {
 				assert.Error(t, err)
 				assert.Equal(t, tc.expectedErr.Error(), err.Error())
@@ -89,22 +243,123 @@ func TestGetVolumeService(t *testing.T) {
 		token       string
 		fromStr     string
 		toStr       string
+		interval    string
+		exact       string
 		mockRepoFn  func(m *MockRepository)
 		expectErr   bool
 		expectValue string
 	}{
 		{
-			name:    "valid case",
+			name:     "valid case",
+			token:    "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			fromStr:  "1633036800",
+			toStr:    "1633123200",
+			interval: "day",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetTokenDayData", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", int64(1633036800), int64(1633123200)).
+					Return([]TokenDayData{{VolumeUSD: "300.0000000000"}}, nil).Once()
+			},
+			expectErr:   false,
+			expectValue: "300.0000000000",
+		},
+		{
+			name:    "short range defaults to hourly buckets",
 			token:   "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
 			fromStr: "1633036800",
-			toStr:   "1633123200",
+			toStr:   "1633047600",
 			mockRepoFn: func(m *MockRepository) {
-				m.On("GetVolume", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", int64(1633036800), int64(1633123200)).
-					Return([]TokenDayData{{VolumeUSD: "300.0000000000"}}, nil).Once()
+				m.On("GetTokenHourData", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", int64(1633036800), int64(1633047600)).
+					Return([]TokenHourData{{VolumeUSD: "100"}, {VolumeUSD: "150.5"}, {VolumeUSD: "49.5"}}, nil).Once()
 			},
 			expectErr:   false,
 			expectValue: "300.0000000000",
 		},
+		{
+			name:    "exact volume within a single day",
+			token:   "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			fromStr: "1633046400",
+			toStr:   "1633050000",
+			exact:   "true",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetSwapsByToken", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", int64(1633046400), int64(1633050000)).
+					Return([]Swap{{AmountUSD: "120.5"}, {AmountUSD: "79.5"}}, nil).Once()
+			},
+			expectErr:   false,
+			expectValue: "200.0000000000",
+		},
+		{
+			// 2021-10-01 10:00 to 2021-10-04 06:00: full days 10-02 and 10-03, partial days at both edges
+			name:    "exact volume across days",
+			token:   "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			fromStr: "1633082400",
+			toStr:   "1633327200",
+			exact:   "true",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetTokenDayData", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", int64(1633132800), int64(1633219200)).
+					Return([]TokenDayData{{Date: 1633132800, VolumeUSD: "1000"}, {Date: 1633219200, VolumeUSD: "2000"}}, nil).Once()
+				m.On("GetSwapsByToken", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", int64(1633082400), int64(1633132799)).
+					Return([]Swap{{AmountUSD: "10"}}, nil).Once()
+				m.On("GetSwapsByToken", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", int64(1633305600), int64(1633327200)).
+					Return([]Swap{{AmountUSD: "5"}, {AmountUSD: "0.25"}}, nil).Once()
+			},
+			expectErr:   false,
+			expectValue: "3015.2500000000",
+		},
+		{
+			name:    "exact volume without swaps",
+			token:   "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			fromStr: "1633046400",
+			toStr:   "1633050000",
+			exact:   "true",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetSwapsByToken", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", int64(1633046400), int64(1633050000)).
+					Return([]Swap{}, nil).Once()
+			},
+			expectErr:   false,
+			expectValue: "0.0000000000",
+		},
+		{
+			name:    "exact volume summed exactly",
+			token:   "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			fromStr: "1633046400",
+			toStr:   "1633050000",
+			exact:   "true",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetSwapsByToken", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", int64(1633046400), int64(1633050000)).
+					Return([]Swap{{AmountUSD: "123456789.123456789123456789"}, {AmountUSD: "987654321.987654321987654321"}}, nil).Once()
+			},
+			expectErr:   false,
+			expectValue: "1111111111.11111111111111111",
+		},
+		{
+			name:       "exact volume with an interval",
+			token:      "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			fromStr:    "1633046400",
+			toStr:      "1633050000",
+			interval:   "hour",
+			exact:      "true",
+			mockRepoFn: func(m *MockRepository) {},
+			expectErr:  true,
+		},
+		{
+			name:       "invalid exact",
+			token:      "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			fromStr:    "1633036800",
+			toStr:      "1633123200",
+			exact:      "maybe",
+			mockRepoFn: func(m *MockRepository) {},
+			expectErr:  true,
+		},
+		{
+			name:     "invalid interval",
+			token:    "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			fromStr:  "1633036800",
+			toStr:    "1633123200",
+			interval: "week",
+			mockRepoFn: func(m *MockRepository) {
+			},
+			expectErr: true,
+		},
 		{
 			name:    "invalid token",
 			token:   "",
@@ -115,12 +370,13 @@ func TestGetVolumeService(t *testing.T) {
 			expectErr: true,
 		},
 		{
-			name:    "repository error",
-			token:   "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
-			fromStr: "1633036800",
-			toStr:   "1633123200",
+			name:     "repository error",
+			token:    "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			fromStr:  "1633036800",
+			toStr:    "1633123200",
+			interval: "day",
 			mockRepoFn: func(m *MockRepository) {
-				m.On("GetVolume", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", int64(1633036800), int64(1633123200)).
+				m.On("GetTokenDayData", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", int64(1633036800), int64(1633123200)).
 					Return([]TokenDayData{}, errors.New("mock error")).Once()
 			},
 			expectErr: true,
@@ -136,7 +392,7 @@ func TestGetVolumeService(t *testing.T) {
 
 			svc := &tokenService{tokenRepo: mockRepo}
 
-			volume, err := svc.GetVolumeService(ctx, tc.token, tc.fromStr, tc.toStr)
+			volume, err := svc.GetVolumeService(ctx, tc.token, VolumeParams{From: tc.fromStr, To: tc.toStr, Interval: tc.interval, Exact: tc.exact})
 
 			if tc.expectErr {
 				assert.Error(t, err)
@@ -149,3 +405,222 @@ func TestGetVolumeService(t *testing.T) {
 		})
 	}
 }
+
+func TestGetVolumeSeriesService(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name        string
+		token       string
+		fromStr     string
+		toStr       string
+		interval    string
+		mockRepoFn  func(m *MockRepository)
+		expectErr   bool
+		expectValue []VolumePoint
+	}{
+		{
+			name:     "daily buckets",
+			token:    "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+			fromStr:  "1633036800",
+			toStr:    "1633209600",
+			interval: "day",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetTokenDayData", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", int64(1633036800), int64(1633209600)).
+					Return([]TokenDayData{
+						{Date: 1633046400, VolumeUSD: "100", Volume: "0.1", FeesUSD: "1", Open: "1000", High: "1100", Low: "900", Close: "1050"},
+						{Date: 1633132800, VolumeUSD: "200", Volume: "0.2", FeesUSD: "2", Open: "1050", High: "1200", Low: "1000", Close: "1150"},
+					}, nil).Once()
+			},
+			expectErr: false,
+			expectValue: []VolumePoint{
+				{Date: 1633046400, VolumeUSD: "100", Volume: "0.1", FeesUSD: "1", Open: "1000", High: "1100", Low: "900", Close: "1050"},
+				{Date: 1633132800, VolumeUSD: "200", Volume: "0.2", FeesUSD: "2", Open: "1050", High: "1200", Low: "1000", Close: "1150"},
+			},
+		},
+		{
+			name:     "hourly buckets",
+			token:    "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			fromStr:  "1633036800",
+			toStr:    "1633040400",
+			interval: "hour",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetTokenHourData", mock.Anything, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", int64(1633036800), int64(1633040400)).
+					Return([]TokenHourData{
+						{PeriodStartUnix: 1633036800, VolumeUSD: "10", Open: "1000", Close: "1010"},
+						{PeriodStartUnix: 1633040400, VolumeUSD: "20", Open: "1010", Close: "1020"},
+					}, nil).Once()
+			},
+			expectErr: false,
+			expectValue: []VolumePoint{
+				{Date: 1633036800, VolumeUSD: "10", Open: "1000", Close: "1010"},
+				{Date: 1633040400, VolumeUSD: "20", Open: "1010", Close: "1020"},
+			},
+		},
+		{
+			name:     "hourly range too long",
+			token:    "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			fromStr:  "1600000000",
+			toStr:    "1633040400",
+			interval: "hour",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetTokenHourData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return([]TokenHourData(nil), paging.ErrTooManyItems).Once()
+			},
+			expectErr: true,
+		},
+		{
+			name:     "no data",
+			token:    "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			fromStr:  "1633036800",
+			toStr:    "1633123200",
+			interval: "day",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetTokenDayData", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+					Return([]TokenDayData{}, nil).Once()
+			},
+			expectErr:   false,
+			expectValue: []VolumePoint{},
+		},
+		{
+			name:       "invalid range",
+			token:      "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+			fromStr:    "1633123200",
+			toStr:      "1633036800",
+			interval:   "day",
+			mockRepoFn: func(m *MockRepository) {},
+			expectErr:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			tc.mockRepoFn(mockRepo)
+
+			svc := NewTokenService(mockRepo)
+
+			points, err := svc.GetVolumeSeriesService(ctx, tc.token, tc.fromStr, tc.toStr, tc.interval)
+
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectValue, points)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetVolumeSeriesServiceTimeout(t *testing.T) {
+	token := "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+
+	mockRepo := new(MockRepository)
+	mockRepo.On("GetTokenDayData", mock.Anything, token, int64(1633036800), int64(1633123200)).
+		Return([]TokenDayData(nil), context.DeadlineExceeded).Once()
+	mockRepo.On("GetTokenHourData", mock.Anything, token, int64(1633036800), int64(1633123200)).
+		Return([]TokenHourData(nil), context.DeadlineExceeded).Once()
+
+	svc := NewTokenService(mockRepo)
+
+	for _, interval := range []string{IntervalDay, IntervalHour} {
+		_, err := svc.GetVolumeSeriesService(context.Background(), token, "1633036800", "1633123200", interval)
+		assert.ErrorIs(t, err, context.DeadlineExceeded, interval)
+	}
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetCandlesService(t *testing.T) {
+	weth := "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+
+	tests := []struct {
+		name           string
+		fromStr        string
+		toStr          string
+		interval       string
+		mockRepoFn     func(m *MockRepository)
+		expectedOutput []candle.Candle
+		expectedErr    error
+	}{
+		{
+			name:     "hourly candles from the hourly series",
+			fromStr:  "1697029200",
+			toStr:    "1697032800",
+			interval: "1h",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetTokenHourData", mock.Anything, weth, int64(1697029200), int64(1697036399)).
+					Return([]TokenHourData{
+						{PeriodStartUnix: 1697029200, Open: "1570", High: "1576", Low: "1569", Close: "1575", VolumeUSD: "100"},
+						{PeriodStartUnix: 1697032800, Open: "1575", High: "1578", Low: "1572", Close: "1573", VolumeUSD: "50"},
+					}, nil).Once()
+			},
+			expectedOutput: []candle.Candle{
+				{Date: 1697029200, Open: "1570", High: "1576", Low: "1569", Close: "1575", VolumeUSD: "100"},
+				{Date: 1697032800, Open: "1575", High: "1578", Low: "1572", Close: "1573", VolumeUSD: "50"},
+			},
+		},
+		{
+			name:     "5 minute candles from the swaps",
+			fromStr:  "1697029200",
+			toStr:    "1697029500",
+			interval: "5m",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetSwapsByToken", mock.Anything, weth, int64(1697029200), int64(1697029799)).
+					Return([]Swap{
+						// WETH as token0 of a WETH/USDT pool
+						{ID: "0xb#1", Timestamp: "1697029211", LogIndex: "40", Token0: Token{ID: weth}, Amount0: "2", Amount1: "-3150", AmountUSD: "3150"},
+						// WETH as token1 of a USDC/WETH pool
+						{ID: "0xa#2", Timestamp: "1697029211", LogIndex: "7", Token0: Token{ID: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"}, Amount0: "1575.5", Amount1: "-1", AmountUSD: "1575.5"},
+						// A pool the subgraph cannot price
+						{ID: "0xc#1", Timestamp: "1697029300", LogIndex: "3", Token0: Token{ID: weth}, Amount0: "1", Amount1: "-1000", AmountUSD: "0"},
+						{ID: "0xd#9", Timestamp: "1697029620", LogIndex: "1", Token0: Token{ID: weth}, Amount0: "-0.5", Amount1: "790", AmountUSD: "790"},
+					}, nil).Once()
+			},
+			expectedOutput: []candle.Candle{
+				{Date: 1697029200, Open: "1575.500000000000000000", High: "1575.500000000000000000", Low: "1575.000000000000000000", Close: "1575.000000000000000000", VolumeUSD: "4725.5000000000"},
+				{Date: 1697029500, Open: "1580.000000000000000000", High: "1580.000000000000000000", Low: "1580.000000000000000000", Close: "1580.000000000000000000", VolumeUSD: "790"},
+			},
+		},
+		{
+			name:     "too many swaps",
+			fromStr:  "1690000000",
+			toStr:    "1697029500",
+			interval: "15m",
+			mockRepoFn: func(m *MockRepository) {
+				m.On("GetSwapsByToken", mock.Anything, weth, mock.Anything, mock.Anything).Return([]Swap(nil), paging.ErrTooManyItems).Once()
+			},
+			expectedErr: errors.New("range too long for 15m interval"),
+		},
+		{
+			name:        "invalid interval",
+			fromStr:     "1697029200",
+			toStr:       "1697029500",
+			interval:    "2h",
+			mockRepoFn:  func(m *MockRepository) {},
+			expectedErr: errors.New("invalid interval"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := new(MockRepository)
+			test.mockRepoFn(mockRepo)
+
+			svc := NewTokenService(mockRepo)
+			output, err := svc.GetCandlesService(context.Background(), weth, test.fromStr, test.toStr, test.interval)
+
+			if test.expectedErr != nil {
+				assert.Error(t, err)
+				assert.Equal(t, test.expectedErr.Error(), err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedOutput, output)
+			}
+
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package blocktime
+
+import (
+	"context"
+	"errors"
+	"eth-graph-api/pkg/logger"
+	"eth-graph-api/pkg/subgraph"
+	"strconv"
+)
+
+// ErrNotFound is returned when a block or its time cannot be resolved.
+var ErrNotFound = errors.New("block not found")
+
+// GraphClient is an interface that declares a method for making GraphQL queries,
+// see the GraphClient of the repositories.
+type GraphClient interface {
+	Query(ctx context.Context, q interface{}, variables map[string]interface{}) error
+}
+
+// Block is a block number along with its UNIX timestamp.
+// Exact is false when the block was estimated from the swaps around it and only approximates the one asked for.
+type Block struct {
+	Number    int64 `json:"number"`
+	Timestamp int64 `json:"timestamp"`
+	Exact     bool  `json:"exact"`
+}
+
+// Resolver bridges UNIX timestamps and block numbers.
+// BlockAt resolves the latest block mined at or before the `timestamp`,
+// while TimeOf resolves the timestamp of the block `number`.
+type Resolver interface {
+	BlockAt(ctx context.Context, timestamp int64) (*Block, error)
+	TimeOf(ctx context.Context, number int64) (*Block, error)
+}
+
+// NewResolver constructs a Resolver backed by a blocks subgraph, which indexes every block with its timestamp.
+// If `blocksClient` is nil, i.e. no blocks subgraph is configured, the resolver falls back to
+// the timestamps of the swaps indexed by the Uniswap subgraph behind `graphClient`.
+func NewResolver(blocksClient GraphClient, graphClient GraphClient) Resolver {
+	if blocksClient != nil {
+		return &blocksResolver{blocksClient: blocksClient}
+	}
+
+	return &swapsResolver{graphClient: graphClient}
+}
+
+// blockData is the block entity as indexed by the blocks subgraph
+type blockData struct {
+	Number    string `graphql:"number"`
+	Timestamp string `graphql:"timestamp"`
+}
+
+// blocksResolver resolves blocks using a blocks subgraph
+type blocksResolver struct {
+	blocksClient GraphClient
+}
+
+// BlockAt performs a GraphQL query to retrieve the latest block with a timestamp not after `timestamp`.
+// It returns the Block, ErrNotFound, or an error if the query operation fails.
+func (r *blocksResolver) BlockAt(ctx context.Context, timestamp int64) (*Block, error) {
+	return r.first(ctx, subgraph.Block_filter{"timestamp_lte": strconv.FormatInt(timestamp, 10)})
+}
+
+// TimeOf performs a GraphQL query to retrieve the block `number` along with its timestamp.
+// It returns the Block, ErrNotFound, or an error if the query operation fails.
+func (r *blocksResolver) TimeOf(ctx context.Context, number int64) (*Block, error) {
+	return r.first(ctx, subgraph.Block_filter{"number": strconv.FormatInt(number, 10)})
+}
+
+// first retrieves the latest block matched by `where`.
+func (r *blocksResolver) first(ctx context.Context, where subgraph.Block_filter) (*Block, error) {
+
+	var query struct {
+		Blocks []blockData `graphql:"blocks(first: 1, orderBy: timestamp, orderDirection: desc, where: $where)"`
+	}
+
+	vars := map[string]interface{}{
+		"where": where,
+	}
+
+	err := r.blocksClient.Query(ctx, &query, vars)
+	if err != nil {
+		logger.Error("error querying blocks", "error", err)
+		return nil, err
+	}
+
+	if len(query.Blocks) == 0 {
+		return nil, ErrNotFound
+	}
+
+	number, err := strconv.ParseInt(query.Blocks[0].Number, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp, err := strconv.ParseInt(query.Blocks[0].Timestamp, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Block{Number: number, Timestamp: timestamp, Exact: true}, nil
+}
+
+// swapData is the part of the swap entity that tells when and in which block the swap was made
+type swapData struct {
+	Timestamp   string `graphql:"timestamp"`
+	Transaction struct {
+		BlockNumber string `graphql:"blockNumber"`
+	} `graphql:"transaction"`
+}
+
+// swapsResolver resolves blocks using the swaps indexed by the Uniswap subgraph.
+// Block timestamps strictly increase with block numbers, so the subgraph's sorted timestamp index
+// narrows the search down to the two swaps bracketing a timestamp or a block, each found with a single ordered query.
+// When blocks without swaps lie between them, the block is interpolated between the bracketing swaps
+// and reported as not exact.
+type swapsResolver struct {
+	graphClient GraphClient
+}
+
+// BlockAt resolves the block mined at or before `timestamp` from the last swap made at or before it
+// and the first swap made after it.
+// The block of the last swap is exact if it was made at `timestamp`, or if the first swap after it is in the very next block.
+// Otherwise, the block is interpolated between the two swaps, or is the block of the last swap if no swap was made after it.
+// It returns the Block, ErrNotFound, or an error if the query operation fails.
+func (r *swapsResolver) BlockAt(ctx context.Context, timestamp int64) (*Block, error) {
+	ts := strconv.FormatInt(timestamp, 10)
+
+	before, err := r.nearest(ctx, subgraph.Swap_filter{"timestamp_lte": ts}, subgraph.Desc)
+	if err != nil {
+		return nil, err
+	}
+
+	if before == nil {
+		return nil, ErrNotFound
+	}
+
+	if before.Timestamp == timestamp {
+		before.Exact = true
+		return before, nil
+	}
+
+	after, err := r.nearest(ctx, subgraph.Swap_filter{"timestamp_gt": ts}, subgraph.Asc)
+	if err != nil {
+		return nil, err
+	}
+
+	if after == nil {
+		return before, nil
+	}
+
+	if after.Number == before.Number+1 {
+		before.Exact = true
+		return before, nil
+	}
+
+	// the blocks before.Number to after.Number-1 were mined from before.Timestamp up to after.Timestamp,
+	// so rounding down keeps the block at or before `timestamp`
+	number := before.Number + (timestamp-before.Timestamp)*(after.Number-before.Number)/(after.Timestamp-before.Timestamp)
+
+	return interpolate(before, after, number), nil
+}
+
+// TimeOf resolves the time of the block `number` from the last swap made at or before the block.
+// The time is exact if the swap was made in the block, and is otherwise interpolated between that swap
+// and the first swap made after the block.
+// It returns the Block, ErrNotFound if the block is not bracketed by swaps, or an error if the query operation fails.
+func (r *swapsResolver) TimeOf(ctx context.Context, number int64) (*Block, error) {
+	n := strconv.FormatInt(number, 10)
+
+	before, err := r.nearest(ctx, subgraph.Swap_filter{"transaction_": map[string]interface{}{"blockNumber_lte": n}}, subgraph.Desc)
+	if err != nil {
+		return nil, err
+	}
+
+	if before == nil {
+		return nil, ErrNotFound
+	}
+
+	if before.Number == number {
+		before.Exact = true
+		return before, nil
+	}
+
+	after, err := r.nearest(ctx, subgraph.Swap_filter{"transaction_": map[string]interface{}{"blockNumber_gt": n}}, subgraph.Asc)
+	if err != nil {
+		return nil, err
+	}
+
+	if after == nil {
+		return nil, ErrNotFound
+	}
+
+	return interpolate(before, after, number), nil
+}
+
+// interpolate estimates the timestamp of the block `number` lying between the blocks `before` and `after`,
+// assuming the blocks in between were mined at a steady pace.
+func interpolate(before, after *Block, number int64) *Block {
+	timestamp := before.Timestamp + (number-before.Number)*(after.Timestamp-before.Timestamp)/(after.Number-before.Number)
+
+	return &Block{Number: number, Timestamp: timestamp}
+}
+
+// nearest retrieves the block of the first swap matched by `where` in the `direction` of timestamps,
+// or nil if there is no such swap.
+func (r *swapsResolver) nearest(ctx context.Context, where subgraph.Swap_filter, direction subgraph.OrderDirection) (*Block, error) {
+
+	var query struct {
+		Swaps []swapData `graphql:"swaps(first: 1, orderBy: timestamp, orderDirection: $orderDirection, where: $where)"`
+	}
+
+	vars := map[string]interface{}{
+		"orderDirection": direction,
+		"where":          where,
+	}
+
+	err := r.graphClient.Query(ctx, &query, vars)
+	if err != nil {
+		logger.Error("error querying swaps for block time", "error", err)
+		return nil, err
+	}
+
+	if len(query.Swaps) == 0 {
+		return nil, nil
+	}
+
+	number, err := strconv.ParseInt(query.Swaps[0].Transaction.BlockNumber, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp, err := strconv.ParseInt(query.Swaps[0].Timestamp, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Block{Number: number, Timestamp: timestamp}, nil
+}
@@ -0,0 +1,226 @@
+package blocktime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"eth-graph-api/pkg/subgraph"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+// fakeClient answers the queries in turn with the given JSON responses, recording the variables sent.
+type fakeClient struct {
+	responses []string
+	vars      []map[string]interface{}
+}
+
+func (f *fakeClient) Query(ctx context.Context, q interface{}, variables map[string]interface{}) error {
+	f.vars = append(f.vars, variables)
+
+	response := f.responses[0]
+	f.responses = f.responses[1:]
+
+	return json.Unmarshal([]byte(response), q)
+}
+
+func TestBlocksResolver(t *testing.T) {
+	client := &fakeClient{responses: []string{
+		`{"blocks": [{"number": "18319881", "timestamp": "1697031095"}]}`,
+		`{"blocks": []}`,
+	}}
+	resolver := NewResolver(client, nil)
+
+	block, err := resolver.BlockAt(context.Background(), 1697031100)
+	if err != nil {
+		t.Fatalf("BlockAt: unexpected error %v", err)
+	}
+
+	want := &Block{Number: 18319881, Timestamp: 1697031095, Exact: true}
+	if !reflect.DeepEqual(block, want) {
+		t.Errorf("BlockAt: got %+v, but want %+v", block, want)
+	}
+
+	wantVars := map[string]interface{}{"where": subgraph.Block_filter{"timestamp_lte": "1697031100"}}
+	if !reflect.DeepEqual(client.vars[0], wantVars) {
+		t.Errorf("BlockAt: sent %v, but want %v", client.vars[0], wantVars)
+	}
+
+	_, err = resolver.TimeOf(context.Background(), 99999999)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("TimeOf: error = %v, but want %v", err, ErrNotFound)
+	}
+
+	wantVars = map[string]interface{}{"where": subgraph.Block_filter{"number": "99999999"}}
+	if !reflect.DeepEqual(client.vars[1], wantVars) {
+		t.Errorf("TimeOf: sent %v, but want %v", client.vars[1], wantVars)
+	}
+}
+
+func TestSwapsResolverBlockAt(t *testing.T) {
+	tests := []struct {
+		name      string
+		timestamp int64
+		responses []string
+		want      *Block
+		wantErr   error
+	}{
+		{
+			name:      "next swap in the next block",
+			timestamp: 1697031100,
+			responses: []string{
+				`{"swaps": [{"timestamp": "1697031095", "transaction": {"blockNumber": "18319881"}}]}`,
+				`{"swaps": [{"timestamp": "1697031107", "transaction": {"blockNumber": "18319882"}}]}`,
+			},
+			want: &Block{Number: 18319881, Timestamp: 1697031095, Exact: true},
+		},
+		{
+			name:      "swap at the timestamp",
+			timestamp: 1697031095,
+			responses: []string{
+				`{"swaps": [{"timestamp": "1697031095", "transaction": {"blockNumber": "18319881"}}]}`,
+			},
+			want: &Block{Number: 18319881, Timestamp: 1697031095, Exact: true},
+		},
+		{
+			name:      "blocks without swaps in between",
+			timestamp: 1697031100,
+			responses: []string{
+				`{"swaps": [{"timestamp": "1697031095", "transaction": {"blockNumber": "18319881"}}]}`,
+				`{"swaps": [{"timestamp": "1697031131", "transaction": {"blockNumber": "18319884"}}]}`,
+			},
+			want: &Block{Number: 18319881, Timestamp: 1697031095, Exact: false},
+		},
+		{
+			name:      "interpolated between swaps",
+			timestamp: 1697031120,
+			responses: []string{
+				`{"swaps": [{"timestamp": "1697031095", "transaction": {"blockNumber": "18319881"}}]}`,
+				`{"swaps": [{"timestamp": "1697031131", "transaction": {"blockNumber": "18319884"}}]}`,
+			},
+			want: &Block{Number: 18319883, Timestamp: 1697031119, Exact: false},
+		},
+		{
+			name:      "after the last swap",
+			timestamp: 1697031100,
+			responses: []string{
+				`{"swaps": [{"timestamp": "1697031095", "transaction": {"blockNumber": "18319881"}}]}`,
+				`{"swaps": []}`,
+			},
+			want: &Block{Number: 18319881, Timestamp: 1697031095, Exact: false},
+		},
+		{
+			name:      "before the first swap",
+			timestamp: 1697031100,
+			responses: []string{
+				`{"swaps": []}`,
+			},
+			wantErr: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{responses: tt.responses}
+			resolver := NewResolver(nil, client)
+
+			block, err := resolver.BlockAt(context.Background(), tt.timestamp)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BlockAt: error = %v, but want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(block, tt.want) {
+				t.Errorf("BlockAt: got %+v, but want %+v", block, tt.want)
+			}
+
+			wantVars := map[string]interface{}{
+				"orderDirection": subgraph.Desc,
+				"where":          subgraph.Swap_filter{"timestamp_lte": strconv.FormatInt(tt.timestamp, 10)},
+			}
+			if !reflect.DeepEqual(client.vars[0], wantVars) {
+				t.Errorf("BlockAt: sent %v, but want %v", client.vars[0], wantVars)
+			}
+		})
+	}
+}
+
+func TestSwapsResolverTimeOf(t *testing.T) {
+	tests := []struct {
+		name      string
+		number    int64
+		responses []string
+		want      *Block
+		wantErr   error
+	}{
+		{
+			name:   "swap in the block",
+			number: 18319881,
+			responses: []string{
+				`{"swaps": [{"timestamp": "1697031095", "transaction": {"blockNumber": "18319881"}}]}`,
+			},
+			want: &Block{Number: 18319881, Timestamp: 1697031095, Exact: true},
+		},
+		{
+			name:   "interpolated between swaps",
+			number: 18319883,
+			responses: []string{
+				`{"swaps": [{"timestamp": "1697031095", "transaction": {"blockNumber": "18319881"}}]}`,
+				`{"swaps": [{"timestamp": "1697031131", "transaction": {"blockNumber": "18319884"}}]}`,
+			},
+			want: &Block{Number: 18319883, Timestamp: 1697031119, Exact: false},
+		},
+		{
+			name:   "after the last swap",
+			number: 18319883,
+			responses: []string{
+				`{"swaps": [{"timestamp": "1697031095", "transaction": {"blockNumber": "18319881"}}]}`,
+				`{"swaps": []}`,
+			},
+			wantErr: ErrNotFound,
+		},
+		{
+			name:   "before the first swap",
+			number: 1,
+			responses: []string{
+				`{"swaps": []}`,
+			},
+			wantErr: ErrNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{responses: tt.responses}
+			resolver := NewResolver(nil, client)
+
+			block, err := resolver.TimeOf(context.Background(), tt.number)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TimeOf: error = %v, but want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(block, tt.want) {
+				t.Errorf("TimeOf: got %+v, but want %+v", block, tt.want)
+			}
+
+			wantVars := map[string]interface{}{
+				"orderDirection": subgraph.Desc,
+				"where": subgraph.Swap_filter{
+					"transaction_": map[string]interface{}{"blockNumber_lte": strconv.FormatInt(tt.number, 10)},
+				},
+			}
+			if !reflect.DeepEqual(client.vars[0], wantVars) {
+				t.Errorf("TimeOf: sent %v, but want %v", client.vars[0], wantVars)
+			}
+			if len(client.vars) > 1 {
+				wantVars = map[string]interface{}{
+					"orderDirection": subgraph.Asc,
+					"where": subgraph.Swap_filter{
+						"transaction_": map[string]interface{}{"blockNumber_gt": strconv.FormatInt(tt.number, 10)},
+					},
+				}
+				if !reflect.DeepEqual(client.vars[1], wantVars) {
+					t.Errorf("TimeOf: sent %v, but want %v", client.vars[1], wantVars)
+				}
+			}
+		})
+	}
+}
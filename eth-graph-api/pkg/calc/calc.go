@@ -3,6 +3,7 @@ package calc
 import (
 	"fmt"
 	"math/big"
+	"strings"
 )
 
 // SumNumbers takes a slice of strings, each representing a number,
@@ -37,3 +38,218 @@ func SumNumbers(numbers []string) (string, error) {
 
 	return sum.Text('f', 10), nil
 }
+
+// SumDecimals takes a slice of strings, each representing a decimal number,
+// and returns their exact sum as a string.
+// It utilizes big.Rat, so that amounts with as many as 18 decimal places, such as token amounts,
+// are summed without rounding, and writes the sum with at least 10 decimal places,
+// and more if needed to keep it exact.
+//
+// Parameters:
+//   - `numbers`: A slice of strings, where each string represents a number that
+//     needs to be included in the sum.
+//
+// Returns:
+//   - A string representing the exact sum of all the numbers in the input slice.
+//   - An error, which will be non-nil if any of the input strings cannot be
+//     parsed into a number.
+//
+// Example usage:
+//
+//	sum, err := SumDecimals([]string{"0.100000000000000001", "2.5"})
+//
+// In the example above, if err is nil, sum will be "2.600000000000000001".
+func SumDecimals(numbers []string) (string, error) {
+	sum := new(big.Rat)
+	for _, numStr := range numbers {
+		num, ok := new(big.Rat).SetString(numStr)
+		if !ok || strings.Contains(numStr, "/") {
+			return "", fmt.Errorf("invalid number: %s", numStr)
+		}
+		sum.Add(sum, num)
+	}
+
+	return sum.FloatString(decimalPlaces(sum, 10)), nil
+}
+
+// decimalPlaces returns the number of decimal places needed to write the decimal fraction `r` exactly,
+// but at least `min`. The denominator of a decimal fraction is 2^a * 5^b, and it takes max(a, b) decimal places.
+func decimalPlaces(r *big.Rat, min int) int {
+	den := new(big.Int).Set(r.Denom())
+	places := 0
+	for _, factor := range []int64{2, 5} {
+		f := big.NewInt(factor)
+		n := 0
+		for m := new(big.Int); ; n++ {
+			q, _ := new(big.Int).QuoRem(den, f, m)
+			if m.Sign() != 0 {
+				break
+			}
+			den = q
+		}
+		if n > places {
+			places = n
+		}
+	}
+
+	if places < min {
+		return min
+	}
+	return places
+}
+
+// MultiplyNumbers takes two strings, each representing a number,
+// and returns their product as a string.
+// It utilizes big.Float with 256 bits of precision, so that the product
+// of two subgraph decimals does not lose precision on the way.
+//
+// Parameters:
+//   - `a`, `b`: Strings representing the numbers to be multiplied.
+//
+// Returns:
+//   - A string representing the product of the two numbers.
+//   - An error, which will be non-nil if any of the input strings cannot be
+//     parsed into a number.
+//
+// Example usage:
+//
+//	product, err := MultiplyNumbers("0.5", "1600.25")
+//
+// In the example above, if err is nil, product will be "800.1250000000".
+func MultiplyNumbers(a string, b string) (string, error) {
+	const precision = 256
+
+	x, _, err := big.ParseFloat(a, 10, precision, big.ToNearestEven)
+	if err != nil {
+		return "", fmt.Errorf("invalid number: %s, error: %v", a, err)
+	}
+
+	y, _, err := big.ParseFloat(b, 10, precision, big.ToNearestEven)
+	if err != nil {
+		return "", fmt.Errorf("invalid number: %s, error: %v", b, err)
+	}
+
+	product := new(big.Float).SetPrec(precision).Mul(x, y)
+
+	return product.Text('f', 10), nil
+}
+
+// CompareNumbers takes two strings, each representing a number,
+// and compares them without losing precision on the way.
+//
+// Parameters:
+//   - `a`, `b`: Strings representing the numbers to be compared.
+//
+// Returns:
+//   - -1 if a < b, 0 if a == b, and +1 if a > b.
+//   - An error, which will be non-nil if any of the input strings cannot be
+//     parsed into a number.
+//
+// Example usage:
+//
+//	cmp, err := CompareNumbers("1600.25", "800.125")
+//
+// In the example above, if err is nil, cmp will be 1.
+func CompareNumbers(a string, b string) (int, error) {
+	const precision = 256
+
+	x, _, err := big.ParseFloat(a, 10, precision, big.ToNearestEven)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number: %s, error: %v", a, err)
+	}
+
+	y, _, err := big.ParseFloat(b, 10, precision, big.ToNearestEven)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number: %s, error: %v", b, err)
+	}
+
+	return x.Cmp(y), nil
+}
+
+// DivideNumbers takes two strings, each representing a number,
+// and returns their quotient as a string.
+// It utilizes big.Float with 256 bits of precision, and keeps 18 decimal places,
+// so that small quotients, such as the prices of cheap tokens, keep their significant digits.
+//
+// Parameters:
+//   - `a`: A string representing the dividend.
+//   - `b`: A string representing the divisor.
+//
+// Returns:
+//   - A string representing the quotient of the two numbers.
+//   - An error, which will be non-nil if any of the input strings cannot be
+//     parsed into a number, or if the divisor is zero.
+//
+// Example usage:
+//
+//	quotient, err := DivideNumbers("48391.42", "30.71")
+//
+// In the example above, if err is nil, quotient will be "1575.754477368935200261".
+func DivideNumbers(a string, b string) (string, error) {
+	const precision = 256
+
+	x, _, err := big.ParseFloat(a, 10, precision, big.ToNearestEven)
+	if err != nil {
+		return "", fmt.Errorf("invalid number: %s, error: %v", a, err)
+	}
+
+	y, _, err := big.ParseFloat(b, 10, precision, big.ToNearestEven)
+	if err != nil {
+		return "", fmt.Errorf("invalid number: %s, error: %v", b, err)
+	}
+
+	if y.Sign() == 0 {
+		return "", fmt.Errorf("division by zero: %s / %s", a, b)
+	}
+
+	quotient := new(big.Float).SetPrec(precision).Quo(x, y)
+
+	return quotient.Text('f', 18), nil
+}
+
+// PriceFromSqrtPriceX96 takes the square root price of a Uniswap v3 pool, as a Q64.96 fixed-point number,
+// and the decimals of its tokens, and returns the price the subgraph calls token0Price: the amount of token0
+// one token1 is worth. The price is computed exactly as a fraction, then written with 18 decimal places.
+//
+// Parameters:
+//   - `sqrtPriceX96`: A string representing the square root price, e.g. the one a swap left the pool at.
+//   - `decimals0`, `decimals1`: Strings representing the decimals of token0 and token1.
+//
+// Returns:
+//   - A string representing the amount of token0 per token1.
+//   - An error, which will be non-nil if any of the input strings cannot be
+//     parsed, or if the square root price is not positive.
+//
+// Example usage:
+//
+//	price, err := PriceFromSqrtPriceX96("1998326233819040146329034457185493", "6", "18")
+//
+// In the example above, the pool holds USDC (6 decimals) and WETH (18 decimals), and if err is nil,
+// price will be the USDC price of one WETH, "1571.905334915384021085".
+func PriceFromSqrtPriceX96(sqrtPriceX96 string, decimals0 string, decimals1 string) (string, error) {
+	sqrtPrice, ok := new(big.Int).SetString(sqrtPriceX96, 10)
+	if !ok || sqrtPrice.Sign() <= 0 {
+		return "", fmt.Errorf("invalid sqrt price: %s", sqrtPriceX96)
+	}
+
+	d0, ok := new(big.Int).SetString(decimals0, 10)
+	if !ok || d0.Sign() < 0 {
+		return "", fmt.Errorf("invalid decimals: %s", decimals0)
+	}
+
+	d1, ok := new(big.Int).SetString(decimals1, 10)
+	if !ok || d1.Sign() < 0 {
+		return "", fmt.Errorf("invalid decimals: %s", decimals1)
+	}
+
+	ten := big.NewInt(10)
+
+	// token0Price = 2^192 * 10^decimals1 / (sqrtPriceX96^2 * 10^decimals0)
+	num := new(big.Int).Lsh(big.NewInt(1), 192)
+	num.Mul(num, new(big.Int).Exp(ten, d1, nil))
+
+	den := new(big.Int).Mul(sqrtPrice, sqrtPrice)
+	den.Mul(den, new(big.Int).Exp(ten, d0, nil))
+
+	return new(big.Rat).SetFrac(num, den).FloatString(18), nil
+}
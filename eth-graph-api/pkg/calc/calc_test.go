@@ -50,3 +50,221 @@ func TestSumNumbers(t *testing.T) {
 		})
 	}
 }
+
+func TestSumDecimals(t *testing.T) {
+	tests := []struct {
+		name      string
+		numbers   []string
+		want      string
+		wantError bool
+	}{
+		{
+			name:    "valid numbers",
+			numbers: []string{"123456789.123456789", "987654321.987654321"},
+			want:    "1111111111.1111111100",
+		},
+		{
+			name:    "token amounts with 18 decimal places",
+			numbers: []string{"123456789.123456789123456789", "987654321.987654321987654321"},
+			want:    "1111111111.11111111111111111",
+		},
+		{
+			name:    "negative numbers",
+			numbers: []string{"-0.000000000000000001", "1"},
+			want:    "0.999999999999999999",
+		},
+		{
+			name:    "no numbers",
+			numbers: nil,
+			want:    "0.0000000000",
+		},
+		{
+			name:      "one invalid number",
+			numbers:   []string{"123456789.123456789", "invalid"},
+			wantError: true,
+		},
+		{
+			name:      "one fraction",
+			numbers:   []string{"1/3"},
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SumDecimals(tt.numbers)
+
+			if (err != nil) != tt.wantError {
+				t.Errorf("SumDecimals: for %v error = %v, wantError %v", tt.numbers, err, tt.wantError)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("SumDecimals: for %v = %v, want %v", tt.numbers, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiplyNumbers(t *testing.T) {
+	tests := []struct {
+		name      string
+		a         string
+		b         string
+		want      string
+		wantError bool
+	}{
+		{
+			name:      "valid numbers",
+			a:         "0.5",
+			b:         "1600.25",
+			want:      "800.1250000000",
+			wantError: false,
+		},
+		{
+			name:      "numbers with many decimal places",
+			a:         "0.000623517823612538716235",
+			b:         "1603.123456789123456789",
+			want:      "0.9995760488",
+			wantError: false,
+		},
+		{
+			name:      "invalid number",
+			a:         "invalid",
+			b:         "1",
+			want:      "",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MultiplyNumbers(tt.a, tt.b)
+
+			if (err != nil) != tt.wantError {
+				t.Errorf("MultiplyNumbers: for %v, %v error = %v, wantError %v", tt.a, tt.b, err, tt.wantError)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("MultiplyNumbers: for %v, %v = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareNumbers(t *testing.T) {
+	tests := []struct {
+		name      string
+		a         string
+		b         string
+		want      int
+		wantError bool
+	}{
+		{name: "greater", a: "1600.25", b: "800.125", want: 1},
+		{name: "less", a: "9.99", b: "10", want: -1},
+		{name: "equal with different notation", a: "1.50", b: "1.5", want: 0},
+		{name: "differ far after the point", a: "0.100000000000000000000001", b: "0.1", want: 1},
+		{name: "invalid number", a: "1", b: "invalid", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CompareNumbers(tt.a, tt.b)
+
+			if (err != nil) != tt.wantError {
+				t.Errorf("CompareNumbers: for %v, %v error = %v, wantError %v", tt.a, tt.b, err, tt.wantError)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("CompareNumbers: for %v, %v = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDivideNumbers(t *testing.T) {
+	tests := []struct {
+		name      string
+		a         string
+		b         string
+		want      string
+		wantError bool
+	}{
+		{name: "price from amounts", a: "48391.42", b: "30.71", want: "1575.754477368935200261"},
+		{name: "small quotient", a: "0.0123", b: "1000000", want: "0.000000012300000000"},
+		{name: "division by zero", a: "1", b: "0.0", wantError: true},
+		{name: "invalid number", a: "invalid", b: "1", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DivideNumbers(tt.a, tt.b)
+
+			if (err != nil) != tt.wantError {
+				t.Errorf("DivideNumbers: for %v, %v error = %v, wantError %v", tt.a, tt.b, err, tt.wantError)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("DivideNumbers: for %v, %v = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceFromSqrtPriceX96(t *testing.T) {
+	tests := []struct {
+		name         string
+		sqrtPriceX96 string
+		decimals0    string
+		decimals1    string
+		want         string
+		wantError    bool
+	}{
+		{
+			name:         "USDC/WETH pool",
+			sqrtPriceX96: "1998326233819040146329034457185493",
+			decimals0:    "6",
+			decimals1:    "18",
+			want:         "1571.905334915384021085",
+		},
+		{
+			name:         "price of one",
+			sqrtPriceX96: "79228162514264337593543950336",
+			decimals0:    "18",
+			decimals1:    "18",
+			want:         "1.000000000000000000",
+		},
+		{
+			name:         "zero sqrt price",
+			sqrtPriceX96: "0",
+			decimals0:    "18",
+			decimals1:    "18",
+			wantError:    true,
+		},
+		{
+			name:         "invalid decimals",
+			sqrtPriceX96: "79228162514264337593543950336",
+			decimals0:    "eighteen",
+			decimals1:    "18",
+			wantError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := PriceFromSqrtPriceX96(tt.sqrtPriceX96, tt.decimals0, tt.decimals1)
+
+			if (err != nil) != tt.wantError {
+				t.Errorf("PriceFromSqrtPriceX96: for %v error = %v, wantError %v", tt.sqrtPriceX96, err, tt.wantError)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("PriceFromSqrtPriceX96: for %v = %v, want %v", tt.sqrtPriceX96, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package candle
+
+import (
+	"eth-graph-api/pkg/calc"
+)
+
+const (
+	// Hour is the length of the buckets of the subgraph's hourly time series, in seconds
+	Hour = 60 * 60
+	// Day is the length of the buckets of the subgraph's daily time series, in seconds
+	Day = 24 * Hour
+)
+
+// DefaultCount is the number of candles covered by the default time range of a candles request
+// of an interval taken from the hourly or daily time series.
+const DefaultCount = 100
+
+// DefaultTapeWindow is the default time range, in seconds, of a candles request of an interval
+// shorter than an hour, built out of the swap tape. It is kept short, as active pools and tokens
+// swap thousands of times an hour, more than fit in a single request.
+const DefaultTapeWindow = Hour
+
+// intervals maps the supported candle intervals to their length in seconds.
+var intervals = map[string]int64{
+	"5m":  5 * 60,
+	"15m": 15 * 60,
+	"1h":  Hour,
+	"4h":  4 * Hour,
+	"1d":  Day,
+}
+
+// Candle holds the open, high, low and close prices over an interval starting at Date (UNIX timestamp),
+// along with the volume in USD traded over it
+type Candle struct {
+	Date      int64  `json:"date"`
+	Open      string `json:"open"`
+	High      string `json:"high"`
+	Low       string `json:"low"`
+	Close     string `json:"close"`
+	VolumeUSD string `json:"volumeUSD"`
+}
+
+// Tick is a single price observation made at Time, such as the price a swap worth VolumeUSD left a pool at
+type Tick struct {
+	Time      int64
+	Price     string
+	VolumeUSD string
+}
+
+// ParseInterval returns the length in seconds of the candle interval named `interval`:
+// one of "5m", "15m", "1h", "4h" and "1d". It returns false if there is no such interval.
+func ParseInterval(interval string) (int64, bool) {
+	seconds, ok := intervals[interval]
+	return seconds, ok
+}
+
+// DefaultWindow returns the default time range, in seconds, of a candles request of `interval` seconds:
+// DefaultCount candles if they come from the hourly or daily time series, and DefaultTapeWindow otherwise.
+func DefaultWindow(interval int64) int64 {
+	if interval%Hour != 0 {
+		return DefaultTapeWindow
+	}
+	return interval * DefaultCount
+}
+
+// Span returns the time range, both ends inclusive, covered by the candles of `interval` seconds
+// starting between `from` and `to`: from the first interval boundary at or after `from`,
+// to the end of the interval `to` falls in. The range is empty, i.e. start > end,
+// if no interval starts between `from` and `to`.
+func Span(from, to, interval int64) (int64, int64) {
+	start := (from + interval - 1) / interval * interval
+	end := to/interval*interval + interval - 1
+	if start > to {
+		return start, start - 1
+	}
+	return start, end
+}
+
+// FromTicks builds the candles of `interval` seconds out of `ticks` ordered by time,
+// the first and the last tick of an interval making its open and close. Intervals without ticks are left out.
+// It returns an error if a price or volume is not a number.
+func FromTicks(ticks []Tick, interval int64) ([]Candle, error) {
+	candles := make([]Candle, 0, len(ticks))
+	for _, t := range ticks {
+		candles = append(candles, Candle{
+			Date:      t.Time,
+			Open:      t.Price,
+			High:      t.Price,
+			Low:       t.Price,
+			Close:     t.Price,
+			VolumeUSD: t.VolumeUSD,
+		})
+	}
+
+	return Merge(candles, interval)
+}
+
+// Merge merges `candles` ordered by date into candles of `interval` seconds, a multiple of their own interval,
+// e.g. hourly candles into 4-hour ones. The merged candles open with the first candle of their interval,
+// close with the last one, span the highest high and the lowest low, and sum up the volumes exactly.
+// It returns an error if a price or volume is not a number.
+func Merge(candles []Candle, interval int64) ([]Candle, error) {
+	merged := make([]Candle, 0, len(candles))
+	var volumes []string
+
+	for _, c := range candles {
+		date := c.Date / interval * interval
+
+		if len(merged) == 0 || merged[len(merged)-1].Date != date {
+			if err := sumVolumes(merged, volumes); err != nil {
+				return nil, err
+			}
+
+			c.Date = date
+			merged = append(merged, c)
+			volumes = []string{c.VolumeUSD}
+			continue
+		}
+
+		last := &merged[len(merged)-1]
+
+		cmp, err := calc.CompareNumbers(c.High, last.High)
+		if err != nil {
+			return nil, err
+		}
+		if cmp > 0 {
+			last.High = c.High
+		}
+
+		cmp, err = calc.CompareNumbers(c.Low, last.Low)
+		if err != nil {
+			return nil, err
+		}
+		if cmp < 0 {
+			last.Low = c.Low
+		}
+
+		last.Close = c.Close
+		volumes = append(volumes, c.VolumeUSD)
+	}
+
+	if err := sumVolumes(merged, volumes); err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// sumVolumes sets the volume of the last of the `merged` candles, if any, to the exact sum of `volumes`.
+// The volume of a candle merged out of a single one is left as it is.
+func sumVolumes(merged []Candle, volumes []string) error {
+	if len(merged) == 0 || len(volumes) < 2 {
+		return nil
+	}
+
+	sum, err := calc.SumDecimals(volumes)
+	if err != nil {
+		return err
+	}
+	merged[len(merged)-1].VolumeUSD = sum
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package candle
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		interval string
+		want     int64
+		wantOk   bool
+	}{
+		{interval: "5m", want: 300, wantOk: true},
+		{interval: "4h", want: 14400, wantOk: true},
+		{interval: "1d", want: 86400, wantOk: true},
+		{interval: "1w", wantOk: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.interval, func(t *testing.T) {
+			got, ok := ParseInterval(tt.interval)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ParseInterval: for %v = %v, %v, but want %v, %v", tt.interval, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestDefaultWindow(t *testing.T) {
+	tests := []struct {
+		interval int64
+		want     int64
+	}{
+		{interval: 5 * 60, want: Hour},
+		{interval: 15 * 60, want: Hour},
+		{interval: Hour, want: 100 * Hour},
+		{interval: Day, want: 100 * Day},
+	}
+
+	for _, tt := range tests {
+		if got := DefaultWindow(tt.interval); got != tt.want {
+			t.Errorf("DefaultWindow: for %v = %v, but want %v", tt.interval, got, tt.want)
+		}
+	}
+}
+
+func TestSpan(t *testing.T) {
+	tests := []struct {
+		name      string
+		from      int64
+		to        int64
+		interval  int64
+		wantStart int64
+		wantEnd   int64
+	}{
+		{name: "aligned", from: 1697029200, to: 1697032800, interval: Hour, wantStart: 1697029200, wantEnd: 1697036399},
+		{name: "unaligned", from: 1697029201, to: 1697032801, interval: 900, wantStart: 1697030100, wantEnd: 1697033699},
+		{name: "no interval starts", from: 1697029201, to: 1697029500, interval: Hour, wantStart: 1697032800, wantEnd: 1697032799},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := Span(tt.from, tt.to, tt.interval)
+			if start != tt.wantStart || end != tt.wantEnd {
+				t.Errorf("Span: got %v-%v, but want %v-%v", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestFromTicks(t *testing.T) {
+	ticks := []Tick{
+		{Time: 1697029210, Price: "1570.5", VolumeUSD: "1000"},
+		{Time: 1697029500, Price: "1574.25", VolumeUSD: "2000.5"},
+		{Time: 1697029800, Price: "1568", VolumeUSD: "500"},
+		{Time: 1697030000, Price: "1571", VolumeUSD: "250"},
+		{Time: 1697031000, Price: "1569", VolumeUSD: "100"},
+	}
+
+	got, err := FromTicks(ticks, 900)
+	if err != nil {
+		t.Fatalf("FromTicks: unexpected error %v", err)
+	}
+
+	want := []Candle{
+		{Date: 1697029200, Open: "1570.5", High: "1574.25", Low: "1568", Close: "1571", VolumeUSD: "3750.5000000000"},
+		{Date: 1697031000, Open: "1569", High: "1569", Low: "1569", Close: "1569", VolumeUSD: "100"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FromTicks: got %+v, but want %+v", got, want)
+	}
+
+	_, err = FromTicks([]Tick{{Time: 1697029210, Price: "1570.5", VolumeUSD: "1000"}, {Time: 1697029211, Price: "n/a", VolumeUSD: "1"}}, 900)
+	if err == nil {
+		t.Errorf("FromTicks: expected an error for a price that is not a number")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	hours := []Candle{
+		{Date: 1697018400, Open: "1560", High: "1565", Low: "1558", Close: "1562", VolumeUSD: "10"},
+		{Date: 1697022000, Open: "1562", High: "1580", Low: "1561", Close: "1575", VolumeUSD: "20"},
+		{Date: 1697025600, Open: "1575", High: "1576", Low: "1555", Close: "1557", VolumeUSD: "30"},
+		{Date: 1697032800, Open: "1557", High: "1559", Low: "1550", Close: "1551", VolumeUSD: "40"},
+	}
+
+	got, err := Merge(hours, 4*Hour)
+	if err != nil {
+		t.Fatalf("Merge: unexpected error %v", err)
+	}
+
+	want := []Candle{
+		{Date: 1697011200, Open: "1560", High: "1580", Low: "1558", Close: "1575", VolumeUSD: "30.0000000000"},
+		{Date: 1697025600, Open: "1575", High: "1576", Low: "1550", Close: "1551", VolumeUSD: "70.0000000000"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Merge: got %+v, but want %+v", got, want)
+	}
+}
+
+func TestMergeVolumePrecision(t *testing.T) {
+	hours := []Candle{
+		{Date: 1697011200, Open: "1", High: "1", Low: "1", Close: "1", VolumeUSD: "123456789.123456789123456789"},
+		{Date: 1697014800, Open: "1", High: "1", Low: "1", Close: "1", VolumeUSD: "987654321.987654321987654321"},
+		{Date: 1697025600, Open: "1", High: "1", Low: "1", Close: "1", VolumeUSD: "0.100000000000000001"},
+	}
+
+	got, err := Merge(hours, 4*Hour)
+	if err != nil {
+		t.Fatalf("Merge: unexpected error %v", err)
+	}
+
+	if got[0].VolumeUSD != "1111111111.11111111111111111" {
+		t.Errorf("Merge: volume = %v, but want the exact sum", got[0].VolumeUSD)
+	}
+	if got[1].VolumeUSD != "0.100000000000000001" {
+		t.Errorf("Merge: volume = %v, but want the volume of the single candle", got[1].VolumeUSD)
+	}
+}
@@ -0,0 +1,192 @@
+package paging
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// PageSize is the largest number of entities the subgraph returns for a single collection query.
+const PageSize = 1000
+
+// MaxItems is the ceiling on the number of entities gathered by a single Collect call,
+// protecting the API from walking huge collections. It can be configured at startup.
+var MaxItems = 10000
+
+var (
+	// ErrTooManyItems is returned by CollectAll when the collection is longer than MaxItems.
+	ErrTooManyItems = errors.New("too many items")
+	// ErrInvalidCursor is returned by fetch functions that cannot make sense of the given cursor.
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// Page is the response envelope of list endpoints: a slice of a collection
+// along with the opaque cursor to resume after it.
+// NextCursor is empty and HasMore is false when the collection is exhausted.
+type Page[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// NewPage wraps `data` into a Page, turning `next`, the cursor returned by Collect, into an opaque one.
+// A nil `data` becomes an empty slice, so the envelope always holds an array.
+func NewPage[T any](data []T, next string) *Page[T] {
+	if data == nil {
+		data = []T{}
+	}
+
+	return &Page[T]{
+		Data:       data,
+		NextCursor: EncodeCursor(next),
+		HasMore:    next != "",
+	}
+}
+
+// EncodeCursor turns a cursor used against the subgraph into the opaque form handed out to clients,
+// so that they do not come to rely on its content. An empty cursor stays empty.
+func EncodeCursor(cursor string) string {
+	if cursor == "" {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString([]byte(cursor))
+}
+
+// DecodeCursor turns an opaque cursor received from a client back into the cursor used against the subgraph.
+// An empty cursor stays empty, while a malformed one results in ErrInvalidCursor.
+func DecodeCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(raw) == 0 {
+		return "", ErrInvalidCursor
+	}
+
+	return string(raw), nil
+}
+
+// LinkHeader builds the RFC 5988 "Link" header pointing to the page after the one requested by `r`,
+// i.e. the same request with its "cursor" query parameter set to `next`, see requestScheme for its scheme.
+// It returns an empty header when there is no next page.
+func LinkHeader(r *http.Request, next string) http.Header {
+	header := http.Header{}
+	if next == "" {
+		return header
+	}
+
+	query := r.URL.Query()
+	query.Set("cursor", next)
+
+	link := url.URL{
+		Scheme:   requestScheme(r),
+		Host:     r.Host,
+		Path:     r.URL.Path,
+		RawQuery: query.Encode(),
+	}
+
+	header.Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", link.String()))
+
+	return header
+}
+
+// Fetch retrieves at most `first` entities following the entity identified by `cursor`
+// in the collection's order, or from the start of the collection if `cursor` is empty.
+// Typically, the cursor is the ID of the last entity seen and the query filters with `id_gt`.
+type Fetch[T any] func(ctx context.Context, cursor string, first int) ([]T, error)
+
+// Collect walks a subgraph collection page by page, starting after `cursor`,
+// until `limit` entities are gathered or the collection is exhausted.
+// A `limit` below 1 or above MaxItems is treated as MaxItems.
+//
+// Parameters:
+//   - `fetch`: retrieves a single page of the collection.
+//   - `cursorOf`: derives the cursor to continue after an entity.
+//   - `cursor`: where to start, empty for the start of the collection.
+//   - `limit`: the number of entities to gather.
+//
+// Returns:
+//   - The gathered entities.
+//   - The cursor to continue after the last entity returned, empty if there is nothing left.
+//   - An error, if fetching any of the pages fails.
+func Collect[T any](ctx context.Context, fetch Fetch[T], cursorOf func(T) string, cursor string, limit int) ([]T, string, error) {
+	if limit < 1 || limit > MaxItems {
+		limit = MaxItems
+	}
+
+	var items []T
+	for {
+		// Ask for one entity more than needed, to learn whether the collection goes on
+		first := limit - len(items) + 1
+		if first > PageSize {
+			first = PageSize
+		}
+
+		page, err := fetch(ctx, cursor, first)
+		if err != nil {
+			return nil, "", err
+		}
+
+		items = append(items, page...)
+
+		if len(items) > limit {
+			items = items[:limit]
+			return items, cursorOf(items[limit-1]), nil
+		}
+
+		if len(page) < first {
+			return items, "", nil
+		}
+
+		cursor = cursorOf(page[len(page)-1])
+	}
+}
+
+// CollectAll walks a subgraph collection from its start until it is exhausted, see Collect.
+// It returns ErrTooManyItems rather than a truncated result when the collection is longer than MaxItems.
+func CollectAll[T any](ctx context.Context, fetch Fetch[T], cursorOf func(T) string) ([]T, error) {
+	items, next, err := Collect(ctx, fetch, cursorOf, "", MaxItems)
+	if err != nil {
+		return nil, err
+	}
+
+	if next != "" {
+		return nil, ErrTooManyItems
+	}
+
+	return items, nil
+}
+
+// RangeError turns an error of collecting a time series of the given `interval` into the error reported to the client:
+// a timeout is returned as is, ErrTooManyItems tells the time range is too long for the interval,
+// and `issue` describes any other failure.
+func RangeError(err error, interval string, issue string) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	if errors.Is(err, ErrTooManyItems) {
+		return errors.New("range too long for " + interval + " interval")
+	}
+	return errors.New(issue)
+}
+
+// requestScheme returns the scheme `r` was made with by the client. Behind a proxy terminating TLS,
+// it is told by the first value of the "X-Forwarded-Proto" header, otherwise by whether `r` came over TLS.
+func requestScheme(r *http.Request) string {
+	proto, _, _ := strings.Cut(r.Header.Get("X-Forwarded-Proto"), ",")
+	proto = strings.ToLower(strings.TrimSpace(proto))
+	if proto == "http" || proto == "https" {
+		return proto
+	}
+
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
@@ -0,0 +1,254 @@
+package paging
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+// collection returns a Fetch over `n` entities whose cursors are their positions,
+// recording the page sizes it was asked for.
+func collection(n int, calls *[]int) Fetch[int] {
+	return func(ctx context.Context, cursor string, first int) ([]int, error) {
+		*calls = append(*calls, first)
+
+		start := 0
+		if cursor != "" {
+			c, err := strconv.Atoi(cursor)
+			if err != nil {
+				return nil, ErrInvalidCursor
+			}
+			start = c + 1
+		}
+
+		var page []int
+		for i := start; i < n && len(page) < first; i++ {
+			page = append(page, i)
+		}
+
+		return page, nil
+	}
+}
+
+func cursorOf(i int) string {
+	return strconv.Itoa(i)
+}
+
+func TestCollect(t *testing.T) {
+	tests := []struct {
+		name      string
+		size      int
+		cursor    string
+		limit     int
+		wantLen   int
+		wantFirst int
+		wantNext  string
+		wantCalls []int
+	}{
+		{
+			name:      "small limit",
+			size:      10,
+			limit:     5,
+			wantLen:   5,
+			wantFirst: 0,
+			wantNext:  "4",
+			wantCalls: []int{6},
+		},
+		{
+			name:      "exhausted collection",
+			size:      3,
+			limit:     5,
+			wantLen:   3,
+			wantFirst: 0,
+			wantNext:  "",
+			wantCalls: []int{6},
+		},
+		{
+			name:      "resumed from cursor",
+			size:      10,
+			cursor:    "4",
+			limit:     5,
+			wantLen:   5,
+			wantFirst: 5,
+			wantNext:  "",
+			wantCalls: []int{6},
+		},
+		{
+			name:      "limit beyond a single page",
+			size:      2500,
+			limit:     2000,
+			wantLen:   2000,
+			wantFirst: 0,
+			wantNext:  "1999",
+			wantCalls: []int{1000, 1000, 1},
+		},
+		{
+			name:      "no limit stops at the ceiling",
+			size:      MaxItems + 10,
+			limit:     0,
+			wantLen:   MaxItems,
+			wantFirst: 0,
+			wantNext:  strconv.Itoa(MaxItems - 1),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []int
+			items, next, err := Collect(context.Background(), collection(tt.size, &calls), cursorOf, tt.cursor, tt.limit)
+
+			if err != nil {
+				t.Fatalf("Collect: unexpected error %v", err)
+			}
+			if len(items) != tt.wantLen {
+				t.Errorf("Collect: len = %v, but want %v", len(items), tt.wantLen)
+			}
+			if len(items) > 0 && items[0] != tt.wantFirst {
+				t.Errorf("Collect: first item = %v, but want %v", items[0], tt.wantFirst)
+			}
+			if next != tt.wantNext {
+				t.Errorf("Collect: next cursor = %q, but want %q", next, tt.wantNext)
+			}
+			if tt.wantCalls != nil && !equal(calls, tt.wantCalls) {
+				t.Errorf("Collect: page sizes = %v, but want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCollectError(t *testing.T) {
+	var calls []int
+	_, _, err := Collect(context.Background(), collection(10, &calls), cursorOf, "bad", 5)
+
+	if !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("Collect: error = %v, but want %v", err, ErrInvalidCursor)
+	}
+}
+
+func TestCollectAll(t *testing.T) {
+	var calls []int
+	items, err := CollectAll(context.Background(), collection(1500, &calls), cursorOf)
+	if err != nil {
+		t.Fatalf("CollectAll: unexpected error %v", err)
+	}
+	if len(items) != 1500 {
+		t.Errorf("CollectAll: len = %v, but want %v", len(items), 1500)
+	}
+
+	_, err = CollectAll(context.Background(), collection(MaxItems+1, &calls), cursorOf)
+	if !errors.Is(err, ErrTooManyItems) {
+		t.Errorf("CollectAll: error = %v, but want %v", err, ErrTooManyItems)
+	}
+}
+
+func TestRangeError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: context.DeadlineExceeded, want: context.DeadlineExceeded.Error()},
+		{err: ErrTooManyItems, want: "range too long for 5m interval"},
+		{err: errors.New("some error"), want: "issue to get candles"},
+	}
+
+	for _, tt := range tests {
+		err := RangeError(tt.err, "5m", "issue to get candles")
+		if err.Error() != tt.want {
+			t.Errorf("RangeError: for %v = %v, but want %v", tt.err, err, tt.want)
+		}
+	}
+
+	if err := RangeError(context.DeadlineExceeded, "5m", "issue"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("RangeError: error = %v, but want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	raw := "62817263.12,0x11b815efb8f581194ae79006d24e0d814b7697f6"
+
+	encoded := EncodeCursor(raw)
+	if encoded == raw {
+		t.Errorf("EncodeCursor: cursor %q is not opaque", encoded)
+	}
+
+	decoded, err := DecodeCursor(encoded)
+	if err != nil {
+		t.Fatalf("DecodeCursor: unexpected error %v", err)
+	}
+	if decoded != raw {
+		t.Errorf("DecodeCursor: cursor = %q, but want %q", decoded, raw)
+	}
+
+	if EncodeCursor("") != "" {
+		t.Errorf("EncodeCursor: empty cursor should stay empty")
+	}
+	if decoded, err := DecodeCursor(""); err != nil || decoded != "" {
+		t.Errorf("DecodeCursor: empty cursor should stay empty, got %q, %v", decoded, err)
+	}
+	if _, err := DecodeCursor("not a cursor!"); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("DecodeCursor: error = %v, but want %v", err, ErrInvalidCursor)
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	page := NewPage([]int{1, 2}, "2")
+	if !page.HasMore || page.NextCursor != EncodeCursor("2") {
+		t.Errorf("NewPage: got has_more %v and cursor %q", page.HasMore, page.NextCursor)
+	}
+
+	page = NewPage[int](nil, "")
+	if page.HasMore || page.NextCursor != "" {
+		t.Errorf("NewPage: last page got has_more %v and cursor %q", page.HasMore, page.NextCursor)
+	}
+	if page.Data == nil {
+		t.Errorf("NewPage: data should be an empty slice, not nil")
+	}
+}
+
+func TestLinkHeader(t *testing.T) {
+	r := httptest.NewRequest("GET", "http://localhost:3000/v1/blocks/1/swaps?limit=2&cursor=old", nil)
+
+	got := LinkHeader(r, "bmV4dA").Get("Link")
+	want := `<http://localhost:3000/v1/blocks/1/swaps?cursor=bmV4dA&limit=2>; rel="next"`
+	if got != want {
+		t.Errorf("LinkHeader: got %q, but want %q", got, want)
+	}
+
+	if got := LinkHeader(r, "").Get("Link"); got != "" {
+		t.Errorf("LinkHeader: got %q for the last page, but want none", got)
+	}
+}
+
+func TestLinkHeaderForwardedProto(t *testing.T) {
+	tests := []struct {
+		proto string
+		want  string
+	}{
+		{proto: "https", want: `<https://localhost:3000/v1/swaps?cursor=bmV4dA>; rel="next"`},
+		{proto: "HTTPS, http", want: `<https://localhost:3000/v1/swaps?cursor=bmV4dA>; rel="next"`},
+		{proto: "gopher", want: `<http://localhost:3000/v1/swaps?cursor=bmV4dA>; rel="next"`},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "http://localhost:3000/v1/swaps", nil)
+		r.Header.Set("X-Forwarded-Proto", tt.proto)
+
+		if got := LinkHeader(r, "bmV4dA").Get("Link"); got != tt.want {
+			t.Errorf("LinkHeader: for %q got %q, but want %q", tt.proto, got, tt.want)
+		}
+	}
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package subgraph
+
+import (
+	"sort"
+	"strconv"
+)
+
+// The types below mirror input types of the Uniswap v3 subgraph schema.
+// The GraphQL client derives the type of a query variable from the name of its Go type,
+// so these names have to match the schema exactly, e.g. a value of type Pool_filter
+// is declared as `$where: Pool_filter!` in the generated query.
+
+// OrderDirection is the sort direction of a collection query, either "asc" or "desc".
+type OrderDirection string
+
+// Pool_orderBy is the field a pools collection query is sorted by.
+type Pool_orderBy string
+
+// Pool_filter is the "where" argument of a pools collection query.
+type Pool_filter map[string]interface{}
+
+// Token_filter is the "where" argument of a tokens collection query.
+type Token_filter map[string]interface{}
+
+// Swap_filter is the "where" argument of a swaps collection query.
+type Swap_filter map[string]interface{}
+
+// Mint_filter is the "where" argument of a mints collection query.
+type Mint_filter map[string]interface{}
+
+// Burn_filter is the "where" argument of a burns collection query.
+type Burn_filter map[string]interface{}
+
+// Collect_filter is the "where" argument of a collects collection query.
+type Collect_filter map[string]interface{}
+
+// Tick_filter is the "where" argument of a ticks collection query.
+type Tick_filter map[string]interface{}
+
+// Block_filter is the "where" argument of a blocks collection query.
+// Unlike the types above, it belongs to the schema of a blocks subgraph, which indexes Ethereum blocks.
+type Block_filter map[string]interface{}
+
+const (
+	Asc  OrderDirection = "asc"
+	Desc OrderDirection = "desc"
+)
+
+// InExecutionOrder returns a copy of the `events` in the order they were executed: by block, and then
+// by log index within the block, as told by `position`. The subgraph stamps an event with the timestamp
+// of its block, and block timestamps strictly increase, so the timestamp stands for the block.
+// The `events` themselves are left untouched.
+func InExecutionOrder[T any](events []T, position func(T) (timestamp string, logIndex string)) []T {
+	sorted := make([]T, len(events))
+	copy(sorted, events)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		tsi, lii := position(sorted[i])
+		tsj, lij := position(sorted[j])
+
+		ti, _ := strconv.ParseInt(tsi, 10, 64)
+		tj, _ := strconv.ParseInt(tsj, 10, 64)
+		if ti != tj {
+			return ti < tj
+		}
+		li, _ := strconv.Atoi(lii)
+		lj, _ := strconv.Atoi(lij)
+		return li < lj
+	})
+
+	return sorted
+}
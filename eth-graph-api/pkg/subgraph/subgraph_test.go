@@ -0,0 +1,77 @@
+package subgraph
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// TestTypeNames guards the Go type names, as the GraphQL client uses them
+// verbatim as the declared types of query variables.
+func TestTypeNames(t *testing.T) {
+	tests := []struct {
+		value interface{}
+		want  string
+	}{
+		{value: Asc, want: "OrderDirection"},
+		{value: Pool_orderBy("feeTier"), want: "Pool_orderBy"},
+		{value: Pool_filter{}, want: "Pool_filter"},
+		{value: Swap_filter{}, want: "Swap_filter"},
+		{value: Mint_filter{}, want: "Mint_filter"},
+		{value: Burn_filter{}, want: "Burn_filter"},
+		{value: Collect_filter{}, want: "Collect_filter"},
+		{value: Tick_filter{}, want: "Tick_filter"},
+		{value: Block_filter{}, want: "Block_filter"},
+	}
+
+	for _, tt := range tests {
+		if got := reflect.TypeOf(tt.value).Name(); got != tt.want {
+			t.Errorf("type name = %v, but want %v", got, tt.want)
+		}
+	}
+}
+
+func TestFilterMarshal(t *testing.T) {
+	filter := Pool_filter{"or": []Pool_filter{{"token0": "0xa"}, {"token1": "0xa"}}}
+
+	out, err := json.Marshal(filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"or":[{"token0":"0xa"},{"token1":"0xa"}]}`
+	if string(out) != want {
+		t.Errorf("json.Marshal = %s, but want %s", out, want)
+	}
+}
+
+func TestInExecutionOrder(t *testing.T) {
+	type event struct {
+		id        string
+		timestamp string
+		logIndex  string
+	}
+
+	events := []event{
+		{id: "c", timestamp: "1697031107", logIndex: "2"},
+		{id: "b", timestamp: "1697031095", logIndex: "10"},
+		{id: "a", timestamp: "1697031095", logIndex: "9"},
+	}
+	original := append([]event(nil), events...)
+
+	sorted := InExecutionOrder(events, func(e event) (string, string) {
+		return e.timestamp, e.logIndex
+	})
+
+	var ids string
+	for _, e := range sorted {
+		ids += e.id
+	}
+	if ids != "abc" {
+		t.Errorf("InExecutionOrder: got %v, but want abc", ids)
+	}
+
+	if !reflect.DeepEqual(events, original) {
+		t.Errorf("InExecutionOrder: changed the events to %v", events)
+	}
+}
@@ -0,0 +1,460 @@
+// Package v3math implements the concentrated-liquidity math of Uniswap v3 pools over math/big:
+// the conversions between ticks and square root prices (TickMath), the amounts of tokens
+// between two prices (SqrtPriceMath), and a single step of a swap within a tick range, fee included (SwapMath).
+// The functions follow the pool contracts, rounding the same way, so that swaps can be simulated off-chain.
+// Prices are square roots of the price of token0 in token1, in raw token units, as Q64.96 fixed-point numbers.
+package v3math
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"sort"
+)
+
+const (
+	// MinTick is the lowest tick a pool price can reach, the price being 1.0001^tick
+	MinTick = -887272
+	// MaxTick is the highest tick a pool price can reach
+	MaxTick = -MinTick
+	// FeeDenominator is the denominator of the fees of pools, which are expressed in hundredths of a basis point (pips),
+	// e.g. the 0.3% fee tier is 3000
+	FeeDenominator = 1000000
+)
+
+var (
+	// Q96 is 2^96, the scale of Q64.96 fixed-point numbers
+	Q96 = new(big.Int).Lsh(big.NewInt(1), 96)
+	// MinSqrtRatio is the square root price at MinTick
+	MinSqrtRatio = big.NewInt(4295128739)
+	// MaxSqrtRatio is the square root price at MaxTick
+	MaxSqrtRatio, _ = new(big.Int).SetString("1461446703485210103287273052203988822378723970342", 10)
+
+	maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	q32        = new(big.Int).Lsh(big.NewInt(1), 32)
+)
+
+var (
+	// ErrTickOutOfRange is returned for ticks below MinTick or above MaxTick.
+	ErrTickOutOfRange = errors.New("tick out of range")
+	// ErrSqrtRatioOutOfRange is returned for square root prices below MinSqrtRatio or not below MaxSqrtRatio.
+	ErrSqrtRatioOutOfRange = errors.New("sqrt ratio out of range")
+	// ErrInsufficientLiquidity is returned when the liquidity cannot provide the requested output.
+	ErrInsufficientLiquidity = errors.New("insufficient liquidity")
+	// ErrNegativeLiquidity is returned when crossing a tick would leave a negative liquidity.
+	ErrNegativeLiquidity = errors.New("negative liquidity")
+)
+
+// tickRatios are the Q128.128 values of 1/sqrt(1.0001)^(2^i), for each bit i of a tick, as in TickMath.
+var tickRatios = []string{
+	"fffcb933bd6fad37aa2d162d1a594001",
+	"fff97272373d413259a46990580e213a",
+	"fff2e50f5f656932ef12357cf3c7fdcc",
+	"ffe5caca7e10e4e61c3624eaa0941cd0",
+	"ffcb9843d60f6159c9db58835c926644",
+	"ff973b41fa98c081472e6896dfb254c0",
+	"ff2ea16466c96a3843ec78b326b52861",
+	"fe5dee046a99a2a811c461f1969c3053",
+	"fcbe86c7900a88aedcffc83b479aa3a4",
+	"f987a7253ac413176f2b074cf7815e54",
+	"f3392b0822b70005940c7a398e4b70f3",
+	"e7159475a2c29b7443b29c7fa6e889d9",
+	"d097f3bdfd2022b8845ad8f792aa5825",
+	"a9f746462d870fdf8a65dc1f90e061e5",
+	"70d869a156d2a1b890bb3df62baf32f7",
+	"31be135f97d08fd981231505542fcfa6",
+	"9aa508b5b7a84e1c677de54f3e99bc9",
+	"5d6af8dedb81196699c329225ee604",
+	"2216e584f5fa1ea926041bedfe98",
+	"48a170391f7dc42444e8fa2",
+}
+
+// GetSqrtRatioAtTick returns the square root price at the `tick`, sqrt(1.0001^tick) as a Q64.96 number,
+// exactly as the TickMath library of the pool contracts computes it.
+// It returns ErrTickOutOfRange if the tick is below MinTick or above MaxTick.
+func GetSqrtRatioAtTick(tick int) (*big.Int, error) {
+	if tick < MinTick || tick > MaxTick {
+		return nil, ErrTickOutOfRange
+	}
+
+	absTick := tick
+	if absTick < 0 {
+		absTick = -absTick
+	}
+
+	ratio := new(big.Int).Lsh(big.NewInt(1), 128)
+	for i, hex := range tickRatios {
+		if absTick&(1<<i) != 0 {
+			c, _ := new(big.Int).SetString(hex, 16)
+			ratio.Mul(ratio, c)
+			ratio.Rsh(ratio, 128)
+		}
+	}
+
+	if tick > 0 {
+		ratio.Div(maxUint256, ratio)
+	}
+
+	// Rounds up from Q128.128 to Q64.96, so that GetTickAtSqrtRatio of the result is the tick
+	sqrtPriceX96, rem := new(big.Int).QuoRem(ratio, q32, new(big.Int))
+	if rem.Sign() != 0 {
+		sqrtPriceX96.Add(sqrtPriceX96, big.NewInt(1))
+	}
+
+	return sqrtPriceX96, nil
+}
+
+// GetTickAtSqrtRatio returns the greatest tick whose square root price is at most `sqrtPriceX96`,
+// the result of the TickMath library of the pool contracts. The tick is estimated in floating point,
+// then settled exactly with GetSqrtRatioAtTick.
+// It returns ErrSqrtRatioOutOfRange if the price is below MinSqrtRatio or not below MaxSqrtRatio.
+func GetTickAtSqrtRatio(sqrtPriceX96 *big.Int) (int, error) {
+	if sqrtPriceX96.Cmp(MinSqrtRatio) < 0 || sqrtPriceX96.Cmp(MaxSqrtRatio) >= 0 {
+		return 0, ErrSqrtRatioOutOfRange
+	}
+
+	sqrtPrice, _ := new(big.Float).Quo(new(big.Float).SetInt(sqrtPriceX96), new(big.Float).SetInt(Q96)).Float64()
+	tick := int(math.Floor(2 * math.Log(sqrtPrice) / math.Log(1.0001)))
+	if tick < MinTick {
+		tick = MinTick
+	} else if tick > MaxTick-1 {
+		tick = MaxTick - 1
+	}
+
+	for {
+		ratio, _ := GetSqrtRatioAtTick(tick)
+		if ratio.Cmp(sqrtPriceX96) > 0 {
+			tick--
+			continue
+		}
+		next, _ := GetSqrtRatioAtTick(tick + 1)
+		if next.Cmp(sqrtPriceX96) <= 0 {
+			tick++
+			continue
+		}
+		return tick, nil
+	}
+}
+
+// GetAmount0Delta returns the amount of token0 between the square root prices `sqrtRatioA` and `sqrtRatioB`,
+// in any order, for the `liquidity`: liquidity / sqrt(lower) - liquidity / sqrt(upper), rounded up if `roundUp`.
+func GetAmount0Delta(sqrtRatioA, sqrtRatioB, liquidity *big.Int, roundUp bool) *big.Int {
+	if sqrtRatioA.Cmp(sqrtRatioB) > 0 {
+		sqrtRatioA, sqrtRatioB = sqrtRatioB, sqrtRatioA
+	}
+
+	numerator1 := new(big.Int).Lsh(liquidity, 96)
+	numerator2 := new(big.Int).Sub(sqrtRatioB, sqrtRatioA)
+
+	if roundUp {
+		return divRoundingUp(mulDivRoundingUp(numerator1, numerator2, sqrtRatioB), sqrtRatioA)
+	}
+	return new(big.Int).Quo(mulDiv(numerator1, numerator2, sqrtRatioB), sqrtRatioA)
+}
+
+// GetAmount1Delta returns the amount of token1 between the square root prices `sqrtRatioA` and `sqrtRatioB`,
+// in any order, for the `liquidity`: liquidity * (sqrt(upper) - sqrt(lower)), rounded up if `roundUp`.
+func GetAmount1Delta(sqrtRatioA, sqrtRatioB, liquidity *big.Int, roundUp bool) *big.Int {
+	if sqrtRatioA.Cmp(sqrtRatioB) > 0 {
+		sqrtRatioA, sqrtRatioB = sqrtRatioB, sqrtRatioA
+	}
+
+	diff := new(big.Int).Sub(sqrtRatioB, sqrtRatioA)
+
+	if roundUp {
+		return mulDivRoundingUp(liquidity, diff, Q96)
+	}
+	return mulDiv(liquidity, diff, Q96)
+}
+
+// GetNextSqrtPriceFromInput returns the square root price after adding `amountIn` of token0 (`zeroForOne`)
+// or token1 to the `liquidity` at `sqrtPriceX96`, rounding so that the price does not pass the target
+// the input can reach.
+func GetNextSqrtPriceFromInput(sqrtPriceX96, liquidity, amountIn *big.Int, zeroForOne bool) (*big.Int, error) {
+	if zeroForOne {
+		return nextSqrtPriceFromAmount0RoundingUp(sqrtPriceX96, liquidity, amountIn, true)
+	}
+	return nextSqrtPriceFromAmount1RoundingDown(sqrtPriceX96, liquidity, amountIn, true)
+}
+
+// GetNextSqrtPriceFromOutput returns the square root price after taking `amountOut` of token1 (`zeroForOne`)
+// or token0 out of the `liquidity` at `sqrtPriceX96`, rounding so that the output is covered.
+// It returns ErrInsufficientLiquidity if the liquidity cannot provide the output.
+func GetNextSqrtPriceFromOutput(sqrtPriceX96, liquidity, amountOut *big.Int, zeroForOne bool) (*big.Int, error) {
+	if zeroForOne {
+		return nextSqrtPriceFromAmount1RoundingDown(sqrtPriceX96, liquidity, amountOut, false)
+	}
+	return nextSqrtPriceFromAmount0RoundingUp(sqrtPriceX96, liquidity, amountOut, false)
+}
+
+// nextSqrtPriceFromAmount0RoundingUp returns the square root price after adding (`add`) or removing
+// `amount` of token0: liquidity * sqrtPrice / (liquidity ± amount * sqrtPrice), rounded up.
+func nextSqrtPriceFromAmount0RoundingUp(sqrtPriceX96, liquidity, amount *big.Int, add bool) (*big.Int, error) {
+	if amount.Sign() == 0 {
+		return new(big.Int).Set(sqrtPriceX96), nil
+	}
+
+	numerator1 := new(big.Int).Lsh(liquidity, 96)
+	product := new(big.Int).Mul(amount, sqrtPriceX96)
+
+	var denominator *big.Int
+	if add {
+		denominator = new(big.Int).Add(numerator1, product)
+	} else {
+		if numerator1.Cmp(product) <= 0 {
+			return nil, ErrInsufficientLiquidity
+		}
+		denominator = new(big.Int).Sub(numerator1, product)
+	}
+
+	return mulDivRoundingUp(numerator1, sqrtPriceX96, denominator), nil
+}
+
+// nextSqrtPriceFromAmount1RoundingDown returns the square root price after adding (`add`) or removing
+// `amount` of token1: sqrtPrice ± amount / liquidity, rounded down.
+func nextSqrtPriceFromAmount1RoundingDown(sqrtPriceX96, liquidity, amount *big.Int, add bool) (*big.Int, error) {
+	shifted := new(big.Int).Lsh(amount, 96)
+
+	if add {
+		return new(big.Int).Add(sqrtPriceX96, new(big.Int).Quo(shifted, liquidity)), nil
+	}
+
+	quotient := divRoundingUp(shifted, liquidity)
+	if sqrtPriceX96.Cmp(quotient) <= 0 {
+		return nil, ErrInsufficientLiquidity
+	}
+
+	return new(big.Int).Sub(sqrtPriceX96, quotient), nil
+}
+
+// SwapStep is the result of swapping within a single range of constant liquidity, see ComputeSwapStep.
+type SwapStep struct {
+	SqrtPriceNextX96 *big.Int
+	AmountIn         *big.Int
+	AmountOut        *big.Int
+	FeeAmount        *big.Int
+}
+
+// ComputeSwapStep swaps from `sqrtPriceCurrentX96` towards `sqrtPriceTargetX96` within the `liquidity`,
+// as the SwapMath library of the pool contracts does. A non-negative `amountRemaining` is an exact input,
+// the fee of `feePips` (over FeeDenominator) taken out of it, and a negative one is an exact output.
+// The swap direction follows from the prices: token0 for token1 when the price goes down.
+// It returns the price reached, the input spent (without the fee), the output and the fee.
+func ComputeSwapStep(sqrtPriceCurrentX96, sqrtPriceTargetX96, liquidity, amountRemaining *big.Int, feePips int64) (*SwapStep, error) {
+	zeroForOne := sqrtPriceCurrentX96.Cmp(sqrtPriceTargetX96) >= 0
+	exactIn := amountRemaining.Sign() >= 0
+
+	fee := big.NewInt(feePips)
+	denominator := big.NewInt(FeeDenominator)
+
+	step := &SwapStep{}
+	var err error
+
+	if exactIn {
+		remainingLessFee := mulDiv(amountRemaining, new(big.Int).Sub(denominator, fee), denominator)
+		if zeroForOne {
+			step.AmountIn = GetAmount0Delta(sqrtPriceTargetX96, sqrtPriceCurrentX96, liquidity, true)
+		} else {
+			step.AmountIn = GetAmount1Delta(sqrtPriceCurrentX96, sqrtPriceTargetX96, liquidity, true)
+		}
+		if remainingLessFee.Cmp(step.AmountIn) >= 0 {
+			step.SqrtPriceNextX96 = new(big.Int).Set(sqrtPriceTargetX96)
+		} else {
+			step.SqrtPriceNextX96, err = GetNextSqrtPriceFromInput(sqrtPriceCurrentX96, liquidity, remainingLessFee, zeroForOne)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		amountOutRemaining := new(big.Int).Neg(amountRemaining)
+		if zeroForOne {
+			step.AmountOut = GetAmount1Delta(sqrtPriceTargetX96, sqrtPriceCurrentX96, liquidity, false)
+		} else {
+			step.AmountOut = GetAmount0Delta(sqrtPriceCurrentX96, sqrtPriceTargetX96, liquidity, false)
+		}
+		if amountOutRemaining.Cmp(step.AmountOut) >= 0 {
+			step.SqrtPriceNextX96 = new(big.Int).Set(sqrtPriceTargetX96)
+		} else {
+			step.SqrtPriceNextX96, err = GetNextSqrtPriceFromOutput(sqrtPriceCurrentX96, liquidity, amountOutRemaining, zeroForOne)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	max := sqrtPriceTargetX96.Cmp(step.SqrtPriceNextX96) == 0
+
+	if zeroForOne {
+		if !max || !exactIn {
+			step.AmountIn = GetAmount0Delta(step.SqrtPriceNextX96, sqrtPriceCurrentX96, liquidity, true)
+		}
+		if !max || exactIn {
+			step.AmountOut = GetAmount1Delta(step.SqrtPriceNextX96, sqrtPriceCurrentX96, liquidity, false)
+		}
+	} else {
+		if !max || !exactIn {
+			step.AmountIn = GetAmount1Delta(sqrtPriceCurrentX96, step.SqrtPriceNextX96, liquidity, true)
+		}
+		if !max || exactIn {
+			step.AmountOut = GetAmount0Delta(sqrtPriceCurrentX96, step.SqrtPriceNextX96, liquidity, false)
+		}
+	}
+
+	// The output cannot exceed the exact output requested
+	if !exactIn && step.AmountOut.Cmp(new(big.Int).Neg(amountRemaining)) > 0 {
+		step.AmountOut = new(big.Int).Neg(amountRemaining)
+	}
+
+	if exactIn && step.SqrtPriceNextX96.Cmp(sqrtPriceTargetX96) != 0 {
+		// The target was not reached, so the whole remainder is taken, the rest of the input being the fee
+		step.FeeAmount = new(big.Int).Sub(amountRemaining, step.AmountIn)
+	} else {
+		step.FeeAmount = mulDivRoundingUp(step.AmountIn, fee, new(big.Int).Sub(denominator, fee))
+	}
+
+	return step, nil
+}
+
+// TickLiquidity is an initialized tick of a pool: crossing it upwards adds LiquidityNet to the active liquidity,
+// and crossing it downwards removes it.
+type TickLiquidity struct {
+	Index        int
+	LiquidityNet *big.Int
+}
+
+// SwapResult is the result of a swap simulated by SwapExactInput.
+type SwapResult struct {
+	// AmountIn is the input spent, fee included, which is less than the input requested
+	// if the liquidity ran out before it was spent
+	AmountIn     *big.Int
+	AmountOut    *big.Int
+	FeeAmount    *big.Int
+	SqrtPriceX96 *big.Int
+	Tick         int
+	TicksCrossed int
+}
+
+// SwapExactInput simulates the swap of `amountIn` of token0 (`zeroForOne`) or token1 through a pool
+// at `sqrtPriceX96` and `tick`, with the active `liquidity`, the fee of `feePips` and the initialized `ticks`,
+// ordered by index, as the swap function of the pool contracts does: it swaps step by step up to the next
+// initialized tick, updating the active liquidity with its net liquidity once crossed, until the input
+// is spent or the price reaches its bound.
+func SwapExactInput(sqrtPriceX96 *big.Int, tick int, liquidity *big.Int, feePips int64, ticks []TickLiquidity, zeroForOne bool, amountIn *big.Int) (*SwapResult, error) {
+	if amountIn.Sign() <= 0 {
+		return nil, errors.New("amount in must be positive")
+	}
+
+	limit := new(big.Int).Add(MinSqrtRatio, big.NewInt(1))
+	if !zeroForOne {
+		limit = new(big.Int).Sub(MaxSqrtRatio, big.NewInt(1))
+	}
+
+	result := &SwapResult{
+		AmountOut:    new(big.Int),
+		FeeAmount:    new(big.Int),
+		SqrtPriceX96: new(big.Int).Set(sqrtPriceX96),
+		Tick:         tick,
+	}
+	remaining := new(big.Int).Set(amountIn)
+	liquidity = new(big.Int).Set(liquidity)
+
+	// next is the index in `ticks` of the next initialized tick in the direction of the swap:
+	// at or below the current tick downwards, and above it upwards
+	next := sort.Search(len(ticks), func(i int) bool { return ticks[i].Index > tick })
+	if zeroForOne {
+		next--
+	}
+
+	for remaining.Sign() > 0 && result.SqrtPriceX96.Cmp(limit) != 0 {
+		initialized := next >= 0 && next < len(ticks)
+		tickNext := MinTick
+		if !zeroForOne {
+			tickNext = MaxTick
+		}
+		if initialized {
+			tickNext = ticks[next].Index
+		}
+
+		sqrtPriceNextTick, err := GetSqrtRatioAtTick(tickNext)
+		if err != nil {
+			return nil, err
+		}
+
+		target := sqrtPriceNextTick
+		if (zeroForOne && target.Cmp(limit) < 0) || (!zeroForOne && target.Cmp(limit) > 0) {
+			target = limit
+		}
+
+		step, err := ComputeSwapStep(result.SqrtPriceX96, target, liquidity, remaining, feePips)
+		if err != nil {
+			return nil, err
+		}
+
+		remaining.Sub(remaining, step.AmountIn)
+		remaining.Sub(remaining, step.FeeAmount)
+		result.AmountOut.Add(result.AmountOut, step.AmountOut)
+		result.FeeAmount.Add(result.FeeAmount, step.FeeAmount)
+
+		if step.SqrtPriceNextX96.Cmp(sqrtPriceNextTick) == 0 {
+			if initialized {
+				delta := ticks[next].LiquidityNet
+				if zeroForOne {
+					delta = new(big.Int).Neg(delta)
+					next--
+				} else {
+					next++
+				}
+				liquidity, err = AddDelta(liquidity, delta)
+				if err != nil {
+					return nil, err
+				}
+				result.TicksCrossed++
+			}
+			result.Tick = tickNext
+			if zeroForOne {
+				result.Tick = tickNext - 1
+			}
+		} else if step.SqrtPriceNextX96.Cmp(result.SqrtPriceX96) != 0 {
+			result.Tick, err = GetTickAtSqrtRatio(step.SqrtPriceNextX96)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		result.SqrtPriceX96 = step.SqrtPriceNextX96
+	}
+
+	result.AmountIn = new(big.Int).Sub(amountIn, remaining)
+
+	return result, nil
+}
+
+// AddDelta adds the signed `delta`, e.g. the net liquidity of a tick crossed, to the `liquidity`.
+// It returns ErrNegativeLiquidity if the result would be negative.
+func AddDelta(liquidity, delta *big.Int) (*big.Int, error) {
+	sum := new(big.Int).Add(liquidity, delta)
+	if sum.Sign() < 0 {
+		return nil, ErrNegativeLiquidity
+	}
+	return sum, nil
+}
+
+// mulDiv returns a * b / denominator, rounded down.
+func mulDiv(a, b, denominator *big.Int) *big.Int {
+	product := new(big.Int).Mul(a, b)
+	return product.Quo(product, denominator)
+}
+
+// mulDivRoundingUp returns a * b / denominator, rounded up.
+func mulDivRoundingUp(a, b, denominator *big.Int) *big.Int {
+	return divRoundingUp(new(big.Int).Mul(a, b), denominator)
+}
+
+// divRoundingUp returns x / y, rounded up, for non-negative x and positive y.
+func divRoundingUp(x, y *big.Int) *big.Int {
+	quotient, rem := new(big.Int).QuoRem(x, y, new(big.Int))
+	if rem.Sign() != 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	return quotient
+}
@@ -0,0 +1,377 @@
+package v3math
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+const (
+	// sqrtPrice1 is the square root price of 1, 2^96
+	sqrtPrice1 = "79228162514264337593543950336"
+	// sqrtPrice101 is the square root price of 1.01
+	sqrtPrice101 = "79623317895830914510639640423"
+	// sqrtPrice121 is the square root price of 1.21
+	sqrtPrice121 = "87150978765690771352898345369"
+	// sqrtPrice1000 is the square root price of 10
+	sqrtPrice1000 = "250541448375047931186413801569"
+)
+
+func bigInt(t *testing.T, s string) *big.Int {
+	t.Helper()
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("invalid integer %q", s)
+	}
+	return n
+}
+
+func TestGetSqrtRatioAtTick(t *testing.T) {
+	tests := []struct {
+		name      string
+		tick      int
+		want      string
+		wantError bool
+	}{
+		{name: "min tick", tick: MinTick, want: MinSqrtRatio.String()},
+		{name: "max tick", tick: MaxTick, want: MaxSqrtRatio.String()},
+		{name: "tick zero", tick: 0, want: sqrtPrice1},
+		{name: "below min tick", tick: MinTick - 1, wantError: true},
+		{name: "above max tick", tick: MaxTick + 1, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSqrtRatioAtTick(tt.tick)
+
+			if (err != nil) != tt.wantError {
+				t.Errorf("GetSqrtRatioAtTick: for %v error = %v, wantError %v", tt.tick, err, tt.wantError)
+				return
+			}
+
+			if err == nil && got.String() != tt.want {
+				t.Errorf("GetSqrtRatioAtTick: for %v = %v, want %v", tt.tick, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSqrtRatioAtTickMatchesFloat(t *testing.T) {
+	q96, _ := new(big.Float).SetInt(Q96).Float64()
+
+	// Covers every bit of the tick, on both sides of zero
+	for bit := 0; bit < 20; bit++ {
+		for _, tick := range []int{1 << bit, -(1 << bit), (1 << bit) + 7, -(1 << bit) - 7} {
+			got, err := GetSqrtRatioAtTick(tick)
+			if err != nil {
+				t.Fatalf("GetSqrtRatioAtTick: for %v error = %v", tick, err)
+			}
+
+			gotFloat, _ := new(big.Float).SetInt(got).Float64()
+			want := math.Pow(1.0001, float64(tick)/2) * q96
+			if math.Abs(gotFloat-want)/want > 1e-9 {
+				t.Errorf("GetSqrtRatioAtTick: for %v = %v, want about %v", tick, gotFloat, want)
+			}
+		}
+	}
+}
+
+func TestGetTickAtSqrtRatio(t *testing.T) {
+	tests := []struct {
+		name         string
+		sqrtPriceX96 *big.Int
+		want         int
+		wantError    bool
+	}{
+		{name: "min sqrt ratio", sqrtPriceX96: MinSqrtRatio, want: MinTick},
+		{name: "below max sqrt ratio", sqrtPriceX96: new(big.Int).Sub(MaxSqrtRatio, big.NewInt(1)), want: MaxTick - 1},
+		{name: "price of one", sqrtPriceX96: Q96, want: 0},
+		{name: "just below price of one", sqrtPriceX96: new(big.Int).Sub(Q96, big.NewInt(1)), want: -1},
+		{name: "below min sqrt ratio", sqrtPriceX96: new(big.Int).Sub(MinSqrtRatio, big.NewInt(1)), wantError: true},
+		{name: "max sqrt ratio", sqrtPriceX96: MaxSqrtRatio, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetTickAtSqrtRatio(tt.sqrtPriceX96)
+
+			if (err != nil) != tt.wantError {
+				t.Errorf("GetTickAtSqrtRatio: for %v error = %v, wantError %v", tt.sqrtPriceX96, err, tt.wantError)
+				return
+			}
+
+			if err == nil && got != tt.want {
+				t.Errorf("GetTickAtSqrtRatio: for %v = %v, want %v", tt.sqrtPriceX96, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetTickAtSqrtRatioRoundTrip(t *testing.T) {
+	for _, tick := range []int{MinTick, -500000, -200000, -60, -1, 0, 1, 60, 201234, 500000, MaxTick - 1} {
+		ratio, _ := GetSqrtRatioAtTick(tick)
+
+		got, err := GetTickAtSqrtRatio(ratio)
+		if err != nil || got != tick {
+			t.Errorf("GetTickAtSqrtRatio: for ratio at tick %v = %v, %v", tick, got, err)
+		}
+
+		if tick == MinTick {
+			continue
+		}
+		got, err = GetTickAtSqrtRatio(new(big.Int).Sub(ratio, big.NewInt(1)))
+		if err != nil || got != tick-1 {
+			t.Errorf("GetTickAtSqrtRatio: for ratio below tick %v = %v, %v", tick, got, err)
+		}
+	}
+}
+
+func TestGetAmountDeltas(t *testing.T) {
+	lower := bigInt(t, sqrtPrice1)
+	upper := bigInt(t, sqrtPrice121)
+	liquidity := bigInt(t, "1000000000000000000")
+
+	tests := []struct {
+		name string
+		got  *big.Int
+		want string
+	}{
+		{name: "amount0 rounded up", got: GetAmount0Delta(lower, upper, liquidity, true), want: "90909090909090910"},
+		{name: "amount0 rounded down", got: GetAmount0Delta(lower, upper, liquidity, false), want: "90909090909090909"},
+		{name: "amount0 reversed prices", got: GetAmount0Delta(upper, lower, liquidity, true), want: "90909090909090910"},
+		{name: "amount1 rounded up", got: GetAmount1Delta(lower, upper, liquidity, true), want: "100000000000000000"},
+		{name: "amount1 rounded down", got: GetAmount1Delta(lower, upper, liquidity, false), want: "99999999999999999"},
+		{name: "amount0 of zero liquidity", got: GetAmount0Delta(lower, upper, big.NewInt(0), true), want: "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got.String() != tt.want {
+				t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetNextSqrtPrice(t *testing.T) {
+	price := bigInt(t, sqrtPrice1)
+	liquidity := bigInt(t, "1000000000000000000")
+	amount := bigInt(t, "100000000000000000")
+
+	tests := []struct {
+		name      string
+		output    bool
+		amount    *big.Int
+		zero      bool
+		want      string
+		wantError bool
+	}{
+		{name: "input of token1", amount: amount, want: "87150978765690771352898345369"},
+		{name: "input of token0", amount: amount, zero: true, want: "72025602285694852357767227579"},
+		{name: "zero input", amount: big.NewInt(0), zero: true, want: sqrtPrice1},
+		{name: "output of token1", output: true, amount: amount, zero: true, want: "71305346262837903834189555302"},
+		{name: "output of token0", output: true, amount: amount, want: "88031291682515930659493278152"},
+		{name: "output of all token1", output: true, amount: liquidity, zero: true, wantError: true},
+		{name: "output of all token0", output: true, amount: liquidity, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *big.Int
+			var err error
+			if tt.output {
+				got, err = GetNextSqrtPriceFromOutput(price, liquidity, tt.amount, tt.zero)
+			} else {
+				got, err = GetNextSqrtPriceFromInput(price, liquidity, tt.amount, tt.zero)
+			}
+
+			if (err != nil) != tt.wantError {
+				t.Errorf("%s: error = %v, wantError %v", tt.name, err, tt.wantError)
+				return
+			}
+
+			if err == nil && got.String() != tt.want {
+				t.Errorf("%s = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComputeSwapStep(t *testing.T) {
+	tests := []struct {
+		name          string
+		price         string
+		target        string
+		liquidity     string
+		amount        string
+		fee           int64
+		wantAmountIn  string
+		wantAmountOut string
+		wantFee       string
+		wantAtTarget  bool
+	}{
+		{
+			name:          "exact in capped at price target",
+			price:         sqrtPrice1,
+			target:        sqrtPrice101,
+			liquidity:     "2000000000000000000",
+			amount:        "1000000000000000000",
+			fee:           600,
+			wantAmountIn:  "9975124224178055",
+			wantAmountOut: "9925619580021728",
+			wantFee:       "5988667735148",
+			wantAtTarget:  true,
+		},
+		{
+			name:          "exact out capped at price target",
+			price:         sqrtPrice1,
+			target:        sqrtPrice101,
+			liquidity:     "2000000000000000000",
+			amount:        "-1000000000000000000",
+			fee:           600,
+			wantAmountIn:  "9975124224178055",
+			wantAmountOut: "9925619580021728",
+			wantFee:       "5988667735148",
+			wantAtTarget:  true,
+		},
+		{
+			name:          "exact in fully spent",
+			price:         sqrtPrice1,
+			target:        sqrtPrice1000,
+			liquidity:     "2000000000000000000",
+			amount:        "1000000000000000000",
+			fee:           600,
+			wantAmountIn:  "999400000000000000",
+			wantAmountOut: "666399946655997866",
+			wantFee:       "600000000000000",
+		},
+		{
+			name:          "exact out fully received",
+			price:         sqrtPrice1,
+			target:        sqrtPrice1000,
+			liquidity:     "2000000000000000000",
+			amount:        "-1000000000000000000",
+			fee:           600,
+			wantAmountIn:  "2000000000000000000",
+			wantAmountOut: "1000000000000000000",
+			wantFee:       "1200720432259356",
+		},
+		{
+			name:          "zero liquidity moves to target",
+			price:         sqrtPrice101,
+			target:        sqrtPrice1,
+			liquidity:     "0",
+			amount:        "1000",
+			fee:           3000,
+			wantAmountIn:  "0",
+			wantAmountOut: "0",
+			wantFee:       "0",
+			wantAtTarget:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := bigInt(t, tt.target)
+
+			got, err := ComputeSwapStep(bigInt(t, tt.price), target, bigInt(t, tt.liquidity), bigInt(t, tt.amount), tt.fee)
+			if err != nil {
+				t.Fatalf("ComputeSwapStep: error = %v", err)
+			}
+
+			if got.AmountIn.String() != tt.wantAmountIn || got.AmountOut.String() != tt.wantAmountOut || got.FeeAmount.String() != tt.wantFee {
+				t.Errorf("ComputeSwapStep = in %v, out %v, fee %v, want in %v, out %v, fee %v",
+					got.AmountIn, got.AmountOut, got.FeeAmount, tt.wantAmountIn, tt.wantAmountOut, tt.wantFee)
+			}
+
+			if (got.SqrtPriceNextX96.Cmp(target) == 0) != tt.wantAtTarget {
+				t.Errorf("ComputeSwapStep: price %v at target %v, want %v", got.SqrtPriceNextX96, target, tt.wantAtTarget)
+			}
+		})
+	}
+}
+
+func TestSwapExactInput(t *testing.T) {
+	liquidity := bigInt(t, "1000000000000000000")
+	price, _ := GetSqrtRatioAtTick(0)
+	sqrt60, _ := GetSqrtRatioAtTick(60)
+	sqrt120, _ := GetSqrtRatioAtTick(120)
+	sqrtMinus60, _ := GetSqrtRatioAtTick(-60)
+
+	ticks := []TickLiquidity{
+		{Index: -60, LiquidityNet: liquidity},
+		{Index: 60, LiquidityNet: liquidity},
+		{Index: 120, LiquidityNet: new(big.Int).Neg(new(big.Int).Mul(liquidity, big.NewInt(2)))},
+	}
+
+	t.Run("within the current range", func(t *testing.T) {
+		amountIn := big.NewInt(1000000000000000)
+
+		got, err := SwapExactInput(price, 0, liquidity, 3000, ticks, true, amountIn)
+		if err != nil {
+			t.Fatalf("SwapExactInput: error = %v", err)
+		}
+
+		step, _ := ComputeSwapStep(price, sqrtMinus60, liquidity, amountIn, 3000)
+		if got.AmountIn.Cmp(amountIn) != 0 || got.AmountOut.Cmp(step.AmountOut) != 0 || got.FeeAmount.Cmp(step.FeeAmount) != 0 {
+			t.Errorf("SwapExactInput = in %v, out %v, fee %v, want in %v, out %v, fee %v",
+				got.AmountIn, got.AmountOut, got.FeeAmount, amountIn, step.AmountOut, step.FeeAmount)
+		}
+		if got.TicksCrossed != 0 || got.Tick != -20 {
+			t.Errorf("SwapExactInput: crossed %v ticks to tick %v, want 0 to -20", got.TicksCrossed, got.Tick)
+		}
+	})
+
+	t.Run("down until the liquidity runs out", func(t *testing.T) {
+		got, err := SwapExactInput(price, 0, liquidity, 3000, ticks, true, liquidity)
+		if err != nil {
+			t.Fatalf("SwapExactInput: error = %v", err)
+		}
+
+		wantIn := GetAmount0Delta(sqrtMinus60, price, liquidity, true)
+		wantIn.Add(wantIn, mulDivRoundingUp(wantIn, big.NewInt(3000), big.NewInt(FeeDenominator-3000)))
+		wantOut := GetAmount1Delta(sqrtMinus60, price, liquidity, false)
+		if got.AmountIn.Cmp(wantIn) != 0 || got.AmountOut.Cmp(wantOut) != 0 {
+			t.Errorf("SwapExactInput = in %v, out %v, want in %v, out %v", got.AmountIn, got.AmountOut, wantIn, wantOut)
+		}
+		if got.TicksCrossed != 1 || got.Tick != MinTick {
+			t.Errorf("SwapExactInput: crossed %v ticks to tick %v, want 1 to %v", got.TicksCrossed, got.Tick, MinTick)
+		}
+	})
+
+	t.Run("up across ticks adding liquidity", func(t *testing.T) {
+		got, err := SwapExactInput(price, 0, liquidity, 500, ticks, false, liquidity)
+		if err != nil {
+			t.Fatalf("SwapExactInput: error = %v", err)
+		}
+
+		wantOut := GetAmount0Delta(price, sqrt60, liquidity, false)
+		wantOut.Add(wantOut, GetAmount0Delta(sqrt60, sqrt120, new(big.Int).Mul(liquidity, big.NewInt(2)), false))
+		if got.AmountOut.Cmp(wantOut) != 0 {
+			t.Errorf("SwapExactInput: out %v, want %v", got.AmountOut, wantOut)
+		}
+		if got.AmountIn.Cmp(liquidity) >= 0 {
+			t.Errorf("SwapExactInput: in %v, want less than %v", got.AmountIn, liquidity)
+		}
+		if got.TicksCrossed != 2 || got.Tick != MaxTick-1 {
+			t.Errorf("SwapExactInput: crossed %v ticks to tick %v, want 2 to %v", got.TicksCrossed, got.Tick, MaxTick-1)
+		}
+	})
+
+	t.Run("zero input", func(t *testing.T) {
+		if _, err := SwapExactInput(price, 0, liquidity, 3000, ticks, true, big.NewInt(0)); err == nil {
+			t.Errorf("SwapExactInput: error = nil, want an error")
+		}
+	})
+}
+
+func TestAddDelta(t *testing.T) {
+	got, err := AddDelta(big.NewInt(10), big.NewInt(-4))
+	if err != nil || got.String() != "6" {
+		t.Errorf("AddDelta = %v, %v, want 6", got, err)
+	}
+
+	if _, err := AddDelta(big.NewInt(10), big.NewInt(-11)); err != ErrNegativeLiquidity {
+		t.Errorf("AddDelta: error = %v, want %v", err, ErrNegativeLiquidity)
+	}
+}
@@ -1,8 +1,10 @@
 package validator
 
 import (
+	"eth-graph-api/pkg/paging"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -35,6 +37,30 @@ func IsValidToken(id string) bool {
 	return true
 }
 
+// IsValidPool checks the validity of a pool ID. Pools are identified by their
+// contract address, so a valid pool ID follows the same rules as a token ID.
+//
+// Parameters:
+// - `id`: a string representing the pool ID to be checked.
+//
+// Returns:
+// - A boolean value indicating whether the pool ID is valid.
+func IsValidPool(id string) bool {
+	return IsValidToken(id)
+}
+
+// IsValidAddress checks the validity of an account address, such as the sender of a swap.
+// A valid address follows the same rules as a token ID.
+//
+// Parameters:
+// - `address`: a string representing the address to be checked.
+//
+// Returns:
+// - A boolean value indicating whether the address is valid.
+func IsValidAddress(address string) bool {
+	return IsValidToken(address)
+}
+
 // IsValidBlock validates the provided block string by ensuring it is a positive integer.
 //
 // Parameters:
@@ -56,6 +82,30 @@ func IsValidBlock(block string) bool {
 	return true
 }
 
+// IsValidBlockTag checks the validity of a block identifier, which is either a block number,
+// as checked by IsValidBlock, or one of the tags resolved against the latest indexed block:
+// - "latest", the latest indexed block itself.
+// - "latest-N", the block N blocks before it, where N is a positive integer.
+// - "finalized", the latest block that is considered final.
+//
+// Parameters:
+// - `block`: a string representing a block identifier to be checked.
+//
+// Returns:
+// - A boolean value indicating whether the block identifier is valid.
+func IsValidBlockTag(block string) bool {
+	switch block {
+	case "latest", "finalized":
+		return true
+	}
+
+	if strings.HasPrefix(block, "latest-") {
+		return IsValidBlock(strings.TrimPrefix(block, "latest-"))
+	}
+
+	return IsValidBlock(block)
+}
+
 // IsValidFirst checks the validity of a query limit value. A valid limit should:
 // - Be between minFirst and maxFirst inclusive.
 //
@@ -77,6 +127,19 @@ func IsValidFirst(first int) bool {
 	return true
 }
 
+// IsValidLimit checks the validity of a paginated query limit. A valid limit should:
+// - Be between 1 and paging.MaxItems inclusive.
+// Unlike the `first` of a single subgraph query, it may exceed 1000, as the results are paged through.
+//
+// Parameters:
+// - `limit`: an integer representing the query limit to be checked.
+//
+// Returns:
+// - A boolean value indicating whether the query limit is valid.
+func IsValidLimit(limit int) bool {
+	return limit >= 1 && limit <= paging.MaxItems
+}
+
 // IsValidRange checks the validity of a time range. The following conditions should be met:
 // - Both "from" and "to" can be successfully parsed into 64-bit integers.
 // - "from" is less than "to".
@@ -103,3 +166,99 @@ func IsValidRange(fromStr, toStr string) bool {
 
 	return from < to && to <= now
 }
+
+// MaxBlockRange is the largest number of blocks a block range may span, about 3 hours of Ethereum blocks.
+const MaxBlockRange = 1000
+
+// MaxTimeRange is the longest time range, in seconds, that swaps may be requested over.
+const MaxTimeRange = 3 * 60 * 60
+
+// IsValidBlockRange checks the validity of a block range. The following conditions should be met:
+// - Both "from" and "to" are valid block numbers.
+// - "from" is less than or equal to "to".
+// - The range spans no more than MaxBlockRange blocks, bounds included.
+//
+// Parameters:
+// - `fromStr`: a string representing the first block of the range.
+// - `toStr`: a string representing the last block of the range.
+//
+// Returns:
+// - A boolean value indicating whether the block range is valid.
+func IsValidBlockRange(fromStr, toStr string) bool {
+	if !IsValidBlock(fromStr) || !IsValidBlock(toStr) {
+		return false
+	}
+
+	from, _ := strconv.Atoi(fromStr)
+	to, _ := strconv.Atoi(toStr)
+
+	return from <= to && to-from < MaxBlockRange
+}
+
+// IsValidTimeRange checks the validity of a time range that has to be walked swap by swap.
+// On top of the conditions of IsValidRange, the range should be no longer than MaxTimeRange.
+//
+// Parameters:
+// - `fromStr`: a string representing the start of the time range.
+// - `toStr`: a string representing the end of the time range.
+//
+// Returns:
+// - A boolean value indicating whether the time range is valid.
+func IsValidTimeRange(fromStr, toStr string) bool {
+	if !IsValidRange(fromStr, toStr) {
+		return false
+	}
+
+	from, _ := strconv.ParseInt(fromStr, 10, 64)
+	to, _ := strconv.ParseInt(toStr, 10, 64)
+
+	return to-from <= MaxTimeRange
+}
+
+// IsValidFeeTier checks that the fee tier, in hundredths of a basis point,
+// is one of the tiers enabled on Uniswap v3: 100, 500, 3000 or 10000.
+//
+// Parameters:
+// - `feeTier`: a string representing the fee tier to be checked.
+//
+// Returns:
+// - A boolean value indicating whether the fee tier is valid.
+func IsValidFeeTier(feeTier string) bool {
+	switch feeTier {
+	case "100", "500", "3000", "10000":
+		return true
+	}
+
+	return false
+}
+
+// IsValidAmount checks that the string is a non-negative decimal number,
+// e.g. "1000" or "0.5", as accepted by the subgraph for BigDecimal filters.
+//
+// Parameters:
+// - `amount`: a string representing the amount to be checked.
+//
+// Returns:
+// - A boolean value indicating whether the amount is valid.
+func IsValidAmount(amount string) bool {
+	isDecimal := regexp.MustCompile(`^[0-9]+(\.[0-9]+)?$`).MatchString
+
+	return isDecimal(amount)
+}
+
+// IsValidTimestamp checks that the string is a positive UNIX timestamp
+// that is not later than the current time.
+//
+// Parameters:
+// - `ts`: a string representing the timestamp to be checked.
+//
+// Returns:
+// - A boolean value indicating whether the timestamp is valid.
+func IsValidTimestamp(ts string) bool {
+	timestamp, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return timestamp > 0 && timestamp <= time.Now().Unix()
+}
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"eth-graph-api/pkg/paging"
 	"strconv"
 	"testing"
 	"time"
@@ -43,6 +44,70 @@ func TestIsValidToken(t *testing.T) {
 	}
 }
 
+func TestIsValidPool(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{
+			name: "valid pool",
+			id:   "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640",
+			want: true,
+		},
+		{
+			name: "too short",
+			id:   "0x88e6a0c2ddd26feeb64f039a2c",
+			want: false,
+		},
+		{
+			name: "invalid character",
+			id:   "0x88e6a0c2ddd26feeb64f039a2c41296fcb3f5640!",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidPool(tt.id); got != tt.want {
+				t.Errorf("IsValidPool: for %v = %v, but want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		address string
+		want    bool
+	}{
+		{
+			name:    "valid address",
+			address: "0x3fC91A3afd70395Cd496C647d5a6CC9D4B2b7FAD",
+			want:    true,
+		},
+		{
+			name:    "empty",
+			address: "",
+			want:    false,
+		},
+		{
+			name:    "invalid character",
+			address: "0x3fc91a3afd70395cd496c647d5a6cc9d4b2b7fa?",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidAddress(tt.address); got != tt.want {
+				t.Errorf("IsValidAddress: for %v = %v, but want %v", tt.address, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIsValidBlock(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -80,6 +145,31 @@ func TestIsValidBlock(t *testing.T) {
 	}
 }
 
+func TestIsValidBlockTag(t *testing.T) {
+	tests := []struct {
+		name  string
+		block string
+		want  bool
+	}{
+		{name: "block number", block: "18319881", want: true},
+		{name: "latest", block: "latest", want: true},
+		{name: "latest with offset", block: "latest-10", want: true},
+		{name: "finalized", block: "finalized", want: true},
+		{name: "zero offset", block: "latest-0", want: false},
+		{name: "forward offset", block: "latest+10", want: false},
+		{name: "offset without number", block: "latest-", want: false},
+		{name: "unknown tag", block: "safe", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidBlockTag(tt.block); got != tt.want {
+				t.Errorf("IsValidBlockTag: for %v = %v, but want %v", tt.block, got, tt.want)
+			}
+		})
+	}
+}
+
 func TestIsValidFirst(t *testing.T) {
 	tests := []struct {
 		name  string
@@ -180,3 +270,139 @@ func TestIsValidRange(t *testing.T) {
 		})
 	}
 }
+
+func TestIsValidFeeTier(t *testing.T) {
+	tests := []struct {
+		name    string
+		feeTier string
+		want    bool
+	}{
+		{name: "lowest tier", feeTier: "100", want: true},
+		{name: "highest tier", feeTier: "10000", want: true},
+		{name: "unknown tier", feeTier: "250", want: false},
+		{name: "non-numeric tier", feeTier: "abc", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidFeeTier(tt.feeTier); got != tt.want {
+				t.Errorf("IsValidFeeTier: for %v = %v, but want %v", tt.feeTier, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidAmount(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount string
+		want   bool
+	}{
+		{name: "integer", amount: "1000", want: true},
+		{name: "decimal", amount: "0.5", want: true},
+		{name: "negative", amount: "-1", want: false},
+		{name: "trailing dot", amount: "1.", want: false},
+		{name: "empty", amount: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidAmount(tt.amount); got != tt.want {
+				t.Errorf("IsValidAmount: for %v = %v, but want %v", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidLimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  bool
+	}{
+		{name: "within a single page", limit: 5, want: true},
+		{name: "beyond a single page", limit: 5000, want: true},
+		{name: "zero", limit: 0, want: false},
+		{name: "above the ceiling", limit: paging.MaxItems + 1, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidLimit(tt.limit); got != tt.want {
+				t.Errorf("IsValidLimit: for %v = %v, but want %v", tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidBlockRange(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want bool
+	}{
+		{name: "single block", from: "18319881", to: "18319881", want: true},
+		{name: "widest range", from: "18319000", to: "18319999", want: true},
+		{name: "range too wide", from: "18319000", to: "18320000", want: false},
+		{name: "from greater than to", from: "18319881", to: "18319880", want: false},
+		{name: "invalid from block", from: "abc", to: "18319881", want: false},
+		{name: "invalid to block", from: "18319881", to: "0", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidBlockRange(tt.from, tt.to); got != tt.want {
+				t.Errorf("IsValidBlockRange: for %v and %v = %v, but want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidTimeRange(t *testing.T) {
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want bool
+	}{
+		{name: "short range", from: strconv.FormatInt(now-600, 10), to: strconv.FormatInt(now-300, 10), want: true},
+		{name: "longest range", from: strconv.FormatInt(now-MaxTimeRange-100, 10), to: strconv.FormatInt(now-100, 10), want: true},
+		{name: "range too long", from: strconv.FormatInt(now-MaxTimeRange-101, 10), to: strconv.FormatInt(now-100, 10), want: false},
+		{name: "invalid range", from: strconv.FormatInt(now-300, 10), to: strconv.FormatInt(now-600, 10), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidTimeRange(tt.from, tt.to); got != tt.want {
+				t.Errorf("IsValidTimeRange: for %v and %v = %v, but want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidTimestamp(t *testing.T) {
+	now := time.Now().Unix()
+
+	tests := []struct {
+		name string
+		ts   string
+		want bool
+	}{
+		{name: "past timestamp", ts: "1697031100", want: true},
+		{name: "future timestamp", ts: strconv.FormatInt(now+600, 10), want: false},
+		{name: "zero", ts: "0", want: false},
+		{name: "negative", ts: "-1", want: false},
+		{name: "not a number", ts: "yesterday", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidTimestamp(tt.ts); got != tt.want {
+				t.Errorf("IsValidTimestamp: for %v = %v, but want %v", tt.ts, got, tt.want)
+			}
+		})
+	}
+}